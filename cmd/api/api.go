@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 
 	internalapi "github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
 // NewCmdAPI returns a cobra.Command that makes authenticated Bitbucket API requests.
@@ -32,7 +34,12 @@ The endpoint is relative to the Bitbucket API base URL
 Use --field to construct a JSON body from key=value pairs:
 
   bb api -X POST /repositories/workspace/repo/pullrequests \
-    -f title="My PR" -f source.branch.name=feature`,
+    -f title="My PR" -f source.branch.name=feature
+
+Use --jq or --template to extract values from the response:
+
+  bb api /user --jq .display_name
+  bb api /repositories/workspace --jq '.values[].full_name'`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			endpoint := args[0]
@@ -65,13 +72,13 @@ Use --field to construct a JSON body from key=value pairs:
 			var data []byte
 			switch strings.ToUpper(method) {
 			case "GET":
-				data, err = client.Get(endpoint)
+				data, err = client.GetContext(cmd.Context(), endpoint)
 			case "POST":
-				data, err = client.Post(endpoint, requestBody)
+				data, err = client.PostContext(cmd.Context(), endpoint, requestBody)
 			case "PUT":
-				data, err = client.Put(endpoint, requestBody)
+				data, err = client.PutContext(cmd.Context(), endpoint, requestBody)
 			case "DELETE":
-				data, err = client.Delete(endpoint)
+				data, err = client.DeleteContext(cmd.Context(), endpoint)
 			default:
 				return fmt.Errorf("unsupported HTTP method: %s (supported: GET, POST, PUT, DELETE)", method)
 			}
@@ -86,6 +93,9 @@ Use --field to construct a JSON body from key=value pairs:
 			// Pretty-print JSON if possible, otherwise print raw
 			var prettyJSON json.RawMessage
 			if err := json.Unmarshal(data, &prettyJSON); err == nil {
+				if output.Filtered() {
+					return output.Fprint(cmd.OutOrStdout(), prettyJSON)
+				}
 				formatted, err := json.MarshalIndent(prettyJSON, "", "  ")
 				if err == nil {
 					fmt.Fprintln(cmd.OutOrStdout(), string(formatted))
@@ -93,6 +103,9 @@ Use --field to construct a JSON body from key=value pairs:
 				}
 			}
 
+			if output.Filtered() {
+				return fmt.Errorf("--jq and --template require a JSON response")
+			}
 			fmt.Fprintln(cmd.OutOrStdout(), string(data))
 			return nil
 		},
@@ -102,6 +115,7 @@ Use --field to construct a JSON body from key=value pairs:
 	cmd.Flags().StringVarP(&body, "body", "b", "", "Request body (JSON string)")
 	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Additional headers in 'Key: Value' format (repeatable)")
 	cmd.Flags().StringArrayVarP(&fields, "field", "f", nil, "Body fields in key=value format, combined into a JSON object (repeatable)")
+	cmdutil.AddFilterFlags(cmd)
 
 	return cmd
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	authPkg "github.com/PhilipKram/bitbucket-cli/internal/auth"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/config"
 	"github.com/PhilipKram/bitbucket-cli/internal/errors"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
@@ -23,9 +24,16 @@ func NewCmdAuth() *cobra.Command {
 Available commands:
   login    Authenticate with Bitbucket (interactive or via flags)
   logout   Remove stored credentials
-  status   Show current authentication state
+  status   Show authentication state of all profiles
   token    Print the stored authentication token
-  refresh  Refresh an OAuth access token`,
+  refresh  Refresh an OAuth access token
+  list     List auth profiles
+  switch   Change the active auth profile
+  migrate  Move stored credentials to another credential store
+
+Each profile has its own OAuth consumer, token, default workspace and
+output format. Select a profile for a single command with --profile or
+BB_PROFILE, or persistently with 'bb auth switch'.`,
 	}
 
 	cmd.AddCommand(newCmdLogin())
@@ -33,6 +41,9 @@ Available commands:
 	cmd.AddCommand(newCmdStatus())
 	cmd.AddCommand(newCmdToken())
 	cmd.AddCommand(newCmdRefresh())
+	cmd.AddCommand(newCmdList())
+	cmd.AddCommand(newCmdSwitch())
+	cmd.AddCommand(newCmdMigrate())
 
 	return cmd
 }
@@ -42,6 +53,7 @@ func newCmdLogin() *cobra.Command {
 	var withToken bool
 	var clientID string
 	var clientSecret string
+	var store string
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log in to Bitbucket",
@@ -67,8 +79,24 @@ For non-interactive use, pass flags:
   bb auth login --web --client-id KEY --client-secret SECRET
 
   # OAuth with saved credentials (re-authenticate)
-  bb auth login --web`,
+  bb auth login --web
+
+  # Log in to an additional named profile
+  bb auth login --profile bot --web --client-id KEY --client-secret SECRET
+
+Credentials are stored in plaintext files by default. Use --store to keep
+them in the desktop keyring (Linux Secret Service) or in a file encrypted
+with a passphrase; existing credentials are migrated to the new store:
+
+  bb auth login --store keyring
+  BB_CREDENTIAL_PASSPHRASE=... bb auth login --store encrypted`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("store") {
+				if err := migrateStore(store); err != nil {
+					return err
+				}
+			}
+
 			// Non-interactive: --with-token reads access token from stdin
 			if withToken {
 				return loginWithToken()
@@ -88,6 +116,8 @@ For non-interactive use, pass flags:
 	cmd.Flags().BoolVar(&withToken, "with-token", false, "Read an OAuth access token from stdin")
 	cmd.Flags().StringVar(&clientID, "client-id", "", "OAuth consumer key")
 	cmd.Flags().StringVar(&clientSecret, "client-secret", "", "OAuth consumer secret")
+	cmd.Flags().StringVar(&store, "store", "", "Credential store: plaintext, keyring or encrypted")
+	cmd.RegisterFlagCompletionFunc("store", storeCompletion)
 	return cmd
 }
 
@@ -132,7 +162,7 @@ func loginWithToken() error {
 		return errors.Wrap(err, "Failed to save credentials")
 	}
 
-	output.PrintMessage("Logged in to Bitbucket.")
+	output.PrintMessage("Logged in to Bitbucket%s.", profileSuffix())
 	return nil
 }
 
@@ -182,7 +212,7 @@ func loginOAuthInteractive(reader *bufio.Reader, clientID, clientSecret string)
 		return errors.ConfigError(fmt.Sprintf("failed to save OAuth credentials: %v", err))
 	}
 
-	token, err := authPkg.Login(clientID, clientSecret)
+	token, err := authPkg.EndpointsFor(cfg).Login(clientID, clientSecret)
 	if err != nil {
 		return errors.Wrap(err, "Login failed")
 	}
@@ -192,7 +222,7 @@ func loginOAuthInteractive(reader *bufio.Reader, clientID, clientSecret string)
 	}
 
 	fmt.Println()
-	output.PrintMessage("Logged in to Bitbucket.")
+	output.PrintMessage("Logged in to Bitbucket%s.", profileSuffix())
 	return nil
 }
 
@@ -224,7 +254,7 @@ func loginWeb(clientID, clientSecret string) error {
 		return errors.ConfigError(fmt.Sprintf("failed to save OAuth credentials: %v", err))
 	}
 
-	token, err := authPkg.Login(clientID, clientSecret)
+	token, err := authPkg.EndpointsFor(cfg).Login(clientID, clientSecret)
 	if err != nil {
 		return errors.Wrap(err, "Login failed")
 	}
@@ -233,15 +263,33 @@ func loginWeb(clientID, clientSecret string) error {
 		return errors.ConfigError(fmt.Sprintf("failed to save token: %v", err))
 	}
 
-	output.PrintMessage("Logged in to Bitbucket.")
+	output.PrintMessage("Logged in to Bitbucket%s.", profileSuffix())
 	return nil
 }
 
 func newCmdLogout() *cobra.Command {
-	return &cobra.Command{
+	var deleteProfile bool
+
+	cmd := &cobra.Command{
 		Use:   "logout",
 		Short: "Log out and remove stored credentials",
+		Long: `Remove the stored token of the active profile.
+
+With --delete, a named profile is removed entirely, including its OAuth
+consumer and settings.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if deleteProfile {
+				name := config.ActiveProfile()
+				if name == config.DefaultProfile {
+					return errors.InvalidInput("profile", "the default profile cannot be deleted; select another profile with --profile")
+				}
+				if err := config.DeleteProfile(name); err != nil {
+					return err
+				}
+				output.PrintMessage("Deleted profile %q.", name)
+				return nil
+			}
+
 			_, err := config.LoadToken()
 			if err != nil {
 				output.PrintMessage("Already logged out.")
@@ -251,59 +299,101 @@ func newCmdLogout() *cobra.Command {
 			if err := config.ClearToken(); err != nil {
 				return err
 			}
-			output.PrintMessage("Logged out of Bitbucket.")
+			output.PrintMessage("Logged out of Bitbucket%s.", profileSuffix())
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&deleteProfile, "delete", false, "Delete the active named profile entirely")
+	return cmd
 }
 
 func newCmdStatus() *cobra.Command {
 	var showToken bool
-	var jsonOut bool
 
 	cmd := &cobra.Command{
 		Use:   "status",
-		Short: "Show current authentication status",
+		Short: "Show authentication status of all profiles",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.LoadToken()
-			if err != nil || token.AccessToken == "" {
+			statuses, err := loadProfileStatuses(true)
+			if err != nil {
+				return err
+			}
+
+			// Profiles that could not be read are reported rather than
+			// taken for logged out.
+			loggedIn := false
+			for _, st := range statuses {
+				loggedIn = loggedIn || st.LoggedIn || st.Err != nil
+			}
+			if !loggedIn {
 				return errors.Unauthorized("Not logged in")
 			}
 
-			if jsonOut {
-				data := map[string]string{
-					"auth_method": "oauth",
+			if !output.IsTable() {
+				var data []map[string]interface{}
+				for _, st := range statuses {
+					entry := map[string]interface{}{
+						"profile":   st.Name,
+						"active":    st.Active,
+						"logged_in": st.LoggedIn,
+						"host":      st.Host,
+						"store":     st.Store,
+					}
+					if st.Err != nil {
+						entry["error"] = st.Err.Error()
+					}
+					if st.LoggedIn {
+						entry["auth_method"] = "oauth"
+						if showToken {
+							entry["token"] = st.Token.AccessToken
+						} else {
+							entry["token"] = maskToken(st.Token.AccessToken)
+						}
+						if st.Token.Scopes != "" {
+							entry["scopes"] = st.Token.Scopes
+						}
+					}
+					data = append(data, entry)
 				}
-				if showToken {
-					data["token"] = token.AccessToken
-				} else {
-					data["token"] = maskToken(token.AccessToken)
-				}
-				if token.Scopes != "" {
-					data["scopes"] = token.Scopes
-				}
-				output.PrintJSON(data)
-				return nil
+				return output.Print(data)
 			}
 
-			fmt.Println("bitbucket.org")
-			fmt.Println("  Logged in to bitbucket.org via OAuth 2.0")
-			fmt.Println("    - Auth method: OAuth 2.0")
-			if token.Scopes != "" {
-				fmt.Printf("    - Token scopes: %s\n", token.Scopes)
-			}
+			for i, st := range statuses {
+				if i > 0 {
+					fmt.Println()
+				}
+				header := st.Name
+				if st.Active {
+					header += " (active)"
+				}
+				fmt.Println(header)
+				if st.Err != nil {
+					fmt.Printf("  Failed to read profile: %v\n", st.Err)
+					continue
+				}
+				if !st.LoggedIn {
+					fmt.Printf("  Not logged in to %s\n", st.Host)
+					continue
+				}
+				fmt.Printf("  Logged in to %s via OAuth 2.0\n", st.Host)
+				fmt.Println("    - Auth method: OAuth 2.0")
+				fmt.Printf("    - Credential store: %s\n", st.Store)
+				if st.Token.Scopes != "" {
+					fmt.Printf("    - Token scopes: %s\n", st.Token.Scopes)
+				}
 
-			if showToken {
-				fmt.Printf("    - Token: %s\n", token.AccessToken)
-			} else {
-				fmt.Printf("    - Token: %s\n", maskToken(token.AccessToken))
+				if showToken {
+					fmt.Printf("    - Token: %s\n", st.Token.AccessToken)
+				} else {
+					fmt.Printf("    - Token: %s\n", maskToken(st.Token.AccessToken))
+				}
 			}
 
 			return nil
 		},
 	}
 	cmd.Flags().BoolVarP(&showToken, "show-token", "t", false, "Display the token in plain text")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
@@ -355,7 +445,7 @@ func newCmdRefresh() *cobra.Command {
 				return errors.ConfigError("OAuth credentials not found. Run 'bb auth login' to re-authenticate")
 			}
 
-			newToken, err := authPkg.RefreshAccessToken(cfg.OAuthKey, cfg.OAuthSecret, token.RefreshToken)
+			newToken, err := authPkg.EndpointsFor(cfg).RefreshAccessToken(cfg.OAuthKey, cfg.OAuthSecret, token.RefreshToken)
 			if err != nil {
 				return errors.Wrap(err, "Token refresh failed")
 			}
@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
 )
 
 func TestMaskToken_Long(t *testing.T) {
@@ -46,6 +50,9 @@ func TestNewCmdAuth_HasSubcommands(t *testing.T) {
 		"status":  false,
 		"token":   false,
 		"refresh": false,
+		"list":    false,
+		"switch":  false,
+		"migrate": false,
 	}
 
 	for _, sub := range subcommands {
@@ -82,10 +89,62 @@ func TestNewCmdLogin_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find login command: %v", err)
 	}
 
-	expectedFlags := []string{"web", "with-token", "client-id", "client-secret"}
+	expectedFlags := []string{"web", "with-token", "client-id", "client-secret", "store"}
 	for _, name := range expectedFlags {
 		if loginCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on login command", name)
 		}
 	}
 }
+
+func TestLoadProfileStatuses_ReportsErrorsPerProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv(config.EnvPassphrase, "")
+	config.ResetConfigDirCache()
+	t.Cleanup(config.ResetConfigDirCache)
+	config.SetPassphrasePrompt(func(string) (string, error) {
+		t.Error("asked for a passphrase")
+		return "", os.ErrPermission
+	})
+	t.Cleanup(func() { config.SetPassphrasePrompt(nil) })
+
+	dir, err := config.ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles := map[string]string{
+		"broken": `{`,
+		"locked": `{"credential_store": "encrypted"}`,
+	}
+	for name, cfg := range profiles {
+		path := filepath.Join(dir, "profiles", name)
+		if err := os.MkdirAll(path, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "config.json"), []byte(cfg), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "profiles", "locked", "credentials.enc"), []byte(`{"version":1,"keys":["token"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := loadProfileStatuses(false)
+	if err != nil {
+		t.Fatalf("loadProfileStatuses() error: %v", err)
+	}
+	got := map[string]profileStatus{}
+	for _, st := range statuses {
+		got[st.Name] = st
+	}
+	if st := got["broken"]; st.Err == nil {
+		t.Error("expected an error for the broken profile")
+	}
+	if st := got["locked"]; st.Err != nil || !st.LoggedIn {
+		t.Errorf("locked profile: logged in = %v, error = %v, want logged in", st.LoggedIn, st.Err)
+	}
+	if st := got[config.DefaultProfile]; st.Err != nil || st.LoggedIn {
+		t.Errorf("default profile: logged in = %v, error = %v, want logged out", st.LoggedIn, st.Err)
+	}
+}
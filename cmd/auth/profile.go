@@ -0,0 +1,183 @@
+package auth
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// profileStatus summarizes the saved state of one auth profile.
+type profileStatus struct {
+	Name      string
+	Active    bool
+	LoggedIn  bool
+	Host      string
+	Workspace string
+	Store     string
+	Token     *config.TokenData
+	// Err is why the profile could not be read, if it could not.
+	Err error
+}
+
+// loadProfileStatuses returns the status of every saved profile. With
+// withToken it loads each logged in profile's token, which may unlock its
+// credential store; otherwise only whether there is a token is checked. A
+// profile that cannot be read is reported in its status' Err.
+func loadProfileStatuses(withToken bool) ([]profileStatus, error) {
+	names, err := config.ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	active := config.ActiveProfile()
+
+	var statuses []profileStatus
+	for _, name := range names {
+		st := profileStatus{Name: name, Active: name == active, Host: config.DefaultGitHost}
+		st.Err = loadProfileStatus(&st, withToken)
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func loadProfileStatus(st *profileStatus, withToken bool) error {
+	cfg, err := config.LoadProfileMetadata(st.Name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile: %w", err)
+	}
+	st.Host = hostLabel(cfg)
+	st.Workspace = cfg.DefaultWorkspace
+	st.Store = cfg.CredentialStore
+	if st.Store == "" {
+		st.Store = config.StorePlaintext
+	}
+
+	if !withToken {
+		st.LoggedIn, err = config.HasProfileToken(st.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read the %s credential store: %w", st.Store, err)
+		}
+		return nil
+	}
+	token, err := config.LoadProfileToken(st.Name)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to load token from the %s credential store: %w", st.Store, err)
+	}
+	if token.AccessToken != "" {
+		st.LoggedIn = true
+		st.Token = token
+	}
+	return nil
+}
+
+// hostLabel returns the host a profile talks to, for display.
+func hostLabel(cfg *config.Config) string {
+	u, err := url.Parse(cfg.APIBaseURL())
+	if err != nil || u.Host == "" || u.Host == "api."+config.DefaultGitHost {
+		return config.DefaultGitHost
+	}
+	return u.Host
+}
+
+// profileSuffix describes the active profile for status messages. It is
+// empty for the default profile.
+func profileSuffix() string {
+	if p := config.ActiveProfile(); p != config.DefaultProfile {
+		return fmt.Sprintf(" (profile %q)", p)
+	}
+	return ""
+}
+
+func newCmdList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List auth profiles",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := loadProfileStatuses(false)
+			if err != nil {
+				return err
+			}
+
+			var data []map[string]interface{}
+			table := output.NewTable("PROFILE", "ACTIVE", "HOST", "STATUS", "WORKSPACE")
+			for _, st := range statuses {
+				entry := map[string]interface{}{
+					"profile":           st.Name,
+					"active":            st.Active,
+					"logged_in":         st.LoggedIn,
+					"host":              st.Host,
+					"default_workspace": st.Workspace,
+				}
+
+				marker := ""
+				if st.Active {
+					marker = "*"
+				}
+				status := "logged out"
+				switch {
+				case st.Err != nil:
+					entry["error"] = st.Err.Error()
+					status = "error: " + st.Err.Error()
+				case st.LoggedIn:
+					status = "logged in"
+				}
+				data = append(data, entry)
+				table.AddRow(st.Name, marker, st.Host, status, st.Workspace)
+			}
+			return output.Render(data, table)
+		},
+	}
+	cmdutil.AddJSONFlag(cmd)
+	return cmd
+}
+
+func newCmdSwitch() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch <profile>",
+		Short: "Change the active auth profile",
+		Long: `Make the named profile the active one for future commands.
+
+BB_PROFILE and the --profile flag still take precedence for a single
+shell or command.`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names, _ := config.ListProfiles()
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := config.ValidateProfileName(name); err != nil {
+				return errors.InvalidInput("profile", err.Error())
+			}
+			if err := config.SwitchProfile(name); err != nil {
+				return &errors.BBError{
+					Message:    err.Error(),
+					Suggestion: fmt.Sprintf("Run 'bb auth list' to see available profiles, or 'bb auth login --profile %s' to create it.", name),
+				}
+			}
+
+			output.PrintMessage("Switched to profile %q.", name)
+			if env := os.Getenv(config.EnvProfile); env != "" && env != name {
+				output.PrintMessage("Note: %s=%s overrides the active profile in this shell.", config.EnvProfile, env)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+func storeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return config.StoreKinds(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// migrateStore moves the active profile's credentials to the given store.
+func migrateStore(kind string) error {
+	if !slices.Contains(config.StoreKinds(), kind) {
+		return errors.InvalidInput("store", fmt.Sprintf("must be one of %s", strings.Join(config.StoreKinds(), ", ")))
+	}
+
+	profile := config.ActiveProfile()
+	current, err := config.ProfileCredentialStore(profile)
+	if err != nil {
+		return errors.ConfigError(fmt.Sprintf("failed to open the current credential store: %v", err))
+	}
+	if current.Name() == kind {
+		return nil
+	}
+
+	if kind == config.StoreEncrypted && os.Getenv(config.EnvPassphrase) == "" {
+		// A typo in a brand new passphrase would lock the user out, so ask twice.
+		pass, err := cmdutil.PromptSecret("New credential store passphrase: ")
+		if err != nil {
+			return &errors.BBError{
+				Message:    err.Error(),
+				Suggestion: fmt.Sprintf("Set %s to provide the passphrase non-interactively.", config.EnvPassphrase),
+			}
+		}
+		confirm, err := cmdutil.PromptSecret("Confirm passphrase: ")
+		if err != nil {
+			return err
+		}
+		if pass == "" || pass != confirm {
+			return errors.InvalidInput("passphrase", "passphrases are empty or do not match")
+		}
+		config.SetPassphrasePrompt(func(string) (string, error) { return pass, nil })
+	}
+
+	if err := config.MigrateCredentials(profile, kind); err != nil {
+		return &errors.BBError{
+			Message: fmt.Sprintf("failed to move credentials to the %s store: %v", kind, err),
+			Err:     err,
+		}
+	}
+	output.PrintMessage("Credentials moved from the %s store to the %s store%s.", current.Name(), kind, profileSuffix())
+	return nil
+}
+
+func newCmdMigrate() *cobra.Command {
+	var store string
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move stored credentials to another credential store",
+		Long: `Move the active profile's token and OAuth consumer secret to another
+credential store, without logging in again.
+
+Stores:
+  plaintext  token.json and config.json in the config directory (default)
+  keyring    the desktop keyring via the Secret Service API (Linux, needs secret-tool)
+  encrypted  credentials.enc, sealed with a passphrase; set BB_CREDENTIAL_PASSPHRASE
+             to unlock it non-interactively`,
+		Example: `  bb auth migrate --store keyring
+  bb auth migrate --store encrypted --profile work`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if store == "" {
+				return errors.InvalidInput("store", "--store is required")
+			}
+			current, err := config.ProfileCredentialStore(config.ActiveProfile())
+			if err == nil && current.Name() == store {
+				output.PrintMessage("Credentials are already in the %s store%s.", store, profileSuffix())
+				return nil
+			}
+			return migrateStore(store)
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "", "Target credential store: plaintext, keyring or encrypted")
+	cmd.RegisterFlagCompletionFunc("store", storeCompletion)
+	return cmd
+}
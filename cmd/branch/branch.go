@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/errors"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
@@ -65,41 +66,30 @@ func NewCmdBranch() *cobra.Command {
 	cmd.AddCommand(newCmdTagDelete())
 	cmd.AddCommand(newCmdRestrictions())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 func newCmdList() *cobra.Command {
-	var jsonOut bool
-	var page int
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List branches",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=25&page=%d", args[0], page)
-			data, err := client.Get(path)
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return errors.Wrap(err, "Failed to parse API response")
-			}
-
-			var branches []Branch
-			if err := json.Unmarshal(paginated.Values, &branches); err != nil {
-				return errors.Wrap(err, "Failed to parse branches data")
-			}
-
-			if jsonOut {
-				output.PrintJSON(branches)
-				return nil
+			path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=25&page=%d", args[0], pagination.Page)
+			branches, raw, _, err := cmdutil.FetchListWithRaw[Branch](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
 			}
 
 			table := output.NewTable("NAME", "HASH", "AUTHOR", "DATE", "MESSAGE")
@@ -116,13 +106,13 @@ func newCmdList() *cobra.Command {
 					output.Truncate(b.Target.Message, 40),
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(branches, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
-	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -130,10 +120,14 @@ func newCmdCreate() *cobra.Command {
 	var target string
 
 	cmd := &cobra.Command{
-		Use:   "create <workspace/repo-slug> <branch-name>",
+		Use:   "create [workspace/repo-slug] <branch-name>",
 		Short: "Create a branch",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			if target == "" {
 				return errors.InvalidInput("target", "target commit hash is required")
 			}
@@ -150,7 +144,7 @@ func newCmdCreate() *cobra.Command {
 			}
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/refs/branches", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -166,21 +160,26 @@ func newCmdCreate() *cobra.Command {
 	cmd.Flags().StringVarP(&target, "target", "t", "", "Target commit hash (required)")
 	cmd.MarkFlagRequired("target")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug> <branch-name>",
+		Use:   "delete [workspace/repo-slug] <branch-name>",
 		Short: "Delete a branch",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/refs/branches/%s", args[0], url.PathEscape(args[1]))
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -197,40 +196,30 @@ func newCmdDelete() *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdTags() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "tags <workspace/repo-slug>",
+		Use:   "tags [workspace/repo-slug]",
 		Short: "List tags",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/refs/tags?pagelen=25", args[0])
-			data, err := client.Get(path)
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return errors.Wrap(err, "Failed to parse API response")
-			}
-
-			var tags []Tag
-			if err := json.Unmarshal(paginated.Values, &tags); err != nil {
-				return errors.Wrap(err, "Failed to parse tags data")
-			}
-
-			if jsonOut {
-				output.PrintJSON(tags)
-				return nil
+			path := fmt.Sprintf("/repositories/%s/refs/tags?pagelen=25&page=%d", args[0], pagination.Page)
+			tags, raw, _, err := cmdutil.FetchListWithRaw[Tag](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
 			}
 
 			table := output.NewTable("NAME", "HASH", "DATE", "MESSAGE")
@@ -241,12 +230,13 @@ func newCmdTags() *cobra.Command {
 				}
 				table.AddRow(t.Name, t.Target.Hash[:12], date, output.Truncate(t.Message, 50))
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(tags, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -255,10 +245,14 @@ func newCmdTagCreate() *cobra.Command {
 	var message string
 
 	cmd := &cobra.Command{
-		Use:   "tag-create <workspace/repo-slug> <tag-name>",
+		Use:   "tag-create [workspace/repo-slug] <tag-name>",
 		Short: "Create a tag",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			if target == "" {
 				return errors.InvalidInput("target", "target commit hash is required")
 			}
@@ -278,7 +272,7 @@ func newCmdTagCreate() *cobra.Command {
 			}
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/refs/tags", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -295,21 +289,26 @@ func newCmdTagCreate() *cobra.Command {
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Tag message")
 	cmd.MarkFlagRequired("target")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdTagDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "tag-delete <workspace/repo-slug> <tag-name>",
+		Use:   "tag-delete [workspace/repo-slug] <tag-name>",
 		Short: "Delete a tag",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/refs/tags/%s", args[0], url.PathEscape(args[1]))
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -318,23 +317,26 @@ func newCmdTagDelete() *cobra.Command {
 		},
 		ValidArgsFunction: completion.RepositoryNamesWithDescriptions,
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdRestrictions() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "restrictions <workspace/repo-slug>",
+		Use:   "restrictions [workspace/repo-slug]",
 		Short: "List branch restrictions",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/branch-restrictions?pagelen=50", args[0])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -349,20 +351,15 @@ func newCmdRestrictions() *cobra.Command {
 				return errors.Wrap(err, "Failed to parse restrictions data")
 			}
 
-			if jsonOut {
-				output.PrintJSON(restrictions)
-				return nil
-			}
-
 			table := output.NewTable("ID", "KIND", "PATTERN")
 			for _, r := range restrictions {
 				table.AddRow(fmt.Sprintf("%d", r.ID), r.Kind, r.Pattern)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(restrictions, paginated.Values), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
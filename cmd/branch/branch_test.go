@@ -39,7 +39,7 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	expectedFlags := []string{"json", "page"}
+	expectedFlags := []string{"json", "page", "all", "limit"}
 	for _, name := range expectedFlags {
 		if listCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on list command", name)
@@ -82,7 +82,7 @@ func TestNewCmdTags_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find tags command: %v", err)
 	}
 
-	expectedFlags := []string{"json"}
+	expectedFlags := []string{"json", "page", "all", "limit"}
 	for _, name := range expectedFlags {
 		if tagsCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on tags command", name)
@@ -127,7 +127,7 @@ func TestNewCmdDelete_Args(t *testing.T) {
 		t.Fatalf("failed to find delete command: %v", err)
 	}
 
-	if deleteCmd.Use != "delete <workspace/repo-slug> <branch-name>" {
+	if deleteCmd.Use != "delete [workspace/repo-slug] <branch-name>" {
 		t.Errorf("delete command Use = %q, expected to include workspace/repo-slug and branch-name", deleteCmd.Use)
 	}
 }
@@ -139,7 +139,7 @@ func TestNewCmdTagDelete_Args(t *testing.T) {
 		t.Fatalf("failed to find tag-delete command: %v", err)
 	}
 
-	if tagDeleteCmd.Use != "tag-delete <workspace/repo-slug> <tag-name>" {
+	if tagDeleteCmd.Use != "tag-delete [workspace/repo-slug] <tag-name>" {
 		t.Errorf("tag-delete command Use = %q, expected to include workspace/repo-slug and tag-name", tagDeleteCmd.Use)
 	}
 }
@@ -161,7 +161,7 @@ func TestNewCmdList_UseShort(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	if listCmd.Use != "list <workspace/repo-slug>" {
+	if listCmd.Use != "list [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", listCmd.Use)
 	}
 	if listCmd.Short != "List branches" {
@@ -176,7 +176,7 @@ func TestNewCmdCreate_UseShort(t *testing.T) {
 		t.Fatalf("failed to find create command: %v", err)
 	}
 
-	if createCmd.Use != "create <workspace/repo-slug> <branch-name>" {
+	if createCmd.Use != "create [workspace/repo-slug] <branch-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and branch-name, got %q", createCmd.Use)
 	}
 	if createCmd.Short != "Create a branch" {
@@ -203,7 +203,7 @@ func TestNewCmdTags_UseShort(t *testing.T) {
 		t.Fatalf("failed to find tags command: %v", err)
 	}
 
-	if tagsCmd.Use != "tags <workspace/repo-slug>" {
+	if tagsCmd.Use != "tags [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", tagsCmd.Use)
 	}
 	if tagsCmd.Short != "List tags" {
@@ -218,7 +218,7 @@ func TestNewCmdTagCreate_UseShort(t *testing.T) {
 		t.Fatalf("failed to find tag-create command: %v", err)
 	}
 
-	if tagCreateCmd.Use != "tag-create <workspace/repo-slug> <tag-name>" {
+	if tagCreateCmd.Use != "tag-create [workspace/repo-slug] <tag-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and tag-name, got %q", tagCreateCmd.Use)
 	}
 	if tagCreateCmd.Short != "Create a tag" {
@@ -245,7 +245,7 @@ func TestNewCmdRestrictions_UseShort(t *testing.T) {
 		t.Fatalf("failed to find restrictions command: %v", err)
 	}
 
-	if restrictionsCmd.Use != "restrictions <workspace/repo-slug>" {
+	if restrictionsCmd.Use != "restrictions [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", restrictionsCmd.Use)
 	}
 	if restrictionsCmd.Short != "List branch restrictions" {
@@ -506,7 +506,7 @@ func TestNewCmdList_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdList returned nil")
 	}
-	if cmd.Use != "list <workspace/repo-slug>" {
+	if cmd.Use != "list [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", cmd.Use)
 	}
 }
@@ -516,7 +516,7 @@ func TestNewCmdCreate_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdCreate returned nil")
 	}
-	if cmd.Use != "create <workspace/repo-slug> <branch-name>" {
+	if cmd.Use != "create [workspace/repo-slug] <branch-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and branch-name, got %q", cmd.Use)
 	}
 }
@@ -526,7 +526,7 @@ func TestNewCmdDelete_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdDelete returned nil")
 	}
-	if cmd.Use != "delete <workspace/repo-slug> <branch-name>" {
+	if cmd.Use != "delete [workspace/repo-slug] <branch-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and branch-name, got %q", cmd.Use)
 	}
 }
@@ -536,7 +536,7 @@ func TestNewCmdTags_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdTags returned nil")
 	}
-	if cmd.Use != "tags <workspace/repo-slug>" {
+	if cmd.Use != "tags [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", cmd.Use)
 	}
 }
@@ -546,7 +546,7 @@ func TestNewCmdTagCreate_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdTagCreate returned nil")
 	}
-	if cmd.Use != "tag-create <workspace/repo-slug> <tag-name>" {
+	if cmd.Use != "tag-create [workspace/repo-slug] <tag-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and tag-name, got %q", cmd.Use)
 	}
 }
@@ -556,7 +556,7 @@ func TestNewCmdTagDelete_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdTagDelete returned nil")
 	}
-	if cmd.Use != "tag-delete <workspace/repo-slug> <tag-name>" {
+	if cmd.Use != "tag-delete [workspace/repo-slug] <tag-name>" {
 		t.Errorf("expected Use to include workspace/repo-slug and tag-name, got %q", cmd.Use)
 	}
 }
@@ -566,7 +566,7 @@ func TestNewCmdRestrictions_DirectCall(t *testing.T) {
 	if cmd == nil {
 		t.Fatal("newCmdRestrictions returned nil")
 	}
-	if cmd.Use != "restrictions <workspace/repo-slug>" {
+	if cmd.Use != "restrictions [workspace/repo-slug]" {
 		t.Errorf("expected Use to include workspace/repo-slug, got %q", cmd.Use)
 	}
 }
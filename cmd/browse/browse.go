@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/browser"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
 	"github.com/PhilipKram/bitbucket-cli/internal/errors"
 )
 
@@ -20,15 +22,19 @@ func NewCmdBrowse() *cobra.Command {
 	var printOnly bool
 
 	cmd := &cobra.Command{
-		Use:   "browse <workspace/repo-slug>",
+		Use:   "browse [workspace/repo-slug]",
 		Short: "Open a Bitbucket repository page in the browser",
 		Long: `Open a Bitbucket repository page in the default web browser.
 
 By default, opens the repository's main page. Use flags to open
 specific pages such as pull requests, pipelines, issues, settings,
 or branches.`,
-		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			repo := args[0]
 
 			// Validate workspace/repo format
@@ -37,7 +43,8 @@ or branches.`,
 				return errors.InvalidInput("repository", "expected format: workspace/repo-slug")
 			}
 
-			baseURL := fmt.Sprintf("https://bitbucket.org/%s", repo)
+			cfg, _ := config.LoadConfig()
+			baseURL := fmt.Sprintf("%s/%s", cfg.WebURL(), repo)
 
 			// Count how many target flags are set to enforce mutual exclusivity
 			flagCount := 0
@@ -98,5 +105,7 @@ or branches.`,
 	cmd.Flags().BoolVar(&branches, "branches", false, "Open the branches page")
 	cmd.Flags().BoolVar(&printOnly, "print", false, "Print the URL instead of opening it")
 
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
@@ -18,8 +18,8 @@ func TestNewCmdBrowse_HasExpectedFlags(t *testing.T) {
 
 func TestNewCmdBrowse_UseAndShort(t *testing.T) {
 	cmd := NewCmdBrowse()
-	if cmd.Use != "browse <workspace/repo-slug>" {
-		t.Errorf("expected Use to be %q, got %q", "browse <workspace/repo-slug>", cmd.Use)
+	if cmd.Use != "browse [workspace/repo-slug]" {
+		t.Errorf("expected Use to be %q, got %q", "browse [workspace/repo-slug]", cmd.Use)
 	}
 	if cmd.Short == "" {
 		t.Error("expected Short to be non-empty")
@@ -67,6 +67,25 @@ func TestNewCmdBrowse_PrintFlag_DefaultURL(t *testing.T) {
 	}
 }
 
+func TestNewCmdBrowse_PrintFlag_ConfiguredHost(t *testing.T) {
+	t.Setenv("BB_AUTH_URL", "https://bitbucket.example.com/site/oauth2/authorize")
+	cmd := NewCmdBrowse()
+	cmd.SetArgs([]string{"myworkspace/myrepo", "--print"})
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "https://bitbucket.example.com/myworkspace/myrepo"
+	if got := buf.String(); got != expected+"\n" {
+		t.Errorf("expected output %q, got %q", expected, got)
+	}
+}
+
 func TestNewCmdBrowse_PrintFlag_PR(t *testing.T) {
 	cmd := NewCmdBrowse()
 	cmd.SetArgs([]string{"myworkspace/myrepo", "--print", "--pr", "42"})
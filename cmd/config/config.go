@@ -2,10 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -18,12 +22,14 @@ func NewCmdConfig() *cobra.Command {
 	cmd.AddCommand(newCmdView())
 	cmd.AddCommand(newCmdSetDefaultWorkspace())
 	cmd.AddCommand(newCmdSetFormat())
+	cmd.AddCommand(newCmdSetEndpoints())
+	cmd.AddCommand(newCmdSetRemote())
 
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "view",
 		Short: "Show current configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -32,9 +38,40 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
+			if !output.IsTable() {
+				_, tokenErr := config.LoadToken()
+				dir, _ := config.ConfigDir()
+				return output.Print(struct {
+					DefaultWorkspace string   `json:"default_workspace"`
+					DefaultFormat    string   `json:"default_format"`
+					APIURL           string   `json:"api_url"`
+					AuthURL          string   `json:"auth_url"`
+					TokenURL         string   `json:"token_url"`
+					GitHosts         []string `json:"git_hosts"`
+					GitRemote        string   `json:"git_remote"`
+					Authenticated    bool     `json:"authenticated"`
+					ConfigDir        string   `json:"config_dir"`
+				}{
+					DefaultWorkspace: cfg.DefaultWorkspace,
+					DefaultFormat:    valueOrDefault(cfg.DefaultFormat, "table"),
+					APIURL:           cfg.APIBaseURL(),
+					AuthURL:          cfg.OAuthAuthorizeURL(),
+					TokenURL:         cfg.OAuthTokenURL(),
+					GitHosts:         cfg.GitHostnames(),
+					GitRemote:        cfg.GitRemoteName(),
+					Authenticated:    tokenErr == nil,
+					ConfigDir:        dir,
+				})
+			}
+
 			output.PrintMessage("Default Workspace: %s", valueOrDefault(cfg.DefaultWorkspace, "(not set)"))
 			output.PrintMessage("Default Format:    %s", valueOrDefault(cfg.DefaultFormat, "table"))
 			output.PrintMessage("OAuth Key:         %s", maskValue(cfg.OAuthKey))
+			output.PrintMessage("API URL:           %s", cfg.APIBaseURL())
+			output.PrintMessage("OAuth Auth URL:    %s", cfg.OAuthAuthorizeURL())
+			output.PrintMessage("OAuth Token URL:   %s", cfg.OAuthTokenURL())
+			output.PrintMessage("Git Hosts:         %s", strings.Join(cfg.GitHostnames(), ", "))
+			output.PrintMessage("Git Remote:        %s", cfg.GitRemoteName())
 
 			// Show current auth method
 			_, tokenErr := config.LoadToken()
@@ -51,6 +88,8 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
+	cmdutil.AddJSONFlag(cmd)
+	return cmd
 }
 
 func newCmdSetDefaultWorkspace() *cobra.Command {
@@ -76,13 +115,18 @@ func newCmdSetDefaultWorkspace() *cobra.Command {
 func newCmdSetFormat() *cobra.Command {
 	return &cobra.Command{
 		Use:   "set-format <format>",
-		Short: "Set default output format (table, json)",
-		Args:  cobra.ExactArgs(1),
+		Short: "Set default output format (table, json, yaml, csv, tsv, ndjson)",
+		Long: `Set the output format used when neither --format nor --json is given.
+
+Supported formats: table, json, yaml, csv, tsv, ndjson.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: output.Formats(),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			format := args[0]
-			if format != "table" && format != "json" {
-				return fmt.Errorf("invalid format '%s': must be 'table' or 'json'", format)
+			parsed, err := output.ParseFormat(args[0])
+			if err != nil {
+				return err
 			}
+			format := string(parsed)
 			cfg, err := config.LoadConfig()
 			if err != nil {
 				return err
@@ -97,6 +141,114 @@ func newCmdSetFormat() *cobra.Command {
 	}
 }
 
+func newCmdSetRemote() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-remote <remote-name>",
+		Short: "Set the git remote used to detect the current repository",
+		Long: `Set the git remote whose URL identifies the repository for commands run
+without a <workspace/repo-slug> argument or --repo. Defaults to "origin";
+BB_GIT_REMOTE overrides this setting.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote := strings.TrimSpace(args[0])
+			if remote == "" || strings.ContainsAny(remote, " \t") {
+				return errors.InvalidInput("remote name", fmt.Sprintf("%q", args[0]))
+			}
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			cfg.GitRemote = remote
+			if err := config.SaveConfig(cfg); err != nil {
+				return err
+			}
+			output.PrintMessage("Git remote set to '%s'.", remote)
+			return nil
+		},
+	}
+}
+
+func newCmdSetEndpoints() *cobra.Command {
+	var apiURL, authURL, tokenURL string
+	var gitHosts []string
+	var reset bool
+
+	cmd := &cobra.Command{
+		Use:   "set-endpoints",
+		Short: "Set API, OAuth and git host overrides",
+		Long: `Point the CLI at a Bitbucket-compatible endpoint such as a corporate
+gateway or a local mock server.
+
+The BB_API_URL, BB_AUTH_URL, BB_TOKEN_URL and BB_GIT_HOSTS environment
+variables take precedence over these settings. Remotes on bitbucket.org are
+always recognized in addition to any --git-host values.`,
+		Example: `  bb config set-endpoints --api-url https://bb-gateway.example.com/2.0
+  bb config set-endpoints --api-url http://localhost:8080/2.0 --git-host localhost:8080
+  bb config set-endpoints --reset`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			if reset {
+				cfg.APIURL, cfg.AuthURL, cfg.TokenURL, cfg.GitHosts = "", "", "", nil
+			}
+			for flag, target := range map[string]*string{
+				"api-url":   &cfg.APIURL,
+				"auth-url":  &cfg.AuthURL,
+				"token-url": &cfg.TokenURL,
+			} {
+				if !cmd.Flags().Changed(flag) {
+					continue
+				}
+				value, _ := cmd.Flags().GetString(flag)
+				if value != "" {
+					if err := validateEndpointURL(value); err != nil {
+						return fmt.Errorf("invalid --%s: %w", flag, err)
+					}
+				}
+				*target = value
+			}
+			if cmd.Flags().Changed("git-host") {
+				cfg.GitHosts = gitHosts
+			}
+
+			if !reset && cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("specify at least one of --api-url, --auth-url, --token-url, --git-host or --reset")
+			}
+
+			if err := config.SaveConfig(cfg); err != nil {
+				return err
+			}
+			output.PrintMessage("API URL:         %s", cfg.APIBaseURL())
+			output.PrintMessage("OAuth Auth URL:  %s", cfg.OAuthAuthorizeURL())
+			output.PrintMessage("OAuth Token URL: %s", cfg.OAuthTokenURL())
+			output.PrintMessage("Git Hosts:       %s", strings.Join(cfg.GitHostnames(), ", "))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&apiURL, "api-url", "", "Base URL of the Bitbucket 2.0 API")
+	cmd.Flags().StringVar(&authURL, "auth-url", "", "OAuth 2.0 authorization URL")
+	cmd.Flags().StringVar(&tokenURL, "token-url", "", "OAuth 2.0 token URL")
+	cmd.Flags().StringSliceVar(&gitHosts, "git-host", nil, "Additional git hostname recognized as Bitbucket (repeatable)")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Clear all endpoint overrides before applying other flags")
+	return cmd
+}
+
+// validateEndpointURL checks that raw is an absolute http(s) URL.
+func validateEndpointURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http or https URL")
+	}
+	return nil
+}
+
 func valueOrDefault(val, def string) string {
 	if val == "" {
 		return def
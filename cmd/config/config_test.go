@@ -68,6 +68,8 @@ func TestNewCmdConfig_HasSubcommands(t *testing.T) {
 		"view":                  false,
 		"set-default-workspace": false,
 		"set-format":            false,
+		"set-endpoints":         false,
+		"set-remote":            false,
 	}
 
 	for _, sub := range subcommands {
@@ -106,3 +108,22 @@ func TestNewCmdSetFormat_RequiresArg(t *testing.T) {
 		t.Error("set-format command should require arguments")
 	}
 }
+
+func TestValidateEndpointURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://api.bitbucket.org/2.0", false},
+		{"http://localhost:8080/2.0", false},
+		{"api.bitbucket.org/2.0", true},
+		{"ftp://example.com", true},
+		{"https://", true},
+	}
+	for _, tt := range tests {
+		err := validateEndpointURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateEndpointURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
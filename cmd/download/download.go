@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -38,6 +39,7 @@ func NewCmdDownload() *cobra.Command {
 	cmd.AddCommand(newCmdGet())
 	cmd.AddCommand(newCmdDelete())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
@@ -50,47 +52,36 @@ func parseRepoArg(arg string) (string, string, error) {
 }
 
 func newCmdList() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List repository downloads",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ws, repo, err := parseRepoArg(args[0])
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			client, err := api.NewClient()
+			ws, repo, err := parseRepoArg(args[0])
 			if err != nil {
 				return err
 			}
 
-			path := fmt.Sprintf("/repositories/%s/%s/downloads?pagelen=25",
-				url.PathEscape(ws), url.PathEscape(repo))
-
-			data, err := client.Get(path)
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
+			path := fmt.Sprintf("/repositories/%s/%s/downloads?pagelen=25&page=%d",
+				url.PathEscape(ws), url.PathEscape(repo), pagination.Page)
 
-			var downloads []Download
-			if err := json.Unmarshal(paginated.Values, &downloads); err != nil {
+			downloads, raw, _, err := cmdutil.FetchListWithRaw[Download](cmd.Context(), client, path, &pagination)
+			if err != nil {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(downloads)
-				return nil
-			}
-
-			if len(downloads) == 0 {
+			if output.IsTable() && len(downloads) == 0 {
 				output.PrintMessage("No downloads found.")
 				return nil
 			}
@@ -103,11 +94,12 @@ func newCmdList() *cobra.Command {
 				}
 				table.AddRow(d.Name, formatSize(d.Size), fmt.Sprintf("%d", d.Downloads), created)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(downloads, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -115,10 +107,14 @@ func newCmdUpload() *cobra.Command {
 	var filePath string
 
 	cmd := &cobra.Command{
-		Use:   "upload <workspace/repo-slug>",
+		Use:   "upload [workspace/repo-slug]",
 		Short: "Upload a file to repository downloads",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			ws, repo, err := parseRepoArg(args[0])
 			if err != nil {
 				return err
@@ -143,7 +139,7 @@ func newCmdUpload() *cobra.Command {
 				url.PathEscape(ws), url.PathEscape(repo))
 
 			fileName := filepath.Base(filePath)
-			_, err = client.PostMultipart(path, "files", fileName, f)
+			_, err = client.PostMultipartContext(cmd.Context(), path, "files", fileName, f)
 			if err != nil {
 				return err
 			}
@@ -154,6 +150,7 @@ func newCmdUpload() *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to the file to upload (required)")
 	cmd.MarkFlagRequired("file")
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -161,10 +158,14 @@ func newCmdGet() *cobra.Command {
 	var outputPath string
 
 	cmd := &cobra.Command{
-		Use:   "get <workspace/repo-slug> <filename>",
+		Use:   "get [workspace/repo-slug] <filename>",
 		Short: "Download a file from repository downloads",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			ws, repo, err := parseRepoArg(args[0])
 			if err != nil {
 				return err
@@ -180,7 +181,7 @@ func newCmdGet() *cobra.Command {
 			path := fmt.Sprintf("/repositories/%s/%s/downloads?pagelen=100",
 				url.PathEscape(ws), url.PathEscape(repo))
 
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -214,7 +215,7 @@ func newCmdGet() *cobra.Command {
 					break
 				}
 
-				nextData, err := client.GetRaw(paginated.Next)
+				nextData, err := client.GetRawContext(cmd.Context(), paginated.Next)
 				if err != nil {
 					return err
 				}
@@ -225,7 +226,7 @@ func newCmdGet() *cobra.Command {
 				return fmt.Errorf("file %q not found in downloads", filename)
 			}
 
-			fileData, err := client.GetRaw(found.Links.Self.Href)
+			fileData, err := client.GetRawContext(cmd.Context(), found.Links.Self.Href)
 			if err != nil {
 				return err
 			}
@@ -244,15 +245,20 @@ func newCmdGet() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path (defaults to filename)")
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug> <filename>",
+		Use:   "delete [workspace/repo-slug] <filename>",
 		Short: "Delete a file from repository downloads",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			ws, repo, err := parseRepoArg(args[0])
 			if err != nil {
 				return err
@@ -267,7 +273,7 @@ func newCmdDelete() *cobra.Command {
 			path := fmt.Sprintf("/repositories/%s/%s/downloads/%s",
 				url.PathEscape(ws), url.PathEscape(repo), url.PathEscape(filename))
 
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -276,6 +282,7 @@ func newCmdDelete() *cobra.Command {
 			return nil
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
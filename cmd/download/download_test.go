@@ -54,9 +54,10 @@ func TestListFlags(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	jsonFlag := listCmd.Flags().Lookup("json")
-	if jsonFlag == nil {
-		t.Error("expected --json flag on list subcommand")
+	for _, name := range []string{"json", "page", "all", "limit"} {
+		if listCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag on list subcommand", name)
+		}
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -41,43 +42,33 @@ func NewCmdEnvironment() *cobra.Command {
 	cmd.AddCommand(newCmdCreate())
 	cmd.AddCommand(newCmdDelete())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 func newCmdList() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List deployment environments",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			path := fmt.Sprintf("/repositories/%s/environments?pagelen=25", args[0])
-			data, err := client.Get(path)
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var environments []Environment
-			if err := json.Unmarshal(paginated.Values, &environments); err != nil {
+			path := fmt.Sprintf("/repositories/%s/environments?pagelen=25&page=%d", args[0], pagination.Page)
+			environments, raw, _, err := cmdutil.FetchListWithRaw[Environment](cmd.Context(), client, path, &pagination)
+			if err != nil {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(environments)
-				return nil
-			}
-
 			table := output.NewTable("UUID", "NAME", "TYPE", "CATEGORY", "RANK", "LOCK")
 			for _, e := range environments {
 				lock := ""
@@ -93,29 +84,32 @@ func newCmdList() *cobra.Command {
 					lock,
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(environments, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "view <workspace/repo-slug> <env-uuid>",
+		Use:   "view [workspace/repo-slug] <env-uuid>",
 		Short: "View environment details",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
 			path := fmt.Sprintf("/repositories/%s/environments/%s", args[0], args[1])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -125,9 +119,8 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(env)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(env, json.RawMessage(data)))
 			}
 
 			lock := "none"
@@ -150,7 +143,8 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -159,10 +153,14 @@ func newCmdCreate() *cobra.Command {
 	var envType string
 
 	cmd := &cobra.Command{
-		Use:   "create <workspace/repo-slug>",
+		Use:   "create [workspace/repo-slug]",
 		Short: "Create a deployment environment",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -177,7 +175,7 @@ func newCmdCreate() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/environments", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -194,22 +192,27 @@ func newCmdCreate() *cobra.Command {
 	cmd.Flags().StringVarP(&envType, "type", "t", "", "Environment type: Test, Staging, or Production (required)")
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("type")
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug> <env-uuid>",
+		Use:   "delete [workspace/repo-slug] <env-uuid>",
 		Short: "Delete a deployment environment",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
 			path := fmt.Sprintf("/repositories/%s/environments/%s", args[0], args[1])
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -217,5 +220,6 @@ func newCmdDelete() *cobra.Command {
 			return nil
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
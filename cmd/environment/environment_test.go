@@ -56,8 +56,10 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	if listCmd.Flags().Lookup("json") == nil {
-		t.Error("expected flag --json not found on list command")
+	for _, name := range []string{"json", "page", "all", "limit"} {
+		if listCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s not found on list command", name)
+		}
 	}
 }
 
@@ -48,6 +48,18 @@ type Issue struct {
 	} `json:"links"`
 }
 
+// Comment is a comment on an issue.
+type Comment struct {
+	ID      int `json:"id"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User struct {
+		DisplayName string `json:"display_name"`
+	} `json:"user"`
+	CreatedOn string `json:"created_on"`
+}
+
 func NewCmdIssue() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "issue",
@@ -64,48 +76,36 @@ func NewCmdIssue() *cobra.Command {
 	cmd.AddCommand(newCmdVote())
 	cmd.AddCommand(newCmdWatch())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 func newCmdList() *cobra.Command {
 	var state string
-	var page int
-	var jsonOut bool
-
+	var pagination cmdutil.PaginationOptions
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List issues",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/issues?pagelen=25&page=%d", args[0], page)
+			path := fmt.Sprintf("/repositories/%s/issues?pagelen=25&page=%d", args[0], pagination.Page)
 			if state != "" {
 				path += fmt.Sprintf("&q=state%%3D%%22%s%%22", url.QueryEscape(state))
 			}
 
-			data, err := client.Get(path)
+			issues, raw, _, err := cmdutil.FetchListWithRaw[Issue](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return errors.Wrap(err, "Failed to parse API response")
-			}
-
-			var issues []Issue
-			if err := json.Unmarshal(paginated.Values, &issues); err != nil {
-				return errors.Wrap(err, "Failed to parse issues data")
-			}
-
-			if jsonOut {
-				output.PrintJSON(issues)
-				return nil
-			}
-
 			table := output.NewTable("ID", "TITLE", "STATE", "PRIORITY", "KIND", "ASSIGNEE")
 			for _, i := range issues {
 				assignee := "–"
@@ -121,31 +121,33 @@ func newCmdList() *cobra.Command {
 					assignee,
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(issues, raw), table)
 		},
 	}
 	cmd.Flags().StringVarP(&state, "state", "s", "", "Filter by state (new, open, resolved, on hold, invalid, duplicate, wontfix, closed)")
-	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "view <workspace/repo-slug> <issue-id>",
+		Use:   "view [workspace/repo-slug] <issue-id>",
 		Short: "View issue details",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/issues/%s", args[0], args[1])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -155,9 +157,8 @@ func newCmdView() *cobra.Command {
 				return errors.Wrap(err, "Failed to parse issue data")
 			}
 
-			if jsonOut {
-				output.PrintJSON(issue)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(issue, json.RawMessage(data)))
 			}
 
 			assignee := "–"
@@ -189,8 +190,9 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -201,10 +203,14 @@ func newCmdCreate() *cobra.Command {
 	var priority string
 
 	cmd := &cobra.Command{
-		Use:   "create <workspace/repo-slug>",
+		Use:   "create [workspace/repo-slug]",
 		Short: "Create an issue",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			if title == "" {
 				return errors.InvalidInput("title", "title is required")
 			}
@@ -222,7 +228,7 @@ func newCmdCreate() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/issues", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -241,6 +247,7 @@ func newCmdCreate() *cobra.Command {
 	cmd.Flags().StringVar(&priority, "priority", "major", "Priority (trivial, minor, major, critical, blocker)")
 	cmd.MarkFlagRequired("title")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -251,10 +258,14 @@ func newCmdEdit() *cobra.Command {
 	var kind string
 
 	cmd := &cobra.Command{
-		Use:   "edit <workspace/repo-slug> <issue-id>",
+		Use:   "edit [workspace/repo-slug] <issue-id>",
 		Short: "Edit an issue",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -275,7 +286,7 @@ func newCmdEdit() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/issues/%s", args[0], args[1])
-			_, err = client.Put(path, string(jsonBody))
+			_, err = client.PutContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -288,21 +299,26 @@ func newCmdEdit() *cobra.Command {
 	cmd.Flags().StringVar(&priority, "priority", "", "New priority")
 	cmd.Flags().StringVarP(&kind, "kind", "k", "", "New kind")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug> <issue-id>",
+		Use:   "delete [workspace/repo-slug] <issue-id>",
 		Short: "Delete an issue",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/issues/%s", args[0], args[1])
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -311,49 +327,34 @@ func newCmdDelete() *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdComments() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "comments <workspace/repo-slug> <issue-id>",
+		Use:   "comments [workspace/repo-slug] <issue-id>",
 		Short: "List issue comments",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/issues/%s/comments?pagelen=50", args[0], args[1])
-			data, err := client.Get(path)
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return errors.Wrap(err, "Failed to parse API response")
-			}
-
-			var comments []struct {
-				ID      int `json:"id"`
-				Content struct {
-					Raw string `json:"raw"`
-				} `json:"content"`
-				User struct {
-					DisplayName string `json:"display_name"`
-				} `json:"user"`
-				CreatedOn string `json:"created_on"`
-			}
-			if err := json.Unmarshal(paginated.Values, &comments); err != nil {
-				return errors.Wrap(err, "Failed to parse comments data")
+			path := fmt.Sprintf("/repositories/%s/issues/%s/comments?pagelen=50&page=%d", args[0], args[1], pagination.Page)
+			comments, raw, _, err := cmdutil.FetchListWithRaw[Comment](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(comments)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(comments, raw))
 			}
 
 			for _, c := range comments {
@@ -363,8 +364,10 @@ func newCmdComments() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -374,10 +377,14 @@ func newCmdComment() *cobra.Command {
 	var useEditor bool
 
 	cmd := &cobra.Command{
-		Use:   "comment <workspace/repo-slug> <issue-id>",
+		Use:   "comment [workspace/repo-slug] <issue-id>",
 		Short: "Add a comment to an issue",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			resolvedBody, err := cmdutil.ResolveBody(
 				body, bodyFile, useEditor,
 				cmd.Flags().Changed("body"),
@@ -401,7 +408,7 @@ func newCmdComment() *cobra.Command {
 			}
 			jsonBody, _ := json.Marshal(reqBody)
 			path := fmt.Sprintf("/repositories/%s/issues/%s/comments", args[0], args[1])
-			_, err = client.Post(path, string(jsonBody))
+			_, err = client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -413,21 +420,26 @@ func newCmdComment() *cobra.Command {
 	cmd.Flags().StringVarP(&bodyFile, "body-file", "F", "", "Read body from file (use - for stdin)")
 	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Open editor to compose comment")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdVote() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "vote <workspace/repo-slug> <issue-id>",
+		Use:   "vote [workspace/repo-slug] <issue-id>",
 		Short: "Vote on an issue",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/issues/%s/vote", args[0], args[1])
-			_, err = client.Put(path, "")
+			_, err = client.PutContext(cmd.Context(), path, "")
 			if err != nil {
 				return err
 			}
@@ -436,21 +448,26 @@ func newCmdVote() *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdWatch() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "watch <workspace/repo-slug> <issue-id>",
+		Use:   "watch [workspace/repo-slug] <issue-id>",
 		Short: "Watch an issue",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/issues/%s/watch", args[0], args[1])
-			_, err = client.Put(path, "")
+			_, err = client.PutContext(cmd.Context(), path, "")
 			if err != nil {
 				return err
 			}
@@ -459,5 +476,6 @@ func newCmdWatch() *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
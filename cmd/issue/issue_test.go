@@ -135,7 +135,7 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	expectedFlags := []string{"state", "page", "json"}
+	expectedFlags := []string{"state", "page", "all", "limit", "json"}
 	for _, name := range expectedFlags {
 		if listCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on list command", name)
@@ -214,7 +214,7 @@ func TestNewCmdComments_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find comments command: %v", err)
 	}
 
-	expectedFlags := []string{"json"}
+	expectedFlags := []string{"json", "page", "all", "limit"}
 	for _, name := range expectedFlags {
 		if commentsCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on comments command", name)
@@ -89,6 +89,7 @@ type mcpSessionStore struct {
 	clientSecret        string // Bitbucket OAuth consumer secret
 	callbackURL         string // OAuth callback URL (Bitbucket redirects here after user authorizes)
 	resourceMetadataURL string // Absolute URL of the protected-resource metadata document (RFC 9728)
+	endpoints           authPkg.Endpoints // Bitbucket OAuth endpoints
 }
 
 func newSessionStore(bbClientID, bbClientSecret, persistPath, callbackURL string) *mcpSessionStore {
@@ -101,6 +102,7 @@ func newSessionStore(bbClientID, bbClientSecret, persistPath, callbackURL string
 		clientSecret: bbClientSecret,
 		path:         persistPath,
 		callbackURL:  callbackURL,
+		endpoints:    authPkg.EndpointsFor(nil),
 	}
 	s.loadFromDisk()
 	return s
@@ -269,7 +271,7 @@ func (s *mcpSessionStore) sessionBearerAuth(next http.Handler) http.Handler {
 		needsRefresh := sess.RefreshToken != "" && (sess.TokenExpiresAt == 0 || time.Now().Unix() >= sess.TokenExpiresAt-tokenRefreshBuffer)
 		if needsRefresh {
 			fmt.Fprintf(os.Stderr, "Refreshing Bitbucket token for session...\n")
-			newToken, err := authPkg.RefreshAccessToken(s.clientID, s.clientSecret, sess.RefreshToken)
+			newToken, err := s.endpoints.RefreshAccessToken(s.clientID, s.clientSecret, sess.RefreshToken)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Token refresh failed: %v\n", err)
 				// If we know the token is expired (not just unknown), reject the request
@@ -450,12 +452,7 @@ func oauthAuthorizeHandler(store *mcpSessionStore) http.HandlerFunc {
 		})
 
 		// Redirect to Bitbucket's authorization page
-		bbAuthURL := fmt.Sprintf("%s?client_id=%s&response_type=code&redirect_uri=%s&state=%s",
-			config.AuthURL,
-			url.QueryEscape(store.clientID),
-			url.QueryEscape(store.callbackURL),
-			url.QueryEscape(bbState),
-		)
+		bbAuthURL := store.endpoints.AuthorizeURL(store.clientID, store.callbackURL, bbState)
 
 		http.Redirect(w, r, bbAuthURL, http.StatusFound)
 	}
@@ -492,7 +489,7 @@ func oauthBitbucketCallbackHandler(store *mcpSessionStore) http.HandlerFunc {
 		}
 
 		// Exchange code with Bitbucket using the same callback URI
-		bbToken, err := authPkg.ExchangeCodeServerSide(store.clientID, store.clientSecret, code, store.callbackURL)
+		bbToken, err := store.endpoints.ExchangeCodeServerSide(store.clientID, store.clientSecret, code, store.callbackURL)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Token exchange failed: %v", err), http.StatusBadGateway)
 			return
@@ -660,7 +657,7 @@ func handleRefreshTokenGrant(w http.ResponseWriter, r *http.Request, store *mcpS
 		return
 	}
 
-	newBBToken, err := authPkg.RefreshAccessToken(store.clientID, store.clientSecret, sess.RefreshToken)
+	newBBToken, err := store.endpoints.RefreshAccessToken(store.clientID, store.clientSecret, sess.RefreshToken)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Bitbucket token refresh failed during MCP refresh: %v\n", err)
 		jsonError(w, "invalid_grant", "Bitbucket token refresh failed — re-authorization required", http.StatusBadRequest)
@@ -734,6 +731,9 @@ func serveHTTPOAuth(host string, port int, basePath, bbClientID, bbClientSecret,
 	}
 
 	store := newSessionStore(bbClientID, bbClientSecret, sessionsPath, "")
+	if cfg, err := config.LoadConfig(); err == nil {
+		store.endpoints = authPkg.EndpointsFor(cfg)
+	}
 
 	// Per-user server factory: each request gets a server with the user's Bitbucket token
 	handler := mcpPkg.NewHTTPHandler(func(r *http.Request) *mcpPkg.Server {
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	authPkg "github.com/PhilipKram/bitbucket-cli/internal/auth"
 	"github.com/PhilipKram/bitbucket-cli/internal/config"
 	mcpPkg "github.com/PhilipKram/bitbucket-cli/internal/mcp"
 )
@@ -376,6 +377,27 @@ func TestOAuthAuthorizeHandler_RedirectsToBitbucket(t *testing.T) {
 	}
 }
 
+func TestOAuthAuthorizeHandler_UsesConfiguredEndpoints(t *testing.T) {
+	store := newSessionStore("bb-consumer-key", "bb-secret", "", "http://localhost:8817/callback")
+	store.endpoints = authPkg.EndpointsFor(&config.Config{AuthURL: "https://bitbucket.example.com/site/oauth2/authorize"})
+	store.putClient(&oauthRegisteredClient{
+		ClientID:     "test-client-id",
+		ClientName:   "test",
+		RedirectURIs: []string{"http://localhost:9999/callback"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/oauth/authorize?client_id=test-client-id&redirect_uri=http://localhost:9999/callback&state=mystate&response_type=code",
+		nil)
+	w := httptest.NewRecorder()
+	oauthAuthorizeHandler(store)(w, req)
+
+	location := w.Result().Header.Get("Location")
+	if !strings.HasPrefix(location, "https://bitbucket.example.com/site/oauth2/authorize?") {
+		t.Errorf("Expected redirect to the configured authorize URL, got %s", location)
+	}
+}
+
 func TestOAuthAuthorizeHandler_MissingParams(t *testing.T) {
 	store := newSessionStore("key", "secret", "", "")
 	handler := oauthAuthorizeHandler(store)
@@ -1,12 +1,11 @@
 package pipeline
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -78,43 +77,31 @@ func NewCmdPipeline() *cobra.Command {
 	cmd.AddCommand(newCmdTrends())
 	cmd.AddCommand(newCmdSlowest())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 func newCmdList() *cobra.Command {
-	var page int
-	var jsonOut bool
-
+	var pagination cmdutil.PaginationOptions
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List pipelines",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
-			if err != nil {
-				return err
-			}
-			path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=20&page=%d&sort=-created_on", args[0], page)
-			data, err := client.Get(path)
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
+			client, err := api.NewClient()
+			if err != nil {
 				return err
 			}
-
-			var pipelines []Pipeline
-			if err := json.Unmarshal(paginated.Values, &pipelines); err != nil {
+			path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=20&page=%d&sort=-created_on", args[0], pagination.Page)
+			pipelines, raw, _, err := cmdutil.FetchListWithRaw[Pipeline](cmd.Context(), client, path, &pagination)
+			if err != nil {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(pipelines)
-				return nil
-			}
-
 			table := output.NewTable("BUILD#", "STATE", "RESULT", "BRANCH", "CREATOR", "CREATED", "DURATION")
 			for _, p := range pipelines {
 				state := p.State.Name
@@ -140,30 +127,32 @@ func newCmdList() *cobra.Command {
 					duration,
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(pipelines, raw), table)
 		},
 	}
-	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "view <workspace/repo-slug> <pipeline-uuid>",
+		Use:   "view [workspace/repo-slug] <pipeline-uuid>",
 		Short: "View pipeline details",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pipelines/%s", args[0], cmdutil.NormalizeUUID(args[1]))
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -173,9 +162,8 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(p)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(p, json.RawMessage(data)))
 			}
 
 			result := "–"
@@ -196,8 +184,9 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -209,10 +198,19 @@ func newCmdTrigger() *cobra.Command {
 	var interval int
 
 	cmd := &cobra.Command{
-		Use:   "trigger <workspace/repo-slug>",
+		Use:   "trigger [workspace/repo-slug]",
 		Short: "Trigger a new pipeline",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if watch {
+				if err := cmdutil.ValidateInterval(interval); err != nil {
+					return err
+				}
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -236,7 +234,7 @@ func newCmdTrigger() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/pipelines/", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -250,7 +248,7 @@ func newCmdTrigger() *cobra.Command {
 			// If watch flag is set, start watching the pipeline
 			if watch {
 				output.PrintMessage("Watching pipeline...")
-				return watchPipeline(client, args[0], p.UUID, interval, false)
+				return watchPipeline(cmd.Context(), client, args[0], p.UUID, interval, false)
 			}
 
 			return nil
@@ -262,21 +260,26 @@ func newCmdTrigger() *cobra.Command {
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch pipeline after triggering")
 	cmd.Flags().IntVarP(&interval, "interval", "i", 5, "Polling interval in seconds (when watching)")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdStop() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "stop <workspace/repo-slug> <pipeline-uuid>",
+		Use:   "stop [workspace/repo-slug] <pipeline-uuid>",
 		Short: "Stop a running pipeline",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pipelines/%s/stopPipeline", args[0], cmdutil.NormalizeUUID(args[1]))
-			_, err = client.Post(path, "")
+			_, err = client.PostContext(cmd.Context(), path, "")
 			if err != nil {
 				return err
 			}
@@ -285,23 +288,26 @@ func newCmdStop() *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdSteps() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "steps <workspace/repo-slug> <pipeline-uuid>",
+		Use:   "steps [workspace/repo-slug] <pipeline-uuid>",
 		Short: "List steps for a pipeline",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pipelines/%s/steps/", args[0], cmdutil.NormalizeUUID(args[1]))
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -316,11 +322,6 @@ func newCmdSteps() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(steps)
-				return nil
-			}
-
 			table := output.NewTable("UUID", "NAME", "STATE", "RESULT", "DURATION")
 			for _, s := range steps {
 				result := "–"
@@ -333,28 +334,32 @@ func newCmdSteps() *cobra.Command {
 				}
 				table.AddRow(s.UUID[:12], s.Name, s.State.Name, result, duration)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(steps, paginated.Values), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdLog() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "log <workspace/repo-slug> <pipeline-uuid> <step-uuid>",
+		Use:   "log [workspace/repo-slug] <pipeline-uuid> <step-uuid>",
 		Short: "View logs for a pipeline step",
-		Args:  cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pipelines/%s/steps/%s/log",
 				args[0], cmdutil.NormalizeUUID(args[1]), cmdutil.NormalizeUUID(args[2]))
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -363,55 +368,52 @@ func newCmdLog() *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 2)
 	return cmd
 }
 
-// watchPipeline polls a pipeline and displays its status in real-time
-func watchPipeline(client *api.Client, repo, pipelineUUID string, interval int, jsonOut bool) error {
-	// Set up signal handling for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	defer signal.Stop(sigChan)
-
-	// Poll the pipeline status
-	ticker := time.NewTicker(time.Duration(interval) * time.Second)
-	defer ticker.Stop()
-
-	for {
+// watchPipeline polls a pipeline and displays its status in real-time.
+// Polling stops, and any in-flight request is aborted, when ctx is cancelled
+// or the process receives an interrupt.
+func watchPipeline(ctx context.Context, client *api.Client, repo, pipelineUUID string, interval int, structured bool) error {
+	var p Pipeline
+	err := cmdutil.Watch(ctx, time.Duration(interval)*time.Second, func(ctx context.Context) (bool, error) {
 		// Fetch pipeline details
 		path := fmt.Sprintf("/repositories/%s/pipelines/%s", repo, url.PathEscape(pipelineUUID))
-		data, err := client.Get(path)
+		data, err := client.GetContext(ctx, path)
 		if err != nil {
-			return err
+			return false, err
 		}
 
-		var p Pipeline
+		p = Pipeline{}
 		if err := json.Unmarshal(data, &p); err != nil {
-			return err
+			return false, err
 		}
 
 		// Fetch pipeline steps
 		stepsPath := fmt.Sprintf("/repositories/%s/pipelines/%s/steps/", repo, url.PathEscape(pipelineUUID))
-		stepsData, err := client.Get(stepsPath)
+		stepsData, err := client.GetContext(ctx, stepsPath)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		var stepsPaginated api.PaginatedResponse
 		if err := json.Unmarshal(stepsData, &stepsPaginated); err != nil {
-			return err
+			return false, err
 		}
 
 		var steps []PipelineStep
 		if err := json.Unmarshal(stepsPaginated.Values, &steps); err != nil {
-			return err
+			return false, err
 		}
 
-		if jsonOut {
-			output.PrintJSON(map[string]interface{}{
+		if structured {
+			if err := output.Print(map[string]interface{}{
 				"pipeline": p,
 				"steps":    steps,
-			})
+			}); err != nil {
+				return false, err
+			}
 		} else {
 			// Clear screen for clean display
 			output.ClearScreen()
@@ -480,42 +482,48 @@ func watchPipeline(client *api.Client, repo, pipelineUUID string, interval int,
 			}
 		}
 
-		// Check if pipeline is in a terminal state
-		if p.State.Name == "COMPLETED" {
-			if p.State.Result != nil {
-				if p.State.Result.Name == "SUCCESSFUL" {
-					output.PrintMessage("\nPipeline completed successfully")
-					os.Exit(0)
-				} else {
-					output.PrintMessage("\nPipeline failed: %s", p.State.Result.Name)
-					os.Exit(1)
-				}
-			}
-			output.PrintMessage("\nPipeline completed")
-			os.Exit(0)
-		}
+		// Stop once the pipeline is in a terminal state
+		return p.State.Name == "COMPLETED", nil
+	})
+	if err == cmdutil.ErrWatchInterrupted {
+		return watchInterrupted()
+	}
+	if err != nil {
+		return err
+	}
 
-		// Wait for next poll or signal interrupt
-		select {
-		case <-ticker.C:
-			// Continue to next iteration
-		case <-sigChan:
-			output.PrintMessage("\nWatch interrupted. Exiting gracefully...")
-			return nil
-		}
+	switch {
+	case p.State.Result == nil:
+		output.PrintMessage("\nPipeline completed")
+	case p.State.Result.Name == "SUCCESSFUL":
+		output.PrintMessage("\nPipeline completed successfully")
+	default:
+		output.PrintMessage("\nPipeline failed: %s", p.State.Result.Name)
+		os.Exit(1)
 	}
+	return nil
+}
+
+func watchInterrupted() error {
+	output.PrintMessage("\nWatch interrupted. Exiting gracefully...")
+	return nil
 }
 
 func newCmdWatch() *cobra.Command {
 	var buildNumber int
 	var interval int
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "watch <workspace/repo-slug>",
+		Use:   "watch [workspace/repo-slug]",
 		Short: "Watch pipeline status in real-time",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := cmdutil.ValidateInterval(interval); err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -527,7 +535,7 @@ func newCmdWatch() *cobra.Command {
 			// If buildNumber is 0, get the latest pipeline
 			if buildNumber == 0 {
 				path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=1&sort=-created_on", repo)
-				data, err := client.Get(path)
+				data, err := client.GetContext(cmd.Context(), path)
 				if err != nil {
 					return err
 				}
@@ -550,7 +558,7 @@ func newCmdWatch() *cobra.Command {
 			} else {
 				// Get pipeline by build number
 				path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=100&sort=-created_on", repo)
-				data, err := client.Get(path)
+				data, err := client.GetContext(cmd.Context(), path)
 				if err != nil {
 					return err
 				}
@@ -579,12 +587,13 @@ func newCmdWatch() *cobra.Command {
 				}
 			}
 
-			return watchPipeline(client, repo, pipelineUUID, interval, jsonOut)
+			return watchPipeline(cmd.Context(), client, repo, pipelineUUID, interval, !output.IsTable())
 		},
 	}
 	cmd.Flags().IntVarP(&buildNumber, "build", "b", 0, "Build number to watch (0 = latest)")
 	cmd.Flags().IntVarP(&interval, "interval", "i", 5, "Polling interval in seconds")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
@@ -16,13 +17,15 @@ import (
 func newCmdSlowest() *cobra.Command {
 	var pipelineUUID string
 	var limit int
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "slowest <workspace/repo-slug>",
+		Use:   "slowest [workspace/repo-slug]",
 		Short: "Show slowest pipeline steps",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -33,7 +36,7 @@ func newCmdSlowest() *cobra.Command {
 			// If no pipeline UUID specified, get the latest pipeline
 			if pipelineUUID == "" {
 				path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=1&sort=-created_on", repo)
-				data, err := client.Get(path)
+				data, err := client.GetContext(cmd.Context(), path)
 				if err != nil {
 					return err
 				}
@@ -57,7 +60,7 @@ func newCmdSlowest() *cobra.Command {
 			// Fetch steps
 			stepsPath := fmt.Sprintf("/repositories/%s/pipelines/%s/steps/",
 				repo, url.PathEscape(pipelineUUID))
-			data, err := client.Get(stepsPath)
+			data, err := client.GetContext(cmd.Context(), stepsPath)
 			if err != nil {
 				return err
 			}
@@ -82,11 +85,6 @@ func newCmdSlowest() *cobra.Command {
 				steps = steps[:limit]
 			}
 
-			if jsonOut {
-				output.PrintJSON(steps)
-				return nil
-			}
-
 			table := output.NewTable("STEP", "DURATION", "STATUS")
 			for _, s := range steps {
 				duration := "–"
@@ -99,13 +97,13 @@ func newCmdSlowest() *cobra.Command {
 				}
 				table.AddRow(s.Name, duration, status)
 			}
-			table.Print()
-			return nil
+			return output.Render(steps, table)
 		},
 	}
 	cmd.Flags().StringVar(&pipelineUUID, "pipeline", "", "Pipeline UUID (default: latest)")
 	cmd.Flags().IntVar(&limit, "limit", 10, "Number of steps to show")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
@@ -17,13 +19,15 @@ import (
 func newCmdStats() *cobra.Command {
 	var days int
 	var branch string
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "stats <workspace/repo-slug>",
+		Use:   "stats [workspace/repo-slug]",
 		Short: "Show pipeline statistics",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -32,7 +36,7 @@ func newCmdStats() *cobra.Command {
 			repo := args[0]
 			cutoff := time.Now().UTC().AddDate(0, 0, -days)
 
-			pipelines, err := fetchPipelinesInWindow(client, repo, branch, cutoff)
+			pipelines, err := fetchPipelinesInWindow(cmd.Context(), client, repo, branch, cutoff)
 			if err != nil {
 				return err
 			}
@@ -59,8 +63,8 @@ func newCmdStats() *cobra.Command {
 				failureRate = float64(failed) / float64(total) * 100
 			}
 
-			if jsonOut {
-				output.PrintJSON(map[string]interface{}{
+			if !output.IsTable() {
+				return output.Print(map[string]interface{}{
 					"total":        total,
 					"successful":   success,
 					"failed":       failed,
@@ -69,7 +73,6 @@ func newCmdStats() *cobra.Command {
 					"failure_rate": failureRate,
 					"days":         days,
 				})
-				return nil
 			}
 
 			output.PrintMessage("Pipeline Statistics (last %d days)", days)
@@ -85,14 +88,15 @@ func newCmdStats() *cobra.Command {
 	}
 	cmd.Flags().IntVar(&days, "days", 30, "Number of days to look back")
 	cmd.Flags().StringVar(&branch, "branch", "", "Filter by branch name")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 // fetchPipelinesInWindow fetches all pipelines within the given time window,
 // optionally filtered by branch.
-func fetchPipelinesInWindow(client *api.Client, repo, branch string, cutoff time.Time) ([]Pipeline, error) {
+func fetchPipelinesInWindow(ctx context.Context, client *api.Client, repo, branch string, cutoff time.Time) ([]Pipeline, error) {
 	var all []Pipeline
 	page := 1
 
@@ -106,7 +110,7 @@ func fetchPipelinesInWindow(client *api.Client, repo, branch string, cutoff time
 	for {
 		path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=100&page=%d&sort=-created_on",
 			escapedRepo, page)
-		data, err := client.Get(path)
+		data, err := client.GetContext(ctx, path)
 		if err != nil {
 			return nil, err
 		}
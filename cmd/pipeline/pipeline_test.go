@@ -58,7 +58,7 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	expectedFlags := []string{"page", "json"}
+	expectedFlags := []string{"page", "all", "limit", "json"}
 	for _, name := range expectedFlags {
 		if listCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on list command", name)
@@ -75,17 +75,17 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdList_RequiresExactlyOneArg(t *testing.T) {
+func TestNewCmdList_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	listCmd, _, err := cmd.Find([]string{"list"})
 	if err != nil {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	// Test with no args - should fail validation
+	// Test with no args - the repository is detected from the git remote
 	err = listCmd.Args(listCmd, []string{})
-	if err == nil {
-		t.Error("expected error when no args provided, got nil")
+	if err != nil {
+		t.Errorf("expected no error with 0 args, got %v", err)
 	}
 
 	// Test with correct number of args - should pass validation
@@ -113,7 +113,7 @@ func TestNewCmdView_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdView_RequiresExactlyTwoArgs(t *testing.T) {
+func TestNewCmdView_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	viewCmd, _, err := cmd.Find([]string{"view"})
 	if err != nil {
@@ -126,10 +126,16 @@ func TestNewCmdView_RequiresExactlyTwoArgs(t *testing.T) {
 		t.Errorf("expected no error with 2 args, got %v", err)
 	}
 
+	// Test without the repository - it is detected from the git remote
+	err = viewCmd.Args(viewCmd, []string{"pipeline-uuid"})
+	if err != nil {
+		t.Errorf("expected no error with 1 arg, got %v", err)
+	}
+
 	// Test with wrong number of args - should fail validation
-	err = viewCmd.Args(viewCmd, []string{"workspace/repo"})
+	err = viewCmd.Args(viewCmd, []string{})
 	if err == nil {
-		t.Error("expected error when only 1 arg provided, got nil")
+		t.Error("expected error when no args provided, got nil")
 	}
 }
 
@@ -162,7 +168,7 @@ func TestNewCmdTrigger_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdTrigger_RequiresExactlyOneArg(t *testing.T) {
+func TestNewCmdTrigger_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	triggerCmd, _, err := cmd.Find([]string{"trigger"})
 	if err != nil {
@@ -175,14 +181,20 @@ func TestNewCmdTrigger_RequiresExactlyOneArg(t *testing.T) {
 		t.Errorf("expected no error with 1 arg, got %v", err)
 	}
 
-	// Test with wrong number of args - should fail validation
+	// Test without the repository - it is detected from the git remote
 	err = triggerCmd.Args(triggerCmd, []string{})
+	if err != nil {
+		t.Errorf("expected no error with 0 args, got %v", err)
+	}
+
+	// Test with wrong number of args - should fail validation
+	err = triggerCmd.Args(triggerCmd, []string{"workspace/repo", "extra"})
 	if err == nil {
-		t.Error("expected error when no args provided, got nil")
+		t.Error("expected error when too many args provided, got nil")
 	}
 }
 
-func TestNewCmdStop_RequiresExactlyTwoArgs(t *testing.T) {
+func TestNewCmdStop_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	stopCmd, _, err := cmd.Find([]string{"stop"})
 	if err != nil {
@@ -195,10 +207,16 @@ func TestNewCmdStop_RequiresExactlyTwoArgs(t *testing.T) {
 		t.Errorf("expected no error with 2 args, got %v", err)
 	}
 
+	// Test without the repository - it is detected from the git remote
+	err = stopCmd.Args(stopCmd, []string{"pipeline-uuid"})
+	if err != nil {
+		t.Errorf("expected no error with 1 arg, got %v", err)
+	}
+
 	// Test with wrong number of args - should fail validation
-	err = stopCmd.Args(stopCmd, []string{"workspace/repo"})
+	err = stopCmd.Args(stopCmd, []string{})
 	if err == nil {
-		t.Error("expected error when only 1 arg provided, got nil")
+		t.Error("expected error when no args provided, got nil")
 	}
 }
 
@@ -214,7 +232,7 @@ func TestNewCmdSteps_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdSteps_RequiresExactlyTwoArgs(t *testing.T) {
+func TestNewCmdSteps_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	stepsCmd, _, err := cmd.Find([]string{"steps"})
 	if err != nil {
@@ -227,14 +245,20 @@ func TestNewCmdSteps_RequiresExactlyTwoArgs(t *testing.T) {
 		t.Errorf("expected no error with 2 args, got %v", err)
 	}
 
+	// Test without the repository - it is detected from the git remote
+	err = stepsCmd.Args(stepsCmd, []string{"pipeline-uuid"})
+	if err != nil {
+		t.Errorf("expected no error with 1 arg, got %v", err)
+	}
+
 	// Test with wrong number of args - should fail validation
-	err = stepsCmd.Args(stepsCmd, []string{"workspace/repo"})
+	err = stepsCmd.Args(stepsCmd, []string{})
 	if err == nil {
-		t.Error("expected error when only 1 arg provided, got nil")
+		t.Error("expected error when no args provided, got nil")
 	}
 }
 
-func TestNewCmdLog_RequiresExactlyThreeArgs(t *testing.T) {
+func TestNewCmdLog_RepoArgOptional(t *testing.T) {
 	cmd := NewCmdPipeline()
 	logCmd, _, err := cmd.Find([]string{"log"})
 	if err != nil {
@@ -247,13 +271,14 @@ func TestNewCmdLog_RequiresExactlyThreeArgs(t *testing.T) {
 		t.Errorf("expected no error with 3 args, got %v", err)
 	}
 
-	// Test with wrong number of args - should fail validation
-	err = logCmd.Args(logCmd, []string{"workspace/repo", "pipeline-uuid"})
-	if err == nil {
-		t.Error("expected error when only 2 args provided, got nil")
+	// Test without the repository - it is detected from the git remote
+	err = logCmd.Args(logCmd, []string{"pipeline-uuid", "step-uuid"})
+	if err != nil {
+		t.Errorf("expected no error with 2 args, got %v", err)
 	}
 
-	err = logCmd.Args(logCmd, []string{"workspace/repo"})
+	// Test with wrong number of args - should fail validation
+	err = logCmd.Args(logCmd, []string{"pipeline-uuid"})
 	if err == nil {
 		t.Error("expected error when only 1 arg provided, got nil")
 	}
@@ -394,7 +419,7 @@ func TestNewCmdList_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find list command: %v", err)
 	}
-	expected := "list <workspace/repo-slug>"
+	expected := "list [workspace/repo-slug]"
 	if listCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", listCmd.Use, expected)
 	}
@@ -406,7 +431,7 @@ func TestNewCmdView_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find view command: %v", err)
 	}
-	expected := "view <workspace/repo-slug> <pipeline-uuid>"
+	expected := "view [workspace/repo-slug] <pipeline-uuid>"
 	if viewCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", viewCmd.Use, expected)
 	}
@@ -418,7 +443,7 @@ func TestNewCmdTrigger_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find trigger command: %v", err)
 	}
-	expected := "trigger <workspace/repo-slug>"
+	expected := "trigger [workspace/repo-slug]"
 	if triggerCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", triggerCmd.Use, expected)
 	}
@@ -430,7 +455,7 @@ func TestNewCmdStop_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find stop command: %v", err)
 	}
-	expected := "stop <workspace/repo-slug> <pipeline-uuid>"
+	expected := "stop [workspace/repo-slug] <pipeline-uuid>"
 	if stopCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", stopCmd.Use, expected)
 	}
@@ -442,7 +467,7 @@ func TestNewCmdSteps_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find steps command: %v", err)
 	}
-	expected := "steps <workspace/repo-slug> <pipeline-uuid>"
+	expected := "steps [workspace/repo-slug] <pipeline-uuid>"
 	if stepsCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", stepsCmd.Use, expected)
 	}
@@ -454,7 +479,7 @@ func TestNewCmdLog_UseString(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to find log command: %v", err)
 	}
-	expected := "log <workspace/repo-slug> <pipeline-uuid> <step-uuid>"
+	expected := "log [workspace/repo-slug] <pipeline-uuid> <step-uuid>"
 	if logCmd.Use != expected {
 		t.Errorf("Use = %q, want %q", logCmd.Use, expected)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
@@ -23,13 +24,15 @@ type dailyStats struct {
 func newCmdTrends() *cobra.Command {
 	var days int
 	var branch string
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "trends <workspace/repo-slug>",
+		Use:   "trends [workspace/repo-slug]",
 		Short: "Show pipeline trends over time",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -38,7 +41,7 @@ func newCmdTrends() *cobra.Command {
 			repo := args[0]
 			cutoff := time.Now().UTC().AddDate(0, 0, -days)
 
-			pipelines, err := fetchPipelinesInWindow(client, repo, branch, cutoff)
+			pipelines, err := fetchPipelinesInWindow(cmd.Context(), client, repo, branch, cutoff)
 			if err != nil {
 				return err
 			}
@@ -89,11 +92,6 @@ func newCmdTrends() *cobra.Command {
 				results = append(results, *ds)
 			}
 
-			if jsonOut {
-				output.PrintJSON(results)
-				return nil
-			}
-
 			table := output.NewTable("DATE", "TOTAL", "PASSED", "FAILED", "RATE")
 			for _, ds := range results {
 				table.AddRow(
@@ -104,13 +102,13 @@ func newCmdTrends() *cobra.Command {
 					ds.Rate,
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(results, table)
 		},
 	}
 	cmd.Flags().IntVar(&days, "days", 30, "Number of days to look back")
 	cmd.Flags().StringVar(&branch, "branch", "", "Filter by branch name")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
@@ -1,16 +1,20 @@
 package pr
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
 	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
 	"github.com/PhilipKram/bitbucket-cli/internal/git"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
@@ -20,6 +24,7 @@ type PullRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
 	CreatedOn   string `json:"created_on"`
 	UpdatedOn   string `json:"updated_on"`
 	Author      struct {
@@ -29,6 +34,9 @@ type PullRequest struct {
 		Branch struct {
 			Name string `json:"name"`
 		} `json:"branch"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
 		Repository struct {
 			FullName string `json:"full_name"`
 		} `json:"repository"`
@@ -37,6 +45,9 @@ type PullRequest struct {
 		Branch struct {
 			Name string `json:"name"`
 		} `json:"branch"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
 	} `json:"destination"`
 	CloseSourceBranch bool `json:"close_source_branch"`
 	MergeCommit       *struct {
@@ -51,15 +62,33 @@ type PullRequest struct {
 	} `json:"links"`
 	Reviewers []struct {
 		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname"`
 		UUID        string `json:"uuid"`
 	} `json:"reviewers"`
-	Participants []struct {
-		User struct {
-			DisplayName string `json:"display_name"`
-		} `json:"user"`
-		Role     string `json:"role"`
-		Approved bool   `json:"approved"`
-	} `json:"participants"`
+	Participants []Participant `json:"participants"`
+}
+
+// Participant is a user who reviewed or commented on a pull request.
+type Participant struct {
+	User struct {
+		DisplayName string `json:"display_name"`
+		UUID        string `json:"uuid"`
+	} `json:"user"`
+	Role     string `json:"role"`
+	Approved bool   `json:"approved"`
+	State    string `json:"state"`
+}
+
+// ReviewState returns "approved", "changes_requested" or "" when the
+// participant has not reviewed the pull request.
+func (p Participant) ReviewState() string {
+	if p.State != "" {
+		return p.State
+	}
+	if p.Approved {
+		return "approved"
+	}
+	return ""
 }
 
 func NewCmdPR() *cobra.Command {
@@ -75,56 +104,66 @@ func NewCmdPR() *cobra.Command {
 	cmd.AddCommand(newCmdMerge())
 	cmd.AddCommand(newCmdApprove())
 	cmd.AddCommand(newCmdUnapprove())
+	cmd.AddCommand(newCmdRequestChanges())
+	cmd.AddCommand(newCmdTasks())
+	cmd.AddCommand(newCmdTask())
+	cmd.AddCommand(newCmdReview())
 	cmd.AddCommand(newCmdDecline())
 	cmd.AddCommand(newCmdComments())
 	cmd.AddCommand(newCmdComment())
 	cmd.AddCommand(newCmdDiff())
 	cmd.AddCommand(newCmdActivity())
 	cmd.AddCommand(newCmdEdit())
-
+	cmd.AddCommand(newCmdReady())
+	cmd.AddCommand(newCmdReviewers())
+	cmd.AddCommand(newCmdCheckout())
+	cmd.AddCommand(newCmdApply())
+	cmd.AddCommand(newCmdStatus())
+	cmd.AddCommand(newCmdChecks())
+
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 func newCmdList() *cobra.Command {
 	var state string
-	var page int
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 	var reviewer string
 	var author string
+	var draft bool
 
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List pull requests",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=25&page=%d", args[0], page)
+			// Participants and reviewers are left out of list responses unless
+			// requested explicitly.
+			fields := url.QueryEscape("+values.participants,+values.reviewers")
+			path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=25&page=%d&fields=%s", args[0], pagination.Page, fields)
 			var filters []string
 			if state != "" {
 				filters = append(filters, fmt.Sprintf(`state="%s"`, strings.ToUpper(state)))
 			}
 			if reviewer != "" {
-				if reviewer == "me" {
-					userData, err := client.Get("/user")
-					if err != nil {
-						return fmt.Errorf("failed to fetch current user: %w", err)
-					}
-					var user struct {
-						UUID string `json:"uuid"`
-					}
-					if err := json.Unmarshal(userData, &user); err != nil {
-						return err
-					}
-					reviewer = user.UUID
+				workspace := strings.SplitN(args[0], "/", 2)[0]
+				user, err := cmdutil.NewUserResolver(client, workspace).Resolve(cmd.Context(), reviewer)
+				if err != nil {
+					return err
 				}
-				filters = append(filters, fmt.Sprintf(`reviewers.uuid="%s"`, reviewer))
+				filters = append(filters, fmt.Sprintf(`reviewers.uuid="%s"`, user.UUID))
 			}
 			if author != "" {
 				if author == "me" {
-					userData, err := client.Get("/user")
+					userData, err := client.GetContext(cmd.Context(), "/user")
 					if err != nil {
 						return fmt.Errorf("failed to fetch current user: %w", err)
 					}
@@ -138,34 +177,25 @@ func newCmdList() *cobra.Command {
 				}
 				filters = append(filters, fmt.Sprintf(`author.uuid="%s"`, author))
 			}
+			if cmd.Flags().Changed("draft") {
+				filters = append(filters, fmt.Sprintf("draft=%t", draft))
+			}
 			if len(filters) > 0 {
 				path += "&q=" + url.QueryEscape(strings.Join(filters, " AND "))
 			}
-			data, err := client.Get(path)
+			prs, raw, _, err := cmdutil.FetchListWithRaw[PullRequest](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var prs []PullRequest
-			if err := json.Unmarshal(paginated.Values, &prs); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(prs)
-				return nil
-			}
-
 			table := output.NewTable("ID", "TITLE", "AUTHOR", "REVIEWERS", "SOURCE", "DEST", "STATE")
 			for _, pr := range prs {
 				reviewerNames := make([]string, len(pr.Reviewers))
-				for i, r := range pr.Reviewers {
+				for i, r := range reviewerStatuses(&pr) {
 					reviewerNames[i] = r.DisplayName
+					if r.Status != "pending" {
+						reviewerNames[i] += " (" + reviewStatusText(r.Status) + ")"
+					}
 				}
 				table.AddRow(
 					fmt.Sprintf("#%d", pr.ID),
@@ -174,36 +204,39 @@ func newCmdList() *cobra.Command {
 					output.Truncate(strings.Join(reviewerNames, ", "), 50),
 					pr.Source.Branch.Name,
 					pr.Destination.Branch.Name,
-					pr.State,
+					displayState(pr),
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(prs, raw), table)
 		},
 	}
 	cmd.Flags().StringVarP(&state, "state", "s", "", "Filter by state (OPEN, MERGED, DECLINED, SUPERSEDED)")
-	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
-	cmd.Flags().StringVar(&reviewer, "reviewer", "", `Filter by reviewer (UUID or "me" for yourself)`)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
+	cmd.Flags().StringVar(&reviewer, "reviewer", "", `Filter by reviewer (nickname, display name, email, UUID or "me" for yourself)`)
 	cmd.Flags().StringVar(&author, "author", "", `Filter by author (UUID or "me" for yourself)`)
+	cmd.Flags().BoolVar(&draft, "draft", false, "Show only draft pull requests (--draft=false hides them)")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "view <workspace/repo-slug> <pr-id>",
+		Use:   "view [workspace/repo-slug] <pr-id>",
 		Short: "View pull request details",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s", args[0], args[1])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -213,19 +246,30 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(pr)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(pr, json.RawMessage(data)))
 			}
 
 			output.PrintMessage("PR #%d: %s", pr.ID, pr.Title)
-			output.PrintMessage("State:       %s", pr.State)
+			output.PrintMessage("State:       %s", displayState(pr))
 			output.PrintMessage("Author:      %s", pr.Author.DisplayName)
 			output.PrintMessage("Source:      %s", pr.Source.Branch.Name)
 			output.PrintMessage("Destination: %s", pr.Destination.Branch.Name)
 			output.PrintMessage("Created:     %s", pr.CreatedOn)
 			output.PrintMessage("Updated:     %s", pr.UpdatedOn)
 			output.PrintMessage("Comments:    %d", pr.CommentCount)
+			if pr.Source.Commit.Hash != "" {
+				checks := "unavailable"
+				if statuses, _, err := fetchCommitStatuses(cmd.Context(), client, args[0], pr.Source.Commit.Hash); err == nil {
+					checks = checksSummary(statuses)
+				}
+				output.PrintMessage("Checks:      %s", checks)
+			}
+			tasks := "unavailable"
+			if list, err := fetchTasks(cmd.Context(), client, args[0], args[1]); err == nil {
+				tasks = tasksSummary(list)
+			}
+			output.PrintMessage("Tasks:       %s", tasks)
 			output.PrintMessage("URL:         %s", pr.Links.HTML.Href)
 			if pr.Description != "" {
 				output.PrintMessage("\nDescription:\n%s", pr.Description)
@@ -240,17 +284,17 @@ func newCmdView() *cobra.Command {
 			if len(pr.Participants) > 0 {
 				output.PrintMessage("\nParticipants:")
 				for _, p := range pr.Participants {
-					approved := ""
-					if p.Approved {
-						approved = " (approved)"
+					state := ""
+					if s := p.ReviewState(); s != "" {
+						state = " (" + reviewStatusText(s) + ")"
 					}
-					output.PrintMessage("  %s [%s]%s", p.User.DisplayName, p.Role, approved)
+					output.PrintMessage("  %s [%s]%s", p.User.DisplayName, p.Role, state)
 				}
 			}
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
@@ -260,35 +304,48 @@ func newCmdView() *cobra.Command {
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdCreate() *cobra.Command {
 	var title string
 	var description string
+	var descriptionFile string
+	var useEditor bool
+	var fill bool
 	var source string
 	var destination string
 	var closeBranch bool
 	var reviewers []string
 	var noDefaultReviewers bool
+	var draft bool
+	var push bool
 
 	cmd := &cobra.Command{
 		Use:   "create [workspace/repo-slug]",
 		Short: "Create a pull request",
-		Args:  cobra.RangeArgs(0, 1),
+		Long: `Create a pull request from the source branch, by default the current branch.
+
+Without --title, when run in a terminal, the command prompts for the title,
+opens your editor on the description and asks for reviewers, searching the
+workspace members. The title and description are prefilled from the commits
+between the source and destination branches, and the description from the
+repository's pull request template (.bitbucket/pull_request_template.md) when
+it has one. --fill uses the commits without prompting.
+
+A local source branch that is missing from the remote, or has unpushed
+commits, is pushed first: with --push directly, otherwise after asking.`,
+		Example: `  bb pr create
+  bb pr create --fill --push
+  bb pr create myworkspace/my-repo --title "Feature" --source feature-branch --editor`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Determine repository slug
-			repoSlug := ""
-			if len(args) > 0 {
-				repoSlug = args[0]
-			} else {
-				// Attempt auto-detection from git remote
-				workspace, repo, _, err := git.GetBitbucketContext("origin")
-				if err != nil {
-					return fmt.Errorf("failed to auto-detect repository from git remote: %w\nUse: bb pr create <workspace/repo-slug> --source <branch> --title <title>", err)
-				}
-				repoSlug = fmt.Sprintf("%s/%s", workspace, repo)
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
 			}
+			repoSlug := args[0]
 
 			// Determine source branch
 			sourceBranch := source
@@ -301,10 +358,79 @@ func newCmdCreate() *cobra.Command {
 				sourceBranch = branch
 			}
 
+			interactive := title == "" && !fill && cmdutil.CanPrompt()
+			if title == "" && !fill && !interactive {
+				return errors.InvalidInput("title", "required when not running in a terminal; use --title or --fill")
+			}
+			descriptionChanged := cmd.Flags().Changed("description") || cmd.Flags().Changed("description-file") || cmd.Flags().Changed("editor")
+			if descriptionChanged {
+				description, err = cmdutil.ResolveBody(
+					description, descriptionFile, useEditor,
+					cmd.Flags().Changed("description"),
+					cmd.Flags().Changed("description-file"),
+					cmd.Flags().Changed("editor"),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
+			workspace := strings.SplitN(repoSlug, "/", 2)[0]
+			resolver := cmdutil.NewUserResolver(client, workspace)
+
+			if fill || interactive {
+				dest := destination
+				if dest == "" {
+					if dest, err = fetchMainBranch(cmd.Context(), client, repoSlug); err != nil {
+						return err
+					}
+				}
+				commits, err := branchCommits(repoSlug, sourceBranch, dest)
+				if err != nil && fill {
+					return errors.GitError("log", err)
+				}
+				filledTitle, filledDescription := fillFromCommits(sourceBranch, commits)
+
+				if fill {
+					if title == "" {
+						title = filledTitle
+					}
+					if !descriptionChanged {
+						description = filledDescription
+					}
+				} else {
+					if title, err = cmdutil.Prompt("Title", filledTitle); err != nil {
+						return err
+					}
+					if title == "" {
+						return errors.InvalidInput("title", "cannot be blank")
+					}
+					if !descriptionChanged {
+						if tmpl := findPRTemplate(); tmpl != "" {
+							filledDescription = tmpl
+						}
+						edit, err := cmdutil.Confirm("Edit the description in your editor?", true)
+						if err != nil {
+							return err
+						}
+						description = filledDescription
+						if edit {
+							if description, err = cmdutil.EditText(filledDescription); err != nil {
+								return err
+							}
+						}
+					}
+					picked, err := promptReviewers(cmd.Context(), resolver, workspace)
+					if err != nil {
+						return err
+					}
+					reviewers = append(reviewers, picked...)
+				}
+			}
 
 			// Build final reviewers list: merge default reviewers + manual reviewers, deduplicated
 			finalReviewers := []string{}
@@ -313,7 +439,7 @@ func newCmdCreate() *cobra.Command {
 			// Fetch default reviewers if not disabled
 			var defaultReviewers []map[string]string
 			if !noDefaultReviewers {
-				defaults, err := fetchDefaultReviewers(client, repoSlug)
+				defaults, err := fetchDefaultReviewers(cmd.Context(), client, repoSlug)
 				if err != nil {
 					// Don't fail PR creation, just warn
 					output.PrintMessage("Warning: Could not fetch default reviewers (%s), continuing with PR creation", err.Error())
@@ -325,7 +451,7 @@ func newCmdCreate() *cobra.Command {
 			// Get current user UUID for self-exclusion
 			var currentUserUUID string
 			if len(defaultReviewers) > 0 {
-				userData, err := client.Get("/user")
+				userData, err := client.GetContext(cmd.Context(), "/user")
 				if err != nil {
 					output.PrintMessage("Warning: Could not fetch current user (%s), skipping self-exclusion", err.Error())
 				} else {
@@ -352,9 +478,16 @@ func newCmdCreate() *cobra.Command {
 
 			// Add manual reviewers from --reviewer flag
 			for _, r := range reviewers {
-				if r != "" && !seenUUIDs[r] {
-					finalReviewers = append(finalReviewers, r)
-					seenUUIDs[r] = true
+				if r == "" {
+					continue
+				}
+				user, err := resolver.Resolve(cmd.Context(), r)
+				if err != nil {
+					return err
+				}
+				if !seenUUIDs[user.UUID] {
+					finalReviewers = append(finalReviewers, user.UUID)
+					seenUUIDs[user.UUID] = true
 				}
 			}
 
@@ -366,6 +499,9 @@ func newCmdCreate() *cobra.Command {
 					"branch": map[string]string{"name": sourceBranch},
 				},
 			}
+			if draft {
+				body["draft"] = true
+			}
 			if destination != "" {
 				body["destination"] = map[string]interface{}{
 					"branch": map[string]string{"name": destination},
@@ -374,15 +510,28 @@ func newCmdCreate() *cobra.Command {
 			if len(finalReviewers) > 0 {
 				revList := make([]map[string]string, len(finalReviewers))
 				for i, r := range finalReviewers {
-					normalizedUUID := cmdutil.NormalizeUUID(r)
-					revList[i] = map[string]string{"uuid": normalizedUUID}
+					revList[i] = map[string]string{"uuid": r}
 				}
 				body["reviewers"] = revList
 			}
 
+			if interactive {
+				ok, err := cmdutil.Confirm(fmt.Sprintf("Create pull request %q from %s?", title, sourceBranch), true)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					output.PrintMessage("Pull request creation cancelled.")
+					return nil
+				}
+			}
+			if err := ensurePushed(repoSlug, sourceBranch, push, cmdutil.CanPrompt()); err != nil {
+				return err
+			}
+
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/pullrequests", repoSlug)
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -391,7 +540,11 @@ func newCmdCreate() *cobra.Command {
 			if err := json.Unmarshal(data, &pr); err != nil {
 				return err
 			}
-			output.PrintMessage("Pull request #%d created: %s", pr.ID, pr.Links.HTML.Href)
+			if pr.Draft {
+				output.PrintMessage("Draft pull request #%d created: %s", pr.ID, pr.Links.HTML.Href)
+			} else {
+				output.PrintMessage("Pull request #%d created: %s", pr.ID, pr.Links.HTML.Href)
+			}
 
 			// Show added default reviewers
 			if len(addedDefaultReviewers) > 0 {
@@ -404,14 +557,19 @@ func newCmdCreate() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().StringVarP(&title, "title", "t", "", "PR title (required)")
+	cmd.Flags().StringVarP(&title, "title", "t", "", "PR title (prompted for in a terminal when omitted)")
 	cmd.Flags().StringVarP(&description, "description", "d", "", "PR description")
+	cmd.Flags().StringVarP(&descriptionFile, "description-file", "F", "", "Read description from file (use - for stdin)")
+	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Open editor to compose description")
+	cmd.Flags().BoolVarP(&fill, "fill", "f", false, "Use commit messages for the title and description without prompting")
 	cmd.Flags().StringVarP(&source, "source", "s", "", "Source branch (auto-detected from current branch if not specified)")
 	cmd.Flags().StringVar(&destination, "destination", "", "Destination branch (defaults to main branch)")
 	cmd.Flags().BoolVar(&closeBranch, "close-branch", false, "Close source branch after merge")
-	cmd.Flags().StringSliceVarP(&reviewers, "reviewer", "r", nil, "Reviewer UUIDs")
+	cmd.Flags().StringSliceVarP(&reviewers, "reviewer", "r", nil, "Reviewers by nickname, display name, email or UUID")
 	cmd.Flags().BoolVar(&noDefaultReviewers, "no-default-reviewers", false, "Skip auto-fetching default reviewers")
-	cmd.MarkFlagRequired("title")
+	cmd.Flags().BoolVar(&draft, "draft", false, "Create the pull request as a draft")
+	cmd.Flags().BoolVar(&push, "push", false, "Push the source branch to the remote first if it is missing or behind")
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -419,16 +577,131 @@ func newCmdMerge() *cobra.Command {
 	var strategy string
 	var closeBranch bool
 	var message string
+	var auto bool
+	var dryRun bool
+	var interval int
+	var timeout time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "merge <workspace/repo-slug> <pr-id>",
+		Use:   "merge [workspace/repo-slug] <pr-id>",
 		Short: "Merge a pull request",
-		Args:  cobra.ExactArgs(2),
+		Long: `Merge a pull request.
+
+With --auto the command waits until the pull request can be merged, then
+merges it. It polls the approvals, change requests, open tasks and build
+statuses required by the destination branch's restrictions (which need
+repository admin access to read), reporting what it is waiting on, and gives
+up when a build fails or --timeout passes. Every reported build must pass
+even without restrictions, and when no restriction requires passing builds
+it still waits up to two minutes for a first build to be reported.
+
+--dry-run reports whether the pull request can be merged right now, exiting
+with 1 when it cannot.`,
+		Example: `  bb pr merge 42 --strategy squash
+  bb pr merge 42 --auto --timeout 2h
+  bb pr merge 42 --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if auto {
+				if err := cmdutil.ValidateInterval(interval); err != nil {
+					return err
+				}
+				if timeout <= 0 {
+					return errors.InvalidInput("timeout", fmt.Sprintf("%s: must be positive", timeout))
+				}
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
+
+			if auto || dryRun {
+				pr, _, err := fetchPullRequest(cmd.Context(), client, args[0], args[1])
+				if err != nil {
+					return err
+				}
+				req, err := fetchMergeRequirements(cmd.Context(), client, args[0], pr.Destination.Branch.Name)
+				if err != nil {
+					output.PrintMessage("Warning: Could not read branch restrictions (%s); only checking builds", err.Error())
+				}
+				for _, u := range req.Unchecked {
+					output.PrintMessage("Warning: Not checking %s", u)
+				}
+
+				var blockers []string
+				var merged bool
+				started := time.Now()
+				check := func(ctx context.Context) (bool, error) {
+					pr, _, err := fetchPullRequest(ctx, client, args[0], args[1])
+					if err != nil {
+						return false, err
+					}
+					if pr.State == "MERGED" {
+						merged = true
+						return true, nil
+					}
+					var statuses []CommitStatus
+					if pr.Source.Commit.Hash != "" {
+						if statuses, _, err = fetchCommitStatuses(ctx, client, args[0], pr.Source.Commit.Hash); err != nil {
+							return false, err
+						}
+					}
+					current := mergeBlockers(pr, statuses, req)
+					if !dryRun && awaitingFirstBuild(statuses, req, time.Since(started)) {
+						current = append(current, "no builds reported yet")
+					}
+					if dryRun {
+						blockers = current
+						return true, nil
+					}
+					if pr.State != "OPEN" || hasFailedBuild(statuses) {
+						return false, fmt.Errorf("not merging pull request #%d: %s", pr.ID, strings.Join(current, ", "))
+					}
+					if len(current) > 0 && strings.Join(current, ", ") != strings.Join(blockers, ", ") {
+						output.PrintMessage("Waiting on: %s", strings.Join(current, ", "))
+					}
+					blockers = current
+					return len(blockers) == 0, nil
+				}
+
+				if dryRun {
+					if _, err := check(cmd.Context()); err != nil {
+						return err
+					}
+					if merged {
+						output.PrintMessage("Pull request #%s is already merged.", args[1])
+						return nil
+					}
+					printMergeBlockers(pr.ID, blockers)
+					if len(blockers) > 0 {
+						os.Exit(1)
+					}
+					return nil
+				}
+
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				defer cancel()
+				err = cmdutil.Watch(ctx, time.Duration(interval)*time.Second, check)
+				if err == cmdutil.ErrWatchInterrupted {
+					if ctx.Err() == context.DeadlineExceeded {
+						return fmt.Errorf("timed out after %s waiting on: %s", timeout, strings.Join(blockers, ", "))
+					}
+					output.PrintMessage("\nWatch interrupted. Pull request #%s was not merged.", args[1])
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if merged {
+					output.PrintMessage("Pull request #%s was merged by someone else.", args[1])
+					return nil
+				}
+			}
+
 			body := map[string]interface{}{
 				"close_source_branch": closeBranch,
 			}
@@ -441,7 +714,7 @@ func newCmdMerge() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/merge", args[0], args[1])
-			_, err = client.Post(path, string(jsonBody))
+			_, err = client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -452,6 +725,11 @@ func newCmdMerge() *cobra.Command {
 	cmd.Flags().StringVar(&strategy, "strategy", "", "Merge strategy (merge_commit, squash, fast_forward)")
 	cmd.Flags().BoolVar(&closeBranch, "close-branch", true, "Close source branch after merge")
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Merge commit message")
+	cmd.Flags().BoolVar(&auto, "auto", false, "Wait until approvals and builds allow merging, then merge")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report whether the pull request can be merged now, without merging")
+	cmd.Flags().IntVarP(&interval, "interval", "i", 30, "Polling interval in seconds (with --auto)")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "Give up waiting after this long (with --auto)")
+	cmd.MarkFlagsMutuallyExclusive("auto", "dry-run")
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
@@ -461,21 +739,26 @@ func newCmdMerge() *cobra.Command {
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdApprove() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "approve <workspace/repo-slug> <pr-id>",
+		Use:   "approve [workspace/repo-slug] <pr-id>",
 		Short: "Approve a pull request",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/approve", args[0], args[1])
-			_, err = client.Post(path, "")
+			_, err = client.PostContext(cmd.Context(), path, "")
 			if err != nil {
 				return err
 			}
@@ -492,21 +775,26 @@ func newCmdApprove() *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdUnapprove() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "unapprove <workspace/repo-slug> <pr-id>",
+		Use:   "unapprove [workspace/repo-slug] <pr-id>",
 		Short: "Remove approval from a pull request",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/approve", args[0], args[1])
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -523,117 +811,82 @@ func newCmdUnapprove() *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
-func newCmdDecline() *cobra.Command {
+func newCmdRequestChanges() *cobra.Command {
+	var undo bool
+
 	cmd := &cobra.Command{
-		Use:   "decline <workspace/repo-slug> <pr-id>",
-		Short: "Decline a pull request",
-		Args:  cobra.ExactArgs(2),
+		Use:   "request-changes [workspace/repo-slug] <pr-id>",
+		Short: "Request changes on a pull request",
+		Long: `Request changes on a pull request, marking it as needing work before it can
+be merged. With --undo the change request is removed.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/decline", args[0], args[1])
-			_, err = client.Post(path, "")
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-			output.PrintMessage("Pull request #%s declined.", args[1])
-			return nil
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) == 0 {
-				return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/request-changes", args[0], args[1])
+			if undo {
+				if _, err := client.DeleteContext(cmd.Context(), path); err != nil {
+					return err
+				}
+				output.PrintMessage("Change request removed from PR #%s.", args[1])
+				return nil
 			}
-			if len(args) == 1 {
-				return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+			if _, err := client.PostContext(cmd.Context(), path, ""); err != nil {
+				return err
 			}
-			return nil, cobra.ShellCompDirectiveNoFileComp
+			output.PrintMessage("Changes requested on pull request #%s.", args[1])
+			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&undo, "undo", false, "Remove your change request")
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
-func newCmdComments() *cobra.Command {
-	var jsonOut bool
-
+func newCmdDecline() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "comments <workspace/repo-slug> <pr-id>",
-		Short: "List comments on a pull request",
-		Args:  cobra.ExactArgs(2),
+		Use:   "decline [workspace/repo-slug] <pr-id>",
+		Short: "Decline a pull request",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
-			if err != nil {
-				return err
-			}
-			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments?pagelen=50", args[0], args[1])
-			data, err := client.Get(path)
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
+			client, err := api.NewClient()
+			if err != nil {
 				return err
 			}
-
-			var comments []struct {
-				ID      int `json:"id"`
-				Content struct {
-					Raw string `json:"raw"`
-				} `json:"content"`
-				User struct {
-					DisplayName string `json:"display_name"`
-				} `json:"user"`
-				CreatedOn string `json:"created_on"`
-				Inline    *struct {
-					Path string `json:"path"`
-					From *int   `json:"from"`
-					To   *int   `json:"to"`
-				} `json:"inline"`
-			}
-			if err := json.Unmarshal(paginated.Values, &comments); err != nil {
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/decline", args[0], args[1])
+			_, err = client.PostContext(cmd.Context(), path, "")
+			if err != nil {
 				return err
 			}
-
-			if jsonOut {
-				output.PrintJSON(comments)
-				return nil
-			}
-
-			if len(comments) == 0 {
-				output.PrintMessage("No comments on this pull request.")
-				return nil
+			output.PrintMessage("Pull request #%s declined.", args[1])
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
 			}
-
-			for _, c := range comments {
-				loc := ""
-				if c.Inline != nil {
-					loc = fmt.Sprintf(" [%s", c.Inline.Path)
-					if c.Inline.To != nil {
-						loc += fmt.Sprintf(":%d", *c.Inline.To)
-					}
-					loc += "]"
-				}
-				output.PrintMessage("--- Comment #%d by %s (%s)%s ---", c.ID, c.User.DisplayName, c.CreatedOn[:10], loc)
-				output.PrintMessage("%s\n", c.Content.Raw)
+			if len(args) == 1 {
+				return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
 			}
-			return nil
+			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
-	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		if len(args) == 0 {
-			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
-		}
-		if len(args) == 1 {
-			return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
-		}
-		return nil, cobra.ShellCompDirectiveNoFileComp
-	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -643,12 +896,24 @@ func newCmdComment() *cobra.Command {
 	var useEditor bool
 	var file string
 	var line int
+	var replyTo int
 
 	cmd := &cobra.Command{
-		Use:   "comment <workspace/repo-slug> <pr-id>",
+		Use:   "comment [workspace/repo-slug] <pr-id>",
 		Short: "Add a comment to a pull request (supports inline comments on specific files/lines)",
-		Args:  cobra.ExactArgs(2),
+		Long: `Add a comment to a pull request: a general comment, an inline comment on a
+line of the diff with --file and --line, or a reply to another comment with
+--reply-to. The subcommands edit, delete and resolve existing comments.`,
+		Example: `  bb pr comment 42 --body "Looks good!"
+  bb pr comment 42 --body "Fix this" --file src/main.go --line 42
+  bb pr comment 42 --reply-to 1234 --body "Done"
+  bb pr comment resolve 42 1234`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			resolvedBody, err := cmdutil.ResolveBody(
 				body, bodyFile, useEditor,
 				cmd.Flags().Changed("body"),
@@ -664,6 +929,10 @@ func newCmdComment() *cobra.Command {
 			if fileSet != lineSet {
 				return fmt.Errorf("--file and --line must be used together")
 			}
+			replySet := cmd.Flags().Changed("reply-to")
+			if replySet && fileSet {
+				return fmt.Errorf("--reply-to cannot be used with --file and --line; replies are placed with their parent comment")
+			}
 
 			client, err := api.NewClient()
 			if err != nil {
@@ -678,15 +947,21 @@ func newCmdComment() *cobra.Command {
 					"to":   line,
 				}
 			}
+			if replySet {
+				reqBody["parent"] = map[string]int{"id": replyTo}
+			}
 			jsonBody, _ := json.Marshal(reqBody)
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments", args[0], args[1])
-			_, err = client.Post(path, string(jsonBody))
+			_, err = client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
-			if fileSet {
+			switch {
+			case fileSet:
 				output.PrintMessage("Inline comment added to PR #%s on %s:%d.", args[1], file, line)
-			} else {
+			case replySet:
+				output.PrintMessage("Reply to comment #%d added to PR #%s.", replyTo, args[1])
+			default:
 				output.PrintMessage("Comment added to PR #%s.", args[1])
 			}
 			return nil
@@ -697,6 +972,12 @@ func newCmdComment() *cobra.Command {
 	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Open editor to compose comment")
 	cmd.Flags().StringVarP(&file, "file", "f", "", "File path in the diff for inline comment")
 	cmd.Flags().IntVarP(&line, "line", "l", 0, "Line number in the file for inline comment")
+	cmd.Flags().IntVar(&replyTo, "reply-to", 0, "ID of the comment to reply to")
+
+	cmd.AddCommand(newCmdCommentEdit())
+	cmd.AddCommand(newCmdCommentDelete())
+	cmd.AddCommand(newCmdCommentResolve(false))
+	cmd.AddCommand(newCmdCommentResolve(true))
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
@@ -706,26 +987,48 @@ func newCmdComment() *cobra.Command {
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdDiff() *cobra.Command {
+	var opts cmdutil.DiffOptions
+	var patch bool
+
 	cmd := &cobra.Command{
-		Use:   "diff <workspace/repo-slug> <pr-id>",
+		Use:   "diff [workspace/repo-slug] <pr-id>",
 		Short: "View pull request diff",
-		Args:  cobra.ExactArgs(2),
+		Long: `View the diff of a pull request. On a terminal the diff is colored and
+shown through $BB_PAGER or $PAGER (less by default); set BB_PAGER=cat or
+use --no-pager to print it directly.
+
+--patch prints the pull request's commits as a mailbox of patches instead,
+in the format of git format-patch, for git am or bb pr apply.`,
+		Example: `  bb pr diff 42
+  bb pr diff 42 --stat
+  bb pr diff 42 --path 'internal/**/*.go' --side-by-side
+  bb pr diff 42 --patch | git am`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", args[0], args[1])
-			data, err := client.Get(path)
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-			fmt.Println(string(data))
-			return nil
+			if patch {
+				mbox, err := fetchPatch(cmd.Context(), client, args[0], args[1])
+				if err != nil {
+					return err
+				}
+				fmt.Print(mbox)
+				return nil
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", args[0], args[1])
+			statPath := fmt.Sprintf("/repositories/%s/pullrequests/%s/diffstat?pagelen=100", args[0], args[1])
+			return cmdutil.PrintDiff(cmd.Context(), client, path, statPath, &opts)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
@@ -737,23 +1040,31 @@ func newCmdDiff() *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
+	cmdutil.AddDiffFlags(cmd, &opts)
+	cmd.Flags().BoolVar(&patch, "patch", false, "Print the commits as a mailbox of patches for git am")
+	for _, name := range []string{"stat", "name-only", "side-by-side", "path"} {
+		cmd.MarkFlagsMutuallyExclusive("patch", name)
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
 func newCmdActivity() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "activity <workspace/repo-slug> <pr-id>",
+		Use:   "activity [workspace/repo-slug] <pr-id>",
 		Short: "View pull request activity log",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/activity?pagelen=50", args[0], args[1])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -763,13 +1074,12 @@ func newCmdActivity() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
+			if !output.IsTable() {
 				var raw interface{}
 				if err := json.Unmarshal(paginated.Values, &raw); err != nil {
 					return err
 				}
-				output.PrintJSON(raw)
-				return nil
+				return output.Print(raw)
 			}
 
 			// Activity is a heterogeneous list; render a summary table
@@ -826,7 +1136,7 @@ func newCmdActivity() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
@@ -836,6 +1146,7 @@ func newCmdActivity() *cobra.Command {
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -846,12 +1157,17 @@ func newCmdEdit() *cobra.Command {
 	var useEditor bool
 	var destination string
 	var closeBranch *bool
+	var draft *bool
 
 	cmd := &cobra.Command{
-		Use:   "edit <workspace/repo-slug> <pr-id>",
+		Use:   "edit [workspace/repo-slug] <pr-id>",
 		Short: "Edit a pull request",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			body := map[string]interface{}{}
 
 			if cmd.Flags().Changed("title") {
@@ -877,9 +1193,12 @@ func newCmdEdit() *cobra.Command {
 			if cmd.Flags().Changed("close-branch") {
 				body["close_source_branch"] = *closeBranch
 			}
+			if cmd.Flags().Changed("draft") {
+				body["draft"] = *draft
+			}
 
 			if len(body) == 0 {
-				return fmt.Errorf("no changes specified; use --title, --description, --destination, or --close-branch")
+				return fmt.Errorf("no changes specified; use --title, --description, --destination, --close-branch, or --draft")
 			}
 
 			client, err := api.NewClient()
@@ -889,7 +1208,7 @@ func newCmdEdit() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/pullrequests/%s", args[0], args[1])
-			data, err := client.Put(path, string(jsonBody))
+			data, err := client.PutContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -908,6 +1227,7 @@ func newCmdEdit() *cobra.Command {
 	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Open editor to compose description")
 	cmd.Flags().StringVar(&destination, "destination", "", "New destination branch")
 	closeBranch = cmd.Flags().Bool("close-branch", false, "Close source branch after merge")
+	draft = cmd.Flags().Bool("draft", false, "Convert to a draft (--draft=false marks it ready for review)")
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
@@ -917,14 +1237,68 @@ func newCmdEdit() *cobra.Command {
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
+func newCmdReady() *cobra.Command {
+	var undo bool
+
+	cmd := &cobra.Command{
+		Use:   "ready [workspace/repo-slug] <pr-id>",
+		Short: "Mark a draft pull request as ready for review",
+		Long: `Mark a draft pull request as ready for review, notifying its reviewers.
+With --undo the pull request is converted back to a draft.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			jsonBody, _ := json.Marshal(map[string]bool{"draft": undo})
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s", args[0], args[1])
+			if _, err := client.PutContext(cmd.Context(), path, string(jsonBody)); err != nil {
+				return err
+			}
+			if undo {
+				output.PrintMessage("Pull request #%s converted to draft.", args[1])
+			} else {
+				output.PrintMessage("Pull request #%s is marked as ready for review.", args[1])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&undo, "undo", false, "Convert the pull request back to a draft")
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+		}
+		if len(args) == 1 {
+			return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// displayState is the state shown in tables, marking open drafts.
+func displayState(pr PullRequest) string {
+	if pr.Draft && pr.State == "OPEN" {
+		return "DRAFT"
+	}
+	return pr.State
+}
+
 // fetchDefaultReviewers retrieves the repository's default reviewers from the Bitbucket API.
 // Returns a slice of reviewer maps with "uuid" and "display_name" keys.
-func fetchDefaultReviewers(client *api.Client, repoSlug string) ([]map[string]string, error) {
+func fetchDefaultReviewers(ctx context.Context, client *api.Client, repoSlug string) ([]map[string]string, error) {
 	path := fmt.Sprintf("/repositories/%s/default-reviewers", repoSlug)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,125 @@
+package pr
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+func newCmdApply() *cobra.Command {
+	var signoff bool
+
+	cmd := &cobra.Command{
+		Use:   "apply [workspace/repo-slug] <pr-id>",
+		Short: "Apply a pull request's commits to the current branch",
+		Long: `Download the commits of a pull request as patches and apply them to the
+current branch with git am, keeping their authors and messages. Unlike
+bb pr checkout, this needs no remote for pull requests from forks.
+
+Patches that do not apply cleanly fall back to a three-way merge. When a
+patch conflicts, the conflicting files are listed and git am is left in
+progress: resolve them and run "git am --continue", or run "git am --abort"
+to return the branch to where it was.`,
+		Example: `  bb pr apply 42
+  bb pr apply myworkspace/my-repo 42 --signoff
+  bb pr diff 42 --patch > pr-42.mbox`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			dirty, err := git.HasUncommittedChanges()
+			if err != nil {
+				return errors.GitError("status", err)
+			}
+			if dirty {
+				return &errors.BBError{
+					Message:    "You have uncommitted changes",
+					Suggestion: "Commit or stash them before applying a pull request.",
+				}
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			mbox, err := fetchPatch(cmd.Context(), client, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(mbox) == "" {
+				output.PrintMessage("Pull request #%s has no commits to apply.", args[1])
+				return nil
+			}
+
+			applied, err := git.ApplyMailbox(mbox, signoff)
+			for _, subject := range applied {
+				output.PrintMessage("Applied: %s", subject)
+			}
+			var applyErr *git.ApplyError
+			if stderrors.As(err, &applyErr) {
+				return applyConflictError(args[1], len(applied), applyErr)
+			}
+			if err != nil {
+				return errors.GitError("am", err)
+			}
+			output.PrintMessage("Applied %s from PR #%s.", pluralize(len(applied), "commit"), args[1])
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+			}
+			if len(args) == 1 {
+				return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	cmd.Flags().BoolVarP(&signoff, "signoff", "s", false, "Add a Signed-off-by trailer to each commit")
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// fetchPatch returns the commits of a pull request as a mailbox of patches,
+// in the format of git format-patch.
+func fetchPatch(ctx context.Context, client *api.Client, repo, id string) (string, error) {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/patch", repo, id)
+	data, err := client.GetContext(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// applyConflictError describes a patch of pull request id that did not
+// apply after the first applied patches did.
+func applyConflictError(id string, applied int, e *git.ApplyError) *errors.BBError {
+	patch := e.Patch
+	if patch == "" {
+		patch = "a patch"
+	}
+	msg := fmt.Sprintf("Could not apply %s of PR #%s", patch, id)
+	if applied > 0 {
+		msg += fmt.Sprintf(" (%s applied before it)", pluralize(applied, "commit"))
+	}
+	if len(e.Conflicts) > 0 {
+		msg += "\n\nConflicts:\n  " + strings.Join(e.Conflicts, "\n  ")
+	}
+	return &errors.BBError{
+		Message:    msg,
+		Suggestion: `Resolve the conflicts, "git add" the files and run "git am --continue", or run "git am --abort" to undo the applied commits.`,
+		Err:        e,
+	}
+}
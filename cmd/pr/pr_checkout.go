@@ -0,0 +1,185 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+func newCmdCheckout() *cobra.Command {
+	var branchName string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "checkout [workspace/repo-slug] <pr-id>",
+		Short: "Check out a pull request's source branch locally",
+		Long: `Fetch the source branch of a pull request and switch to a local branch
+tracking it.
+
+Pull requests from forks get a remote named after the fork's workspace,
+using the same protocol and host as the repository's own remote. The local
+branch is named after the source branch; if a branch of that name already
+tracks something else, pr-<id>-<branch> is used instead. An existing
+branch tracking the pull request is fast-forwarded.`,
+		Example: `  bb pr checkout 42
+  bb pr checkout myworkspace/my-repo 42 --branch review-42
+  bb pr checkout 42 --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s", args[0], args[1])
+			data, err := client.GetContext(cmd.Context(), path)
+			if err != nil {
+				return err
+			}
+			var pr PullRequest
+			if err := json.Unmarshal(data, &pr); err != nil {
+				return err
+			}
+
+			branch := pr.Source.Branch.Name
+			if branch == "" {
+				return fmt.Errorf("pull request #%d has no source branch", pr.ID)
+			}
+			sourceRepo := pr.Source.Repository.FullName
+			if sourceRepo == "" {
+				sourceRepo = args[0]
+			}
+
+			remote, err := checkoutRemote(args[0], sourceRepo)
+			if err != nil {
+				return err
+			}
+			if err := git.FetchBranch(remote, branch); err != nil {
+				return &errors.BBError{
+					Message:    fmt.Sprintf("Could not fetch branch '%s' from %s", branch, sourceRepo),
+					Suggestion: "The source branch may have been deleted after the pull request was merged or declined.",
+					Err:        err,
+				}
+			}
+			upstream := remote + "/" + branch
+
+			local, exists, err := checkoutBranchName(branchName, pr.ID, remote, branch, force)
+			if err != nil {
+				return err
+			}
+			switch {
+			case !exists || force:
+				if err := git.CheckoutTrackingBranch(local, remote, branch, force); err != nil {
+					return errors.GitError("checkout", err)
+				}
+			default:
+				if err := git.CheckoutBranch(local); err != nil {
+					return errors.GitError("checkout", err)
+				}
+				if err := git.MergeFastForward(upstream); err != nil {
+					return &errors.BBError{
+						Message:    fmt.Sprintf("Local branch '%s' has diverged from %s", local, upstream),
+						Suggestion: "Use --force to reset it to the pull request's head, or merge the changes yourself.",
+						Err:        err,
+					}
+				}
+			}
+			output.PrintMessage("Switched to branch '%s' tracking %s (PR #%d).", local, upstream, pr.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&branchName, "branch", "b", "", "Name of the local branch (default: the source branch name)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Reset an existing local branch to the pull request's head")
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+		}
+		if len(args) == 1 {
+			return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// checkoutRemote returns the name of the git remote for sourceRepo, the
+// repository a pull request into repoSlug comes from. A remote is added for
+// forks that have none, modeled on the remote of repoSlug.
+func checkoutRemote(repoSlug, sourceRepo string) (string, error) {
+	cfg, _ := config.LoadConfig()
+	hosts := cfg.GitHostnames()
+
+	remotes, err := git.Remotes()
+	if err != nil {
+		return "", errors.GitError("remote", err)
+	}
+	if r := git.FindRemote(remotes, sourceRepo, hosts...); r != nil {
+		return r.Name, nil
+	}
+
+	base := git.FindRemote(remotes, repoSlug, hosts...)
+	if base == nil {
+		return "", &errors.BBError{
+			Message:    fmt.Sprintf("No git remote points at %s", repoSlug),
+			Suggestion: "Run the command inside a clone of the repository, for example one created with 'bb repo clone'.",
+		}
+	}
+	url, err := git.ReplaceRepoInURL(base.URL, sourceRepo, hosts...)
+	if err != nil {
+		return "", errors.GitError("remote add", err)
+	}
+
+	name := strings.SplitN(sourceRepo, "/", 2)[0]
+	for _, r := range remotes {
+		if r.Name == name {
+			name = strings.ReplaceAll(sourceRepo, "/", "-")
+			break
+		}
+	}
+	if err := git.AddRemote(name, url); err != nil {
+		return "", errors.GitError("remote add", err)
+	}
+	output.PrintMessage("Added remote '%s' for %s.", name, sourceRepo)
+	return name, nil
+}
+
+// checkoutBranchName picks the local branch for a pull request whose source
+// is remote/branch. It reports whether that branch already exists. Without an
+// explicit name, a source branch name taken by a branch tracking something
+// else falls back to pr-<id>-<branch>.
+func checkoutBranchName(requested string, prID int, remote, branch string, force bool) (string, bool, error) {
+	candidates := []string{requested}
+	if requested == "" {
+		candidates = []string{branch, fmt.Sprintf("pr-%d-%s", prID, branch)}
+	}
+	if force {
+		return candidates[0], git.BranchExists(candidates[0]), nil
+	}
+
+	for _, name := range candidates {
+		if !git.BranchExists(name) {
+			return name, false, nil
+		}
+		if r, b := git.BranchUpstream(name); r == remote && b == branch {
+			return name, true, nil
+		}
+	}
+	return "", false, &errors.BBError{
+		Message:    fmt.Sprintf("Local branch '%s' already exists and does not track %s/%s", candidates[len(candidates)-1], remote, branch),
+		Suggestion: "Choose another name with --branch, or use --force to reset the branch to the pull request's head.",
+	}
+}
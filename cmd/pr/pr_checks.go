@@ -0,0 +1,292 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// CommitStatus is a build status reported against a commit, for example by
+// Bitbucket Pipelines or an external CI system.
+type CommitStatus struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	CreatedOn   string `json:"created_on"`
+	UpdatedOn   string `json:"updated_on"`
+}
+
+// Exit codes of pr checks when a check failed or is still running.
+const (
+	checksFailedExitCode  = 1
+	checksPendingExitCode = 8
+)
+
+func newCmdChecks() *cobra.Command {
+	var watch bool
+	var interval int
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "checks [workspace/repo-slug] <pr-id>",
+		Short: "Show build statuses for a pull request",
+		Long: `List the build statuses reported by Bitbucket Pipelines and external CI for
+the latest commit of a pull request.
+
+The exit code is 0 when every check passed, 1 when a check failed or was
+stopped and 8 when checks are still in progress. With --watch the command
+polls until every check has finished, also waiting while no check has been
+reported yet; when --timeout passes first it exits with 8. Formats other
+than the table print only the final result.`,
+		Example: `  bb pr checks 42
+  bb pr checks 42 --watch
+  bb pr checks 42 --watch --timeout 30m
+  bb pr checks myworkspace/my-repo 42 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if watch {
+				if err := cmdutil.ValidateInterval(interval); err != nil {
+					return err
+				}
+				if timeout <= 0 {
+					return errors.InvalidInput("timeout", fmt.Sprintf("%s: must be positive", timeout))
+				}
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			pr, _, err := fetchPullRequest(cmd.Context(), client, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			hash := pr.Source.Commit.Hash
+			if hash == "" {
+				return fmt.Errorf("pull request #%d has no source commit", pr.ID)
+			}
+
+			var statuses []CommitStatus
+			var raw []json.RawMessage
+			// While watching, the table is redrawn on every poll, but other
+			// formats get only the final result so the output stays a single
+			// document.
+			poll := func(ctx context.Context) (bool, error) {
+				latest, latestRaw, err := fetchCommitStatuses(ctx, client, args[0], hash)
+				if err != nil {
+					return false, err
+				}
+				statuses, raw = latest, latestRaw
+				finished := checksFinished(statuses)
+				if watch && output.IsTable() {
+					output.ClearScreen()
+				} else if watch && !finished {
+					return false, nil
+				}
+				if err := printChecks(pr.ID, statuses, raw); err != nil {
+					return false, err
+				}
+				return finished, nil
+			}
+			// printLast prints the last result when watching stops before
+			// the checks have finished.
+			printLast := func() error {
+				if output.IsTable() || raw == nil {
+					return nil
+				}
+				return printChecks(pr.ID, statuses, raw)
+			}
+
+			if watch {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				defer cancel()
+				err = cmdutil.Watch(ctx, time.Duration(interval)*time.Second, poll)
+				if err == cmdutil.ErrWatchInterrupted {
+					if err := printLast(); err != nil {
+						return err
+					}
+					if ctx.Err() != context.DeadlineExceeded {
+						output.PrintMessage("\nWatch interrupted. Exiting gracefully...")
+						return nil
+					}
+					output.PrintMessage("\nTimed out after %s waiting for checks.", timeout)
+					os.Exit(checksPendingExitCode)
+				}
+			} else {
+				_, err = poll(cmd.Context())
+			}
+			if err != nil {
+				return err
+			}
+
+			switch summarizeBuildStatus(statuses) {
+			case "FAILED":
+				os.Exit(checksFailedExitCode)
+			case "INPROGRESS":
+				os.Exit(checksPendingExitCode)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Poll until every check has finished")
+	cmd.Flags().IntVarP(&interval, "interval", "i", 10, "Polling interval in seconds (when watching)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Give up waiting after this long (when watching)")
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+		}
+		if len(args) == 1 {
+			return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// fetchPullRequest returns a pull request along with its raw JSON.
+func fetchPullRequest(ctx context.Context, client *api.Client, repoSlug, id string) (*PullRequest, json.RawMessage, error) {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s", repoSlug, id)
+	data, err := client.GetContext(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pr PullRequest
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return nil, nil, err
+	}
+	return &pr, json.RawMessage(data), nil
+}
+
+// fetchCommitStatuses returns the build statuses of a commit along with each
+// status's raw JSON.
+func fetchCommitStatuses(ctx context.Context, client *api.Client, repoSlug, hash string) ([]CommitStatus, []json.RawMessage, error) {
+	path := fmt.Sprintf("/repositories/%s/commit/%s/statuses?pagelen=100", repoSlug, hash)
+	raw, err := api.NewPaginator[json.RawMessage](client, path, 0).All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	statuses := make([]CommitStatus, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &statuses[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return statuses, raw, nil
+}
+
+// checksFinished reports whether watching can stop: at least one check was
+// reported and none is still running. Right after a push the checks may not
+// have been reported yet, so no statuses means keep waiting.
+func checksFinished(statuses []CommitStatus) bool {
+	summary := summarizeBuildStatus(statuses)
+	return summary != "" && summary != "INPROGRESS"
+}
+
+// summarizeBuildStatus reduces commit statuses to a single state: FAILED if
+// any build failed or was stopped, INPROGRESS if any is still running,
+// SUCCESSFUL otherwise. It returns "" when there are no statuses.
+func summarizeBuildStatus(statuses []CommitStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	summary := "SUCCESSFUL"
+	for _, s := range statuses {
+		switch s.State {
+		case "FAILED", "STOPPED":
+			return "FAILED"
+		case "INPROGRESS":
+			summary = "INPROGRESS"
+		}
+	}
+	return summary
+}
+
+// checksSummary counts commit statuses by outcome, for example
+// "2 passed, 1 failed".
+func checksSummary(statuses []CommitStatus) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	var passed, failed, running int
+	for _, s := range statuses {
+		switch s.State {
+		case "SUCCESSFUL":
+			passed++
+		case "FAILED", "STOPPED":
+			failed++
+		case "INPROGRESS":
+			running++
+		}
+	}
+	var parts []string
+	if passed > 0 {
+		parts = append(parts, fmt.Sprintf("%d passed", passed))
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	if running > 0 {
+		parts = append(parts, fmt.Sprintf("%d in progress", running))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkDuration is how long a build ran, or has been running so far.
+func checkDuration(s CommitStatus, now time.Time) string {
+	start, err := time.Parse(time.RFC3339, s.CreatedOn)
+	if err != nil {
+		return "-"
+	}
+	end := now
+	if s.State != "INPROGRESS" {
+		if end, err = time.Parse(time.RFC3339, s.UpdatedOn); err != nil {
+			return "-"
+		}
+	}
+	if end.Before(start) {
+		return "-"
+	}
+	return end.Sub(start).Round(time.Second).String()
+}
+
+func printChecks(prID int, statuses []CommitStatus, raw []json.RawMessage) error {
+	if len(statuses) == 0 && output.IsTable() {
+		output.PrintMessage("No checks reported for PR #%d.", prID)
+		return nil
+	}
+
+	now := time.Now()
+	table := output.NewTable("STATE", "NAME", "DURATION", "URL")
+	for _, s := range statuses {
+		name := s.Name
+		if name == "" {
+			name = s.Key
+		}
+		table.AddRow(s.State, output.Truncate(name, 50), checkDuration(s, now), s.URL)
+	}
+	if err := output.Render(output.WithRaw(statuses, raw), table); err != nil {
+		return err
+	}
+	if output.IsTable() {
+		output.PrintMessage("\n%s: %s", pluralize(len(statuses), "check"), checksSummary(statuses))
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/comments"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// commentLocation describes where an inline comment is, for example
+// "main.go:42".
+func commentLocation(c *comments.Comment) string {
+	if c.Inline == nil {
+		return ""
+	}
+	switch {
+	case c.Inline.To != nil:
+		return fmt.Sprintf("%s:%d", c.Inline.Path, *c.Inline.To)
+	case c.Inline.From != nil:
+		return fmt.Sprintf("%s:%d (old)", c.Inline.Path, *c.Inline.From)
+	}
+	return c.Inline.Path
+}
+
+func newCmdComments() *cobra.Command {
+	var contextLines int
+	var unresolved bool
+
+	cmd := &cobra.Command{
+		Use:   "comments [workspace/repo-slug] <pr-id>",
+		Short: "List comments on a pull request",
+		Long: `List the comments on a pull request as threads, with replies below the
+comment they answer. Inline comments show the lines of the diff around them.`,
+		Example: `  bb pr comments 42
+  bb pr comments 42 --unresolved
+  bb pr comments 42 --context 0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			all, raw, err := fetchComments(cmd.Context(), client, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			threads := comments.Threads(all)
+			if unresolved {
+				threads = comments.Unresolved(threads)
+			}
+
+			if !output.IsTable() {
+				keep := make(map[int]bool)
+				for _, t := range threads {
+					t.Walk(func(c *comments.Comment, _ int) { keep[c.ID] = true })
+				}
+				var data []comments.Comment
+				var rawData []json.RawMessage
+				for i, c := range all {
+					if keep[c.ID] {
+						data = append(data, c)
+						rawData = append(rawData, raw[i])
+					}
+				}
+				return output.Print(output.WithRaw(data, rawData))
+			}
+
+			if len(threads) == 0 {
+				if unresolved {
+					output.PrintMessage("No unresolved comments on this pull request.")
+				} else {
+					output.PrintMessage("No comments on this pull request.")
+				}
+				return nil
+			}
+
+			var files []diff.File
+			if contextLines > 0 && comments.HasInline(threads) {
+				path := fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", args[0], args[1])
+				if data, err := client.GetContext(cmd.Context(), path); err == nil {
+					files = diff.Parse(string(data))
+				}
+			}
+			for _, t := range threads {
+				printThread(t, files, contextLines)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&contextLines, "context", "C", 3, "Lines of diff shown around inline comments (0 to hide)")
+	cmd.Flags().BoolVar(&unresolved, "unresolved", false, "Only show threads that are not resolved")
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+func newCmdCommentEdit() *cobra.Command {
+	var body string
+	var bodyFile string
+	var useEditor bool
+
+	cmd := &cobra.Command{
+		Use:   "edit [workspace/repo-slug] <pr-id> <comment-id>",
+		Short: "Edit a pull request comment",
+		Example: `  bb pr comment edit 42 1234 --body "Updated wording"
+  bb pr comment edit 42 1234 --editor`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := validateID("comment-id", args[2]); err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s", args[0], args[1], args[2])
+
+			var content string
+			if useEditor && !cmd.Flags().Changed("body") && !cmd.Flags().Changed("body-file") {
+				// Start the editor from the current text of the comment.
+				data, err := client.GetContext(cmd.Context(), path)
+				if err != nil {
+					return err
+				}
+				var c comments.Comment
+				if err := json.Unmarshal(data, &c); err != nil {
+					return err
+				}
+				content, err = cmdutil.EditText(c.Content.Raw)
+				if err != nil {
+					return err
+				}
+				if strings.TrimSpace(content) == "" {
+					return fmt.Errorf("comment cannot be blank")
+				}
+			} else {
+				content, err = cmdutil.ResolveBody(
+					body, bodyFile, useEditor,
+					cmd.Flags().Changed("body"),
+					cmd.Flags().Changed("body-file"),
+					cmd.Flags().Changed("editor"),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			jsonBody, _ := json.Marshal(map[string]interface{}{
+				"content": map[string]string{"raw": content},
+			})
+			if _, err := client.PutContext(cmd.Context(), path, string(jsonBody)); err != nil {
+				return err
+			}
+			output.PrintMessage("Comment #%s on PR #%s updated.", args[2], args[1])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&body, "body", "b", "", "New comment body")
+	cmd.Flags().StringVarP(&bodyFile, "body-file", "F", "", "Read body from file (use - for stdin)")
+	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Edit the comment in your editor")
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+func newCmdCommentDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [workspace/repo-slug] <pr-id> <comment-id>",
+		Short: "Delete a pull request comment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := validateID("comment-id", args[2]); err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s", args[0], args[1], args[2])
+			if _, err := client.DeleteContext(cmd.Context(), path); err != nil {
+				return err
+			}
+			output.PrintMessage("Comment #%s deleted from PR #%s.", args[2], args[1])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+// newCmdCommentResolve returns the resolve command, or the unresolve command
+// when undo is set.
+func newCmdCommentResolve(undo bool) *cobra.Command {
+	name, short := "resolve", "Resolve a pull request comment thread"
+	if undo {
+		name, short = "unresolve", "Reopen a resolved pull request comment thread"
+	}
+
+	cmd := &cobra.Command{
+		Use:   name + " [workspace/repo-slug] <pr-id> <comment-id>",
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := validateID("comment-id", args[2]); err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s/resolve", args[0], args[1], args[2])
+			if undo {
+				if _, err := client.DeleteContext(cmd.Context(), path); err != nil {
+					return err
+				}
+				output.PrintMessage("Comment thread #%s on PR #%s reopened.", args[2], args[1])
+				return nil
+			}
+			if _, err := client.PostContext(cmd.Context(), path, ""); err != nil {
+				return err
+			}
+			output.PrintMessage("Comment thread #%s on PR #%s resolved.", args[2], args[1])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+// fetchComments returns every comment on a pull request along with the JSON
+// of each as returned by the API.
+func fetchComments(ctx context.Context, client *api.Client, repoSlug, id string) ([]comments.Comment, []json.RawMessage, error) {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments?pagelen=100", repoSlug, id)
+	raw, err := api.GetAll[json.RawMessage](ctx, client, path, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	all := make([]comments.Comment, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &all[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return all, raw, nil
+}
+
+// printThread prints a comment thread, indenting replies and showing the
+// diff around the line an inline thread is on.
+func printThread(t *comments.Thread, files []diff.File, contextLines int) {
+	t.Walk(func(c *comments.Comment, depth int) {
+		indent := strings.Repeat("    ", depth)
+		header := fmt.Sprintf("Comment #%d by %s (%s)", c.ID, c.Author(), commentDate(c.CreatedOn))
+		if depth > 0 {
+			header = fmt.Sprintf("Reply #%d by %s (%s)", c.ID, c.Author(), commentDate(c.CreatedOn))
+		}
+		if loc := commentLocation(c); loc != "" && depth == 0 {
+			header += " [" + loc + "]"
+			if c.Inline.Outdated {
+				header += " " + output.ColorText("outdated", "gray")
+			}
+		}
+		if c.Resolution != nil {
+			header += " " + output.ColorText("resolved by "+c.Resolution.User.DisplayName, "green")
+		}
+		output.PrintMessage("%s--- %s ---", indent, header)
+
+		if depth == 0 && contextLines > 0 {
+			for _, line := range inlineContext(c, files, contextLines) {
+				output.PrintMessage("%s", line)
+			}
+		}
+
+		body := c.Content.Raw
+		if c.Deleted {
+			body = output.ColorText("(deleted)", "gray")
+		}
+		for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+			output.PrintMessage("%s%s", indent, line)
+		}
+		output.PrintMessage("")
+	})
+}
+
+// inlineContext returns the diff lines around an inline comment, marking the
+// line it is on, or nil when the diff does not show that line.
+func inlineContext(c *comments.Comment, files []diff.File, contextLines int) []string {
+	lines, index, ok := c.DiffContext(files, contextLines)
+	if !ok {
+		return nil
+	}
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		marker := " "
+		if i == index {
+			marker = ">"
+		}
+		num := l.NewLine
+		if l.Kind == diff.Removed {
+			num = l.OldLine
+		}
+		text := fmt.Sprintf("%c%s", l.Kind, l.Text)
+		switch l.Kind {
+		case diff.Added:
+			text = output.ColorText(text, "green")
+		case diff.Removed:
+			text = output.ColorText(text, "red")
+		}
+		out[i] = fmt.Sprintf("%s %5d %s", marker, num, text)
+	}
+	return out
+}
+
+// commentDate returns the date part of an API timestamp.
+func commentDate(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
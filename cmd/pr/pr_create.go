@@ -0,0 +1,232 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// prTemplatePaths are the pull request template locations checked, relative
+// to the root of the working tree, in order.
+var prTemplatePaths = []string{
+	".bitbucket/pull_request_template.md",
+	".bitbucket/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+}
+
+// findPRTemplate returns the contents of the repository's pull request
+// template, or "" when the current working tree has none.
+func findPRTemplate() string {
+	root, err := git.TopLevel()
+	if err != nil {
+		return ""
+	}
+	for _, p := range prTemplatePaths {
+		if data, err := os.ReadFile(filepath.Join(root, p)); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
+
+// repoRemote returns the name of the git remote pointing at repoSlug, falling
+// back to the configured remote when none matches.
+func repoRemote(repoSlug string) string {
+	cfg, _ := config.LoadConfig()
+	if remotes, err := git.Remotes(); err == nil {
+		if r := git.FindRemote(remotes, repoSlug, cfg.GitHostnames()...); r != nil {
+			return r.Name
+		}
+	}
+	return cfg.GitRemoteName()
+}
+
+// ensurePushed makes sure the local branch is on the repository's remote
+// before a pull request is opened from it. Missing branches and unpushed
+// commits are pushed when push is set, or when the user agrees if prompt is
+// set; otherwise a missing branch is an error and unpushed commits a warning.
+// Branches that do not exist locally are left alone.
+func ensurePushed(repoSlug, branch string, push, prompt bool) error {
+	if !git.BranchExists(branch) {
+		return nil
+	}
+	remote := repoRemote(repoSlug)
+
+	onRemote, err := git.RemoteBranchExists(remote, branch)
+	if err != nil {
+		return errors.GitError("ls-remote", err)
+	}
+	var ahead int
+	if onRemote {
+		// Compare against the branch as it is on the remote now, not as it
+		// was when last fetched.
+		if err := git.FetchBranch(remote, branch); err != nil {
+			return errors.GitError("fetch", err)
+		}
+		n, err := git.CommitsAhead(remote+"/"+branch, branch)
+		if err != nil {
+			return errors.GitError("rev-list", err)
+		}
+		ahead = n
+	}
+	if onRemote && ahead == 0 {
+		return nil
+	}
+
+	problem := fmt.Sprintf("Branch %s is not on %s", branch, remote)
+	if onRemote {
+		problem = fmt.Sprintf("Branch %s has %s not pushed to %s", branch, pluralize(ahead, "commit"), remote)
+	}
+	if !push && prompt {
+		ok, err := cmdutil.Confirm(problem+". Push it now?", true)
+		if err != nil {
+			return err
+		}
+		push = ok
+	}
+	if !push {
+		if onRemote {
+			output.PrintMessage("Warning: %s; they will not be part of the pull request.", problem)
+			return nil
+		}
+		return &errors.BBError{
+			Message:    problem,
+			Suggestion: fmt.Sprintf("Run again with --push, or push it with 'git push -u %s %s'.", remote, branch),
+		}
+	}
+
+	upstream, _ := git.BranchUpstream(branch)
+	if err := git.Push(remote, branch, upstream == ""); err != nil {
+		return errors.GitError("push", err)
+	}
+	output.PrintMessage("Pushed %s to %s.", branch, remote)
+	return nil
+}
+
+// fetchMainBranch returns the name of the repository's main branch.
+func fetchMainBranch(ctx context.Context, client *api.Client, repoSlug string) (string, error) {
+	data, err := client.GetContext(ctx, fmt.Sprintf("/repositories/%s?fields=mainbranch.name", repoSlug))
+	if err != nil {
+		return "", err
+	}
+	var repo struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if err := json.Unmarshal(data, &repo); err != nil {
+		return "", err
+	}
+	if repo.MainBranch.Name == "" {
+		return "", fmt.Errorf("repository %s has no main branch", repoSlug)
+	}
+	return repo.MainBranch.Name, nil
+}
+
+// branchCommits returns the local commits on source that are not on
+// destination, preferring the remote-tracking branches of the repository's
+// remote when they exist.
+func branchCommits(repoSlug, source, destination string) ([]git.Commit, error) {
+	remote := repoRemote(repoSlug)
+	resolve := func(branch string, candidates ...string) (string, error) {
+		for _, ref := range candidates {
+			if _, err := git.ResolveCommit(ref); err == nil {
+				return ref, nil
+			}
+		}
+		return "", fmt.Errorf("branch %s not found locally; fetch it first", branch)
+	}
+
+	base, err := resolve(destination, remote+"/"+destination, destination)
+	if err != nil {
+		return nil, err
+	}
+	head, err := resolve(source, source, remote+"/"+source)
+	if err != nil {
+		return nil, err
+	}
+	return git.Commits(base, head)
+}
+
+// fillFromCommits derives a pull request title and description from the
+// commits on branch: a single commit provides both, several commits are
+// listed in the description under a title made from the branch name.
+func fillFromCommits(branch string, commits []git.Commit) (title, description string) {
+	if len(commits) == 1 {
+		return commits[0].Subject, commits[0].Body
+	}
+
+	var sb strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&sb, "- %s\n", c.Subject)
+	}
+	return titleFromBranch(branch), sb.String()
+}
+
+// titleFromBranch turns a branch name such as "feature/add-login" into a
+// title such as "Add login".
+func titleFromBranch(branch string) string {
+	name := branch[strings.LastIndex(branch, "/")+1:]
+	name = strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+	if name == "" {
+		return branch
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// promptReviewers asks for reviewers by searching the members of workspace
+// until a blank search, returning the UUIDs of the users picked.
+func promptReviewers(ctx context.Context, resolver *cmdutil.UserResolver, workspace string) ([]string, error) {
+	var uuids []string
+	for {
+		query, err := cmdutil.Prompt("Add a reviewer (search by name, blank to finish)", "")
+		if err != nil || query == "" {
+			return uuids, err
+		}
+
+		members, err := resolver.Search(ctx, query)
+		if err != nil {
+			output.PrintMessage("Warning: Could not search workspace members (%s)", err.Error())
+			continue
+		}
+		if len(members) == 0 {
+			output.PrintMessage("No members of %s match %q.", workspace, query)
+			continue
+		}
+		for i, m := range members {
+			output.PrintMessage("  %d. %s (%s)", i+1, m.DisplayName, m.Nickname)
+		}
+
+		def := ""
+		if len(members) == 1 {
+			def = "1"
+		}
+		choice, err := cmdutil.Prompt(fmt.Sprintf("Reviewer to add (1-%d, blank to skip)", len(members)), def)
+		if err != nil {
+			return uuids, err
+		}
+		if choice == "" {
+			continue
+		}
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > len(members) {
+			output.PrintMessage("Invalid choice %q.", choice)
+			continue
+		}
+		uuids = append(uuids, members[n-1].UUID)
+		output.PrintMessage("Added %s.", members[n-1].DisplayName)
+	}
+}
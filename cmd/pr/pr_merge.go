@@ -0,0 +1,294 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// branchRestriction is a destination branch restriction relevant to merging.
+// It applies to branches matching Pattern, or with branch_match_kind
+// "branching_model" to the branches of BranchType in the branching model.
+type branchRestriction struct {
+	Kind            string `json:"kind"`
+	BranchMatchKind string `json:"branch_match_kind"`
+	Pattern         string `json:"pattern"`
+	BranchType      string `json:"branch_type"`
+	Value           *int   `json:"value"`
+}
+
+// branchingModel is a repository's effective branching model.
+type branchingModel struct {
+	Development *modelBranch `json:"development"`
+	Production  *modelBranch `json:"production"`
+	BranchTypes []struct {
+		Kind   string `json:"kind"`
+		Prefix string `json:"prefix"`
+	} `json:"branch_types"`
+}
+
+type modelBranch struct {
+	Branch *struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+// hasType reports whether branch is of branchType in the model: the
+// development or production branch itself, or a branch whose name starts
+// with the prefix of a type such as feature or release.
+func (m *branchingModel) hasType(branchType, branch string) bool {
+	switch branchType {
+	case "development":
+		return m.Development != nil && m.Development.Branch != nil && m.Development.Branch.Name == branch
+	case "production":
+		return m.Production != nil && m.Production.Branch != nil && m.Production.Branch.Name == branch
+	}
+	for _, t := range m.BranchTypes {
+		if t.Kind == branchType && t.Prefix != "" && strings.HasPrefix(branch, t.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRequirements are the merge checks that branch restrictions place on a
+// pull request into a branch.
+type mergeRequirements struct {
+	Approvals                int
+	DefaultReviewerApprovals int
+	PassingBuilds            int
+	TasksCompleted           bool
+	NoChangesRequested       bool
+	// DefaultReviewers holds the UUIDs of the default reviewers, whose
+	// approvals count towards DefaultReviewerApprovals.
+	DefaultReviewers map[string]bool
+	// Unchecked describes restrictions that could not be evaluated.
+	Unchecked []string
+}
+
+// fetchMergeRequirements returns the merge checks configured for the branch
+// through branch restrictions. Reading restrictions needs repository admin
+// access. Restrictions on branch types are resolved with the branching
+// model; those that cannot be are listed in Unchecked.
+func fetchMergeRequirements(ctx context.Context, client *api.Client, repoSlug, branch string) (mergeRequirements, error) {
+	path := fmt.Sprintf("/repositories/%s/branch-restrictions?pagelen=100", repoSlug)
+	restrictions, err := api.GetAll[branchRestriction](ctx, client, path, 0)
+	if err != nil {
+		return mergeRequirements{}, err
+	}
+
+	var model *branchingModel
+	for _, r := range restrictions {
+		if r.BranchMatchKind == "branching_model" {
+			model, err = fetchBranchingModel(ctx, client, repoSlug)
+			if err != nil {
+				output.PrintMessage("Warning: Could not read the branching model (%s)", err.Error())
+			}
+			break
+		}
+	}
+
+	req := requirementsFor(restrictions, branch, model)
+	if req.DefaultReviewerApprovals > 0 {
+		if req.DefaultReviewers, err = fetchEffectiveDefaultReviewers(ctx, client, repoSlug); err != nil {
+			req.Unchecked = append(req.Unchecked, fmt.Sprintf("%s from default reviewers (%s)", pluralize(req.DefaultReviewerApprovals, "approval"), err.Error()))
+			req.DefaultReviewerApprovals = 0
+		}
+	}
+	return req, nil
+}
+
+// fetchBranchingModel returns the branching model in effect for a
+// repository, including settings inherited from its project.
+func fetchBranchingModel(ctx context.Context, client *api.Client, repoSlug string) (*branchingModel, error) {
+	data, err := client.GetContext(ctx, fmt.Sprintf("/repositories/%s/effective-branching-model", repoSlug))
+	if err != nil {
+		return nil, err
+	}
+	var model branchingModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// fetchEffectiveDefaultReviewers returns the UUIDs of the repository's
+// default reviewers, including those inherited from its project.
+func fetchEffectiveDefaultReviewers(ctx context.Context, client *api.Client, repoSlug string) (map[string]bool, error) {
+	path := fmt.Sprintf("/repositories/%s/effective-default-reviewers?pagelen=100", repoSlug)
+	reviewers, err := api.GetAll[struct {
+		User struct {
+			UUID string `json:"uuid"`
+		} `json:"user"`
+	}](ctx, client, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	uuids := make(map[string]bool, len(reviewers))
+	for _, r := range reviewers {
+		uuids[r.User.UUID] = true
+	}
+	return uuids, nil
+}
+
+// requirementsFor combines the restrictions that apply to branch. model
+// resolves restrictions on branch types; when it is nil they are listed in
+// Unchecked instead.
+func requirementsFor(restrictions []branchRestriction, branch string, model *branchingModel) mergeRequirements {
+	var req mergeRequirements
+	for _, r := range restrictions {
+		if !isMergeCheck(r.Kind) {
+			continue
+		}
+		switch r.BranchMatchKind {
+		case "glob":
+			if !matchBranchPattern(r.Pattern, branch) {
+				continue
+			}
+		case "branching_model":
+			if model == nil {
+				req.Unchecked = append(req.Unchecked, fmt.Sprintf("%s on %s branches", r.Kind, r.BranchType))
+				continue
+			}
+			if !model.hasType(r.BranchType, branch) {
+				continue
+			}
+		default:
+			continue
+		}
+		value := 1
+		if r.Value != nil {
+			value = *r.Value
+		}
+		switch r.Kind {
+		case "require_approvals_to_merge":
+			req.Approvals = max(req.Approvals, value)
+		case "require_default_reviewer_approvals_to_merge":
+			req.DefaultReviewerApprovals = max(req.DefaultReviewerApprovals, value)
+		case "require_passing_builds_to_merge":
+			req.PassingBuilds = max(req.PassingBuilds, value)
+		case "require_tasks_to_be_completed":
+			req.TasksCompleted = true
+		case "require_no_changes_requested":
+			req.NoChangesRequested = true
+		}
+	}
+	return req
+}
+
+// isMergeCheck reports whether a restriction kind is a merge check that
+// mergeBlockers evaluates.
+func isMergeCheck(kind string) bool {
+	switch kind {
+	case "require_approvals_to_merge", "require_default_reviewer_approvals_to_merge",
+		"require_passing_builds_to_merge", "require_tasks_to_be_completed", "require_no_changes_requested":
+		return true
+	}
+	return false
+}
+
+// matchBranchPattern reports whether branch matches a branch restriction
+// glob, in which * matches any run of characters.
+func matchBranchPattern(pattern, branch string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(branch)
+}
+
+// mergeBlockers lists what keeps pr from being merged: missing approvals,
+// unfinished or failed builds, open tasks and change requests. It is empty
+// when the pull request can be merged.
+func mergeBlockers(pr *PullRequest, statuses []CommitStatus, req mergeRequirements) []string {
+	if pr.State != "OPEN" {
+		return []string{fmt.Sprintf("pull request is %s", strings.ToLower(pr.State))}
+	}
+
+	var blockers []string
+	if pr.Draft {
+		blockers = append(blockers, "pull request is a draft")
+	}
+
+	var approvals, defaultApprovals, changes int
+	for _, p := range pr.Participants {
+		switch p.ReviewState() {
+		case "approved":
+			approvals++
+			if req.DefaultReviewers[p.User.UUID] {
+				defaultApprovals++
+			}
+		case "changes_requested":
+			changes++
+		}
+	}
+	if approvals < req.Approvals {
+		blockers = append(blockers, fmt.Sprintf("needs %s (has %d)", pluralize(req.Approvals, "approval"), approvals))
+	}
+	if defaultApprovals < req.DefaultReviewerApprovals {
+		blockers = append(blockers, fmt.Sprintf("needs %s from default reviewers (has %d)", pluralize(req.DefaultReviewerApprovals, "approval"), defaultApprovals))
+	}
+	if req.NoChangesRequested && changes > 0 {
+		blockers = append(blockers, pluralize(changes, "change request"))
+	}
+	if req.TasksCompleted && pr.TaskCount > 0 {
+		blockers = append(blockers, pluralize(pr.TaskCount, "open task"))
+	}
+
+	var passed, failed, running int
+	for _, s := range statuses {
+		switch s.State {
+		case "SUCCESSFUL":
+			passed++
+		case "FAILED", "STOPPED":
+			failed++
+		case "INPROGRESS":
+			running++
+		}
+	}
+	if failed > 0 {
+		blockers = append(blockers, pluralize(failed, "failed build"))
+	}
+	if running > 0 {
+		blockers = append(blockers, pluralize(running, "build")+" in progress")
+	}
+	if passed < req.PassingBuilds && running == 0 {
+		blockers = append(blockers, fmt.Sprintf("needs %s (has %d)", pluralize(req.PassingBuilds, "passing build"), passed))
+	}
+	return blockers
+}
+
+// buildSettlePeriod is how long merge --auto waits for a first build status
+// when no restriction requires passing builds. Right after a push CI may not
+// have reported yet, and merging then would skip its result.
+const buildSettlePeriod = 2 * time.Minute
+
+// awaitingFirstBuild reports whether merging should wait for a build to be
+// reported, elapsed after polling started. Passing-build requirements already
+// block on missing builds, so only unrestricted branches need the wait.
+func awaitingFirstBuild(statuses []CommitStatus, req mergeRequirements, elapsed time.Duration) bool {
+	return len(statuses) == 0 && req.PassingBuilds == 0 && elapsed < buildSettlePeriod
+}
+
+// hasFailedBuild reports whether a build failed, which waiting will not fix.
+func hasFailedBuild(statuses []CommitStatus) bool {
+	return summarizeBuildStatus(statuses) == "FAILED"
+}
+
+func printMergeBlockers(id int, blockers []string) {
+	if len(blockers) == 0 {
+		output.PrintMessage("Pull request #%d can be merged.", id)
+		return
+	}
+	output.PrintMessage("Pull request #%d cannot be merged yet:", id)
+	for _, b := range blockers {
+		output.PrintMessage("  - %s", b)
+	}
+}
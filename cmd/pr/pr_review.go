@@ -0,0 +1,468 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// reviewCommentPrefix starts the lines of an inline comment in a review.
+const reviewCommentPrefix = ">>"
+
+// review is a code review parsed from a review document: inline comments, an
+// overall comment and a verdict of "approve", "request-changes" or "comment".
+type review struct {
+	Verdict  string
+	Summary  string
+	Comments []reviewComment
+
+	// commentLines holds the indexes of the lines of each comment in the
+	// review document, and summaryLines those of the overall comment.
+	commentLines [][]int
+	summaryLines []int
+	// posted counts the comments submitReview has posted, in order, and
+	// summaryPosted is set once it has posted the overall comment.
+	posted        int
+	summaryPosted bool
+}
+
+// reviewComment is an inline comment of a review. To is the line in the new
+// file; comments on removed lines have From, the line in the old file.
+type reviewComment struct {
+	Path string
+	From int
+	To   int
+	Body string
+}
+
+func (c reviewComment) location() string {
+	if c.To > 0 {
+		return fmt.Sprintf("%s:%d", c.Path, c.To)
+	}
+	return fmt.Sprintf("%s:%d (old)", c.Path, c.From)
+}
+
+func newCmdReview() *cobra.Command {
+	var fromFile string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "review [workspace/repo-slug] <pr-id>",
+		Short: "Review a pull request in your editor",
+		Long: `Review a pull request in your editor. The diff of the pull request opens in
+$VISUAL or $EDITOR; write inline comments on lines starting with ">>" below
+the diff line they are about, and set the verdict (approve, request-changes
+or comment) at the top. Text between the verdict and the diff becomes an
+overall comment.
+
+After a preview, the comments are posted and the pull request is approved
+or marked as needing changes according to the verdict.
+
+To review offline, save the diff with 'bb pr diff', add ">>" lines and a
+"verdict:" line to the file, and submit it with --from-file.`,
+		Example: `  bb pr review 42
+  bb pr diff 42 > review.diff    # add >> comments, then:
+  bb pr review 42 --from-file review.diff`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if fromFile == "" && !cmdutil.CanPrompt() {
+				return &errors.BBError{
+					Message:    "bb pr review needs a terminal to open your editor",
+					Suggestion: fmt.Sprintf("Save the diff with 'bb pr diff %s > review.diff', add \">>\" comments, and run 'bb pr review %s --from-file review.diff'.", args[1], args[1]),
+				}
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			pr, _, err := fetchPullRequest(cmd.Context(), client, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			data, err := client.GetContext(cmd.Context(), fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", args[0], args[1]))
+			if err != nil {
+				return err
+			}
+			diffText := string(data)
+
+			var doc string
+			if fromFile != "" {
+				doc, err = readReviewFile(fromFile)
+			} else {
+				doc, err = cmdutil.EditText(reviewTemplate(pr, diffText))
+			}
+			if err != nil {
+				return err
+			}
+
+			r, err := parseReview(doc, diffText)
+			if err != nil {
+				return keepReviewDraft(doc, fromFile, args[1], err)
+			}
+			if len(r.Comments) == 0 && r.Summary == "" && r.Verdict == "comment" {
+				output.PrintMessage("Nothing to submit; review discarded.")
+				return nil
+			}
+
+			printReviewPreview(pr.ID, r)
+			if !yes && cmdutil.CanPrompt() {
+				ok, err := cmdutil.Confirm("Submit review?", true)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					if fromFile == "" {
+						if path := saveReviewDraft(doc); path != "" {
+							output.PrintMessage("Review cancelled; it was saved to %s.", path)
+							return nil
+						}
+					}
+					output.PrintMessage("Review cancelled.")
+					return nil
+				}
+			}
+
+			if err := submitReview(cmd.Context(), client, args[0], args[1], r); err != nil {
+				return keepUnpostedReview(doc, fromFile, args[1], r, err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&fromFile, "from-file", "F", "", "Submit a review written in a file instead of opening the editor (use - for stdin)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Submit without asking for confirmation")
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// reviewTemplate is the document opened in the editor to review pr.
+func reviewTemplate(pr *PullRequest, diffText string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Review of pull request #%d: %s\n", pr.ID, pr.Title)
+	fmt.Fprintf(&sb, "# %s -> %s\n", pr.Source.Branch.Name, pr.Destination.Branch.Name)
+	sb.WriteString(`#
+# Write inline comments on lines starting with ">>" directly below the diff
+# line they are about; consecutive ">>" lines form one comment. Do not change
+# the diff itself.
+#
+# Set the verdict to approve, request-changes or comment. Text between the
+# verdict and the diff is posted as an overall comment. Lines starting with
+# "#" are ignored. Leave everything as it is to discard the review.
+verdict: comment
+
+`)
+	sb.WriteString(diffText)
+	return sb.String()
+}
+
+var verdictLine = regexp.MustCompile(`(?i)^verdict:\s*(.*?)\s*$`)
+
+// parseReview reads a review document: a header with the verdict and overall
+// comment followed by diffText with inline comments added on ">>" lines.
+func parseReview(doc, diffText string) (*review, error) {
+	lines := strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n")
+	start := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			start = i
+			break
+		}
+	}
+
+	r := &review{Verdict: "comment"}
+	var summary []string
+	for i, line := range lines[:start] {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := verdictLine.FindStringSubmatch(line); m != nil {
+			v, err := parseVerdict(m[1])
+			if err != nil {
+				return nil, err
+			}
+			r.Verdict = v
+			continue
+		}
+		summary = append(summary, line)
+		r.summaryLines = append(r.summaryLines, i)
+	}
+	r.Summary = strings.TrimSpace(strings.Join(summary, "\n"))
+
+	// Separate the comments from the diff, remembering the diff line each
+	// comment follows.
+	var kept []string
+	type pending struct {
+		after   int
+		docLine int
+		lines   []int
+		body    []string
+	}
+	var comments []*pending
+	var last *pending
+	for i, line := range lines[start:] {
+		if !strings.HasPrefix(line, reviewCommentPrefix) {
+			kept = append(kept, line)
+			last = nil
+			continue
+		}
+		text := strings.TrimPrefix(line, reviewCommentPrefix)
+		text = strings.TrimPrefix(text, " ")
+		if last == nil {
+			last = &pending{after: len(kept) - 1, docLine: start + i + 1}
+			comments = append(comments, last)
+		}
+		last.body = append(last.body, text)
+		last.lines = append(last.lines, start+i)
+	}
+
+	if !sameDiff(kept, diffText) {
+		return nil, &errors.BBError{
+			Message:    "The diff in the review does not match the pull request",
+			Suggestion: "Only add lines starting with \">>\" and leave the diff lines unchanged. If the pull request was updated since, start the review again.",
+		}
+	}
+
+	type diffLine struct {
+		file *diff.File
+		line diff.Line
+	}
+	lineAt := make(map[int]diffLine)
+	files := diff.Parse(strings.Join(kept, "\n"))
+	for i := range files {
+		for _, h := range files[i].Hunks {
+			for _, l := range h.Lines {
+				lineAt[l.Index] = diffLine{&files[i], l}
+			}
+		}
+	}
+
+	for _, p := range comments {
+		body := strings.TrimSpace(strings.Join(p.body, "\n"))
+		if body == "" {
+			continue
+		}
+		at, ok := lineAt[p.after]
+		if !ok {
+			return nil, errors.InvalidInput("review", fmt.Sprintf("the comment on line %d is not below a line of the diff", p.docLine))
+		}
+		c := reviewComment{Body: body}
+		if at.line.Kind == diff.Removed {
+			c.Path, c.From = at.file.OldPath, at.line.OldLine
+		} else {
+			c.Path, c.To = at.file.NewPath, at.line.NewLine
+		}
+		r.Comments = append(r.Comments, c)
+		r.commentLines = append(r.commentLines, p.lines)
+	}
+	return r, nil
+}
+
+// parseVerdict normalizes the verdict of a review.
+func parseVerdict(v string) (string, error) {
+	switch strings.ToLower(strings.NewReplacer("_", "-", " ", "-").Replace(v)) {
+	case "", "comment":
+		return "comment", nil
+	case "approve", "approved":
+		return "approve", nil
+	case "request-changes", "changes-requested", "changes":
+		return "request-changes", nil
+	}
+	return "", errors.InvalidInput("verdict", fmt.Sprintf("%q (expected approve, request-changes or comment)", v))
+}
+
+// sameDiff reports whether lines are the lines of diffText, ignoring trailing
+// whitespace, which editors tend to strip.
+func sameDiff(lines []string, diffText string) bool {
+	normalize := func(lines []string) []string {
+		out := make([]string, len(lines))
+		for i, l := range lines {
+			out[i] = strings.TrimRight(l, " \t\r")
+		}
+		for len(out) > 0 && out[len(out)-1] == "" {
+			out = out[:len(out)-1]
+		}
+		return out
+	}
+	a := normalize(lines)
+	b := normalize(strings.Split(strings.ReplaceAll(diffText, "\r\n", "\n"), "\n"))
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func printReviewPreview(id int, r *review) {
+	verdict := map[string]string{
+		"approve":         output.ColorText("approve", "green"),
+		"request-changes": output.ColorText("request changes", "red"),
+		"comment":         "comment only",
+	}[r.Verdict]
+	output.PrintMessage("Review of pull request #%d: %s, %s", id, pluralize(len(r.Comments), "inline comment"), verdict)
+	for _, c := range r.Comments {
+		output.PrintMessage("  %s", c.location())
+		for _, line := range strings.Split(c.Body, "\n") {
+			output.PrintMessage("      %s", line)
+		}
+	}
+	if r.Summary != "" {
+		output.PrintMessage("  Overall comment:")
+		for _, line := range strings.Split(r.Summary, "\n") {
+			output.PrintMessage("      %s", line)
+		}
+	}
+}
+
+// submitReview posts the comments of r and then applies its verdict.
+func submitReview(ctx context.Context, client *api.Client, repoSlug, id string, r *review) error {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s", repoSlug, id)
+	post := func(body map[string]interface{}) error {
+		jsonBody, _ := json.Marshal(body)
+		_, err := client.PostContext(ctx, path+"/comments", string(jsonBody))
+		return err
+	}
+
+	for i, c := range r.Comments {
+		inline := map[string]interface{}{"path": c.Path}
+		if c.To > 0 {
+			inline["to"] = c.To
+		} else {
+			inline["from"] = c.From
+		}
+		err := post(map[string]interface{}{
+			"content": map[string]string{"raw": c.Body},
+			"inline":  inline,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post the comment on %s after posting %s: %w", c.location(), pluralize(i, "comment"), err)
+		}
+		r.posted = i + 1
+	}
+	if r.Summary != "" {
+		if err := post(map[string]interface{}{"content": map[string]string{"raw": r.Summary}}); err != nil {
+			return fmt.Errorf("failed to post the overall comment: %w", err)
+		}
+		r.summaryPosted = true
+	}
+
+	switch r.Verdict {
+	case "approve":
+		if _, err := client.PostContext(ctx, path+"/approve", ""); err != nil {
+			return fmt.Errorf("comments posted, but approving failed: %w", err)
+		}
+		output.PrintMessage("Review submitted: pull request #%s approved.", id)
+	case "request-changes":
+		if _, err := client.PostContext(ctx, path+"/request-changes", ""); err != nil {
+			return fmt.Errorf("comments posted, but requesting changes failed: %w", err)
+		}
+		output.PrintMessage("Review submitted: changes requested on pull request #%s.", id)
+	default:
+		output.PrintMessage("Review submitted to pull request #%s.", id)
+	}
+	return nil
+}
+
+func readReviewFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read review: %w", err)
+	}
+	return string(data), nil
+}
+
+// saveReviewDraft saves a review written in the editor to a temporary file,
+// returning its path, or "" when it could not be saved.
+func saveReviewDraft(doc string) string {
+	f, err := os.CreateTemp("", "bb-review-*.diff")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if _, err := f.WriteString(doc); err != nil {
+		return ""
+	}
+	return f.Name()
+}
+
+// unposted returns doc, the document r was parsed from, without the comments
+// submitReview has already posted, so that submitting it again only posts the
+// rest of the review.
+func (r *review) unposted(doc string) string {
+	drop := make(map[int]bool)
+	for _, lines := range r.commentLines[:r.posted] {
+		for _, i := range lines {
+			drop[i] = true
+		}
+	}
+	if r.summaryPosted {
+		for _, i := range r.summaryLines {
+			drop[i] = true
+		}
+	}
+	var kept []string
+	for i, line := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		if !drop[i] {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// keepUnpostedReview returns cause, which submitting r failed with. When part
+// of the review was posted, the rest is saved, even for --from-file, so that
+// retrying does not post the same comments twice.
+func keepUnpostedReview(doc, fromFile, id string, r *review, cause error) error {
+	if r.posted == 0 && !r.summaryPosted {
+		return keepReviewDraft(doc, fromFile, id, cause)
+	}
+	path := saveReviewDraft(r.unposted(doc))
+	if path == "" {
+		return cause
+	}
+	return &errors.BBError{
+		Message:    cause.Error(),
+		Suggestion: fmt.Sprintf("The rest of your review, without what was already posted, was saved to %s; submit it with 'bb pr review %s --from-file %s'.", path, id, path),
+	}
+}
+
+// keepReviewDraft returns cause, saving a review written in the editor first
+// so that it is not lost when it cannot be submitted.
+func keepReviewDraft(doc, fromFile, id string, cause error) error {
+	if fromFile != "" {
+		return cause
+	}
+	path := saveReviewDraft(doc)
+	if path == "" {
+		return cause
+	}
+	return &errors.BBError{
+		Message:    cause.Error(),
+		Suggestion: fmt.Sprintf("Your review was saved to %s; submit it with 'bb pr review %s --from-file %s'.", path, id, path),
+	}
+}
@@ -0,0 +1,239 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// prReviewer is a reviewer of a pull request along with their review status.
+type prReviewer struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	Status      string `json:"status"`
+}
+
+func newCmdReviewers() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reviewers",
+		Short: "List, add and remove pull request reviewers",
+		Long: `List, add and remove the reviewers of a pull request.
+
+Users can be given by nickname, display name, email address (workspace admins
+only), UUID or "me"; several users are separated by commas.`,
+	}
+
+	cmd.AddCommand(newCmdReviewersList())
+	cmd.AddCommand(newCmdReviewersAdd())
+	cmd.AddCommand(newCmdReviewersRemove())
+	return cmd
+}
+
+func newCmdReviewersList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [workspace/repo-slug] <pr-id>",
+		Short: "List the reviewers of a pull request",
+		Example: `  bb pr reviewers list 42
+  bb pr reviewers list myworkspace/my-repo 42 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			pr, _, err := fetchPullRequest(cmd.Context(), client, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			reviewers := reviewerStatuses(pr)
+			if len(reviewers) == 0 && output.IsTable() {
+				output.PrintMessage("Pull request #%d has no reviewers.", pr.ID)
+				return nil
+			}
+			table := output.NewTable("NAME", "NICKNAME", "STATUS")
+			for _, r := range reviewers {
+				table.AddRow(r.DisplayName, r.Nickname, strings.ToUpper(reviewStatusText(r.Status)))
+			}
+			return output.Render(reviewers, table)
+		},
+	}
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+func newCmdReviewersAdd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [workspace/repo-slug] <pr-id> <users>",
+		Short: "Add reviewers to a pull request",
+		Example: `  bb pr reviewers add 42 ann
+  bb pr reviewers add myworkspace/my-repo 42 "Ann Lee,bob@example.com"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return updateReviewers(cmd.Context(), args[0], args[1], splitUsers(args[2]), true)
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+func newCmdReviewersRemove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [workspace/repo-slug] <pr-id> <users>",
+		Short: "Remove reviewers from a pull request",
+		Example: `  bb pr reviewers remove 42 ann
+  bb pr reviewers remove 42 ann,bob`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return updateReviewers(cmd.Context(), args[0], args[1], splitUsers(args[2]), false)
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+// prArgsCompletion completes the [workspace/repo-slug] <pr-id> arguments.
+func prArgsCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+	}
+	if len(args) == 1 {
+		return completion.PRNumbersWithDescriptions(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// splitUsers splits a comma-separated list of user references.
+func splitUsers(list string) []string {
+	var users []string
+	for _, u := range strings.Split(list, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+// reviewerStatuses returns the reviewers of pr with their review status:
+// "approved", "changes_requested" or "pending".
+func reviewerStatuses(pr *PullRequest) []prReviewer {
+	states := make(map[string]string)
+	for _, p := range pr.Participants {
+		states[p.User.UUID] = p.ReviewState()
+	}
+	reviewers := make([]prReviewer, len(pr.Reviewers))
+	for i, r := range pr.Reviewers {
+		reviewers[i] = prReviewer{UUID: r.UUID, DisplayName: r.DisplayName, Nickname: r.Nickname, Status: "pending"}
+		if s := states[r.UUID]; s != "" {
+			reviewers[i].Status = s
+		}
+	}
+	return reviewers
+}
+
+// reviewStatusText describes a review status for display, for example
+// "changes requested".
+func reviewStatusText(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+// updateReviewers adds users to (or, when add is false, removes them from)
+// the reviewers of a pull request.
+func updateReviewers(ctx context.Context, repoSlug, id string, refs []string, add bool) error {
+	if len(refs) == 0 {
+		return fmt.Errorf("no users specified")
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	pr, _, err := fetchPullRequest(ctx, client, repoSlug, id)
+	if err != nil {
+		return err
+	}
+	workspace := strings.SplitN(repoSlug, "/", 2)[0]
+	users, err := cmdutil.NewUserResolver(client, workspace).ResolveAll(ctx, refs)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool)
+	var uuids []string
+	for _, r := range pr.Reviewers {
+		current[r.UUID] = true
+		uuids = append(uuids, r.UUID)
+	}
+
+	var changed []string
+	if add {
+		for _, u := range users {
+			if !current[u.UUID] {
+				uuids = append(uuids, u.UUID)
+				changed = append(changed, u.Name())
+			}
+		}
+	} else {
+		remove := make(map[string]bool)
+		for _, u := range users {
+			if current[u.UUID] {
+				remove[u.UUID] = true
+				changed = append(changed, u.Name())
+			}
+		}
+		kept := uuids[:0]
+		for _, uuid := range uuids {
+			if !remove[uuid] {
+				kept = append(kept, uuid)
+			}
+		}
+		uuids = kept
+	}
+
+	if len(changed) == 0 {
+		if add {
+			output.PrintMessage("No changes: already reviewers of pull request #%d.", pr.ID)
+		} else {
+			output.PrintMessage("No changes: not reviewers of pull request #%d.", pr.ID)
+		}
+		return nil
+	}
+
+	revList := make([]map[string]string, len(uuids))
+	for i, uuid := range uuids {
+		revList[i] = map[string]string{"uuid": uuid}
+	}
+	jsonBody, _ := json.Marshal(map[string]interface{}{"reviewers": revList})
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s", repoSlug, id)
+	if _, err := client.PutContext(ctx, path, string(jsonBody)); err != nil {
+		return err
+	}
+	if add {
+		output.PrintMessage("Added %s as reviewers of pull request #%d.", strings.Join(changed, ", "), pr.ID)
+	} else {
+		output.PrintMessage("Removed %s from the reviewers of pull request #%d.", strings.Join(changed, ", "), pr.ID)
+	}
+	return nil
+}
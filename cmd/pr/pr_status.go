@@ -0,0 +1,259 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// prStatusItem is one pull request in the pr status summary.
+type prStatusItem struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Author      string `json:"author"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Approvals   int    `json:"approvals"`
+	Changes     int    `json:"changes_requested"`
+	Reviewers   int    `json:"reviewers"`
+	TaskCount   int    `json:"task_count"`
+	BuildStatus string `json:"build_status"`
+	URL         string `json:"url"`
+
+	commit string // source commit hash, used to look up the build status
+}
+
+type prStatus struct {
+	CurrentBranch   string         `json:"current_branch"`
+	CurrentBranchPR []prStatusItem `json:"current_branch_prs"`
+	Created         []prStatusItem `json:"created"`
+	ReviewRequested []prStatusItem `json:"review_requested"`
+}
+
+func newCmdStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [workspace/repo-slug]",
+		Short: "Show pull requests relevant to you",
+		Long: `Summarize the open pull requests for the current branch, those you created
+and those requesting your review, with their approvals, open tasks and the
+build status of their latest commit.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			userUUID, err := currentUserUUID(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			var status prStatus
+			// Outside a checkout (or on a detached HEAD) there is no current
+			// branch; that section is left empty.
+			if branch, err := git.GetCurrentBranch(); err == nil {
+				status.CurrentBranch = branch
+				if status.CurrentBranchPR, err = fetchStatusItems(ctx, client, args[0], "source.branch.name="+bbqlString(branch)); err != nil {
+					return err
+				}
+			}
+			if status.Created, err = fetchStatusItems(ctx, client, args[0], "author.uuid="+bbqlString(userUUID)); err != nil {
+				return err
+			}
+			if status.ReviewRequested, err = fetchStatusItems(ctx, client, args[0], "reviewers.uuid="+bbqlString(userUUID)); err != nil {
+				return err
+			}
+			if err := fillBuildStatuses(ctx, client, args[0], status.CurrentBranchPR, status.Created, status.ReviewRequested); err != nil {
+				return err
+			}
+
+			if !output.IsTable() {
+				return output.Print(status)
+			}
+
+			output.PrintMessage("Relevant pull requests in %s\n", args[0])
+			if status.CurrentBranch != "" {
+				printStatusSection(fmt.Sprintf("Current branch (%s)", status.CurrentBranch), status.CurrentBranchPR,
+					fmt.Sprintf("There is no open pull request for %s.", status.CurrentBranch))
+			}
+			printStatusSection("Created by you", status.Created, "You have no open pull requests.")
+			printStatusSection("Requesting your review", status.ReviewRequested, "You have no pull requests to review.")
+			return nil
+		},
+	}
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.SetRepoArgs(cmd, 0)
+	return cmd
+}
+
+// currentUserUUID returns the UUID of the authenticated user.
+func currentUserUUID(ctx context.Context, client *api.Client) (string, error) {
+	data, err := client.GetContext(ctx, "/user")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch current user: %w", err)
+	}
+	var user struct {
+		UUID string `json:"uuid"`
+	}
+	if err := json.Unmarshal(data, &user); err != nil {
+		return "", err
+	}
+	return user.UUID, nil
+}
+
+// bbqlString quotes s as a BBQL string literal, escaping backslashes and
+// double quotes so branch names cannot break out of the filter.
+func bbqlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// fetchStatusItems returns the open pull requests in repoSlug matching the
+// query filter. Build statuses are filled in later by fillBuildStatuses.
+func fetchStatusItems(ctx context.Context, client *api.Client, repoSlug, filter string) ([]prStatusItem, error) {
+	query := url.QueryEscape(filter + ` AND state="OPEN"`)
+	// Participants and reviewers are left out of list responses unless
+	// requested explicitly.
+	fields := url.QueryEscape("+values.participants,+values.reviewers")
+	path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=25&q=%s&fields=%s", repoSlug, query, fields)
+	prs, err := api.NewPaginator[PullRequest](client, path, 0).Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]prStatusItem, 0, len(prs))
+	for _, pr := range prs {
+		item := prStatusItem{
+			ID:          pr.ID,
+			Title:       pr.Title,
+			Author:      pr.Author.DisplayName,
+			Source:      pr.Source.Branch.Name,
+			Destination: pr.Destination.Branch.Name,
+			Reviewers:   len(pr.Reviewers),
+			TaskCount:   pr.TaskCount,
+			URL:         pr.Links.HTML.Href,
+			commit:      pr.Source.Commit.Hash,
+		}
+		for _, p := range pr.Participants {
+			switch p.ReviewState() {
+			case "approved":
+				item.Approvals++
+			case "changes_requested":
+				item.Changes++
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// statusFetchWorkers bounds the number of concurrent commit status requests.
+const statusFetchWorkers = 4
+
+// fillBuildStatuses sets the build status of every item from its source
+// commit's statuses. Each commit is fetched once, even when its pull request
+// appears in several sections, and the requests run concurrently.
+func fillBuildStatuses(ctx context.Context, client *api.Client, repoSlug string, sections ...[]prStatusItem) error {
+	var hashes []string
+	seen := make(map[string]bool)
+	for _, items := range sections {
+		for _, item := range items {
+			if item.commit != "" && !seen[item.commit] {
+				seen[item.commit] = true
+				hashes = append(hashes, item.commit)
+			}
+		}
+	}
+
+	summaries := make([]string, len(hashes))
+	errs := make([]error, len(hashes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(statusFetchWorkers, len(hashes)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				statuses, _, err := fetchCommitStatuses(ctx, client, repoSlug, hashes[i])
+				summaries[i], errs[i] = summarizeBuildStatus(statuses), err
+			}
+		}()
+	}
+	for i := range hashes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	byHash := make(map[string]string, len(hashes))
+	for i, hash := range hashes {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		byHash[hash] = summaries[i]
+	}
+	for _, items := range sections {
+		for i := range items {
+			items[i].BuildStatus = byHash[items[i].commit]
+		}
+	}
+	return nil
+}
+
+func printStatusSection(title string, items []prStatusItem, empty string) {
+	output.PrintMessage("%s", title)
+	if len(items) == 0 {
+		output.PrintMessage("  %s\n", empty)
+		return
+	}
+	for _, item := range items {
+		output.PrintMessage("  #%d  %s [%s]", item.ID, output.Truncate(item.Title, 60), item.Source)
+		details := []string{fmt.Sprintf("%d/%d approved", item.Approvals, item.Reviewers)}
+		if item.Changes > 0 {
+			details = append(details, output.ColorText(pluralize(item.Changes, "change request"), "red"))
+		}
+		details = append(details, pluralize(item.TaskCount, "open task"), buildStatusText(item.BuildStatus))
+		output.PrintMessage("      %s", strings.Join(details, " · "))
+	}
+	output.PrintMessage("")
+}
+
+// buildStatusText describes a summarized build status, colored by outcome.
+func buildStatusText(state string) string {
+	switch state {
+	case "SUCCESSFUL":
+		return output.ColorText("build passed", "green")
+	case "FAILED":
+		return output.ColorText("build failed", "red")
+	case "INPROGRESS":
+		return output.ColorText("build in progress", "yellow")
+	case "":
+		return output.ColorText("no builds", "gray")
+	}
+	return "build " + strings.ToLower(state)
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
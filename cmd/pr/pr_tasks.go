@@ -0,0 +1,287 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// Task is a pull request task, a to-do item that reviewers expect to be
+// done before merging.
+type Task struct {
+	ID      int    `json:"id"`
+	State   string `json:"state"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	Creator struct {
+		DisplayName string `json:"display_name"`
+	} `json:"creator"`
+	Comment *struct {
+		ID int `json:"id"`
+	} `json:"comment"`
+	CreatedOn  string `json:"created_on"`
+	ResolvedOn string `json:"resolved_on"`
+}
+
+func newCmdTasks() *cobra.Command {
+	var state string
+	var pagination cmdutil.PaginationOptions
+
+	cmd := &cobra.Command{
+		Use:   "tasks [workspace/repo-slug] <pr-id>",
+		Short: "List the tasks of a pull request",
+		Example: `  bb pr tasks 42
+  bb pr tasks 42 --state open`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks?pagelen=50&page=%d", args[0], args[1], pagination.Page)
+			if state != "" {
+				s, err := taskStateFilter(state)
+				if err != nil {
+					return err
+				}
+				path += "&q=" + url.QueryEscape(fmt.Sprintf(`state="%s"`, s))
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			tasks, raw, _, err := cmdutil.FetchListWithRaw[Task](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
+			}
+
+			if len(tasks) == 0 && output.IsTable() {
+				output.PrintMessage("No tasks on PR #%s.", args[1])
+				return nil
+			}
+			table := output.NewTable("ID", "STATE", "TASK", "CREATOR", "COMMENT")
+			for _, t := range tasks {
+				comment := ""
+				if t.Comment != nil {
+					comment = fmt.Sprintf("#%d", t.Comment.ID)
+				}
+				table.AddRow(fmt.Sprintf("%d", t.ID), taskStateText(t.State), output.Truncate(t.Content.Raw, 60), t.Creator.DisplayName, comment)
+			}
+			return output.Render(output.WithRaw(tasks, raw), table)
+		},
+	}
+	cmd.Flags().StringVarP(&state, "state", "s", "", "Filter by state (open, resolved)")
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+func newCmdTask() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: "Create, resolve, reopen and delete pull request tasks",
+		Long: `Create, resolve, reopen and delete pull request tasks. List them with
+'bb pr tasks'.`,
+	}
+
+	cmd.AddCommand(newCmdTaskCreate())
+	cmd.AddCommand(newCmdTaskState("resolve", "Resolve a pull request task", "RESOLVED"))
+	cmd.AddCommand(newCmdTaskState("reopen", "Reopen a resolved pull request task", "UNRESOLVED"))
+	cmd.AddCommand(newCmdTaskDelete())
+	return cmd
+}
+
+func newCmdTaskCreate() *cobra.Command {
+	var body string
+	var bodyFile string
+	var useEditor bool
+	var commentID int
+
+	cmd := &cobra.Command{
+		Use:   "create [workspace/repo-slug] <pr-id>",
+		Short: "Create a task on a pull request",
+		Example: `  bb pr task create 42 --body "Add tests for the retry path"
+  bb pr task create 42 --body "Rename this" --comment 1234`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			content, err := cmdutil.ResolveBody(
+				body, bodyFile, useEditor,
+				cmd.Flags().Changed("body"),
+				cmd.Flags().Changed("body-file"),
+				cmd.Flags().Changed("editor"),
+			)
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			reqBody := map[string]interface{}{
+				"content": map[string]string{"raw": strings.TrimSpace(content)},
+			}
+			if cmd.Flags().Changed("comment") {
+				reqBody["comment"] = map[string]int{"id": commentID}
+			}
+			jsonBody, _ := json.Marshal(reqBody)
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks", args[0], args[1])
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
+			if err != nil {
+				return err
+			}
+
+			var task Task
+			if err := json.Unmarshal(data, &task); err != nil {
+				return err
+			}
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(task, json.RawMessage(data)))
+			}
+			output.PrintMessage("Task #%d created on PR #%s.", task.ID, args[1])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&body, "body", "b", "", "Task description")
+	cmd.Flags().StringVarP(&bodyFile, "body-file", "F", "", "Read description from file (use - for stdin)")
+	cmd.Flags().BoolVarP(&useEditor, "editor", "e", false, "Open editor to compose the task")
+	cmd.Flags().IntVarP(&commentID, "comment", "c", 0, "ID of the comment to attach the task to")
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+// newCmdTaskState returns a command that moves a task to state.
+func newCmdTaskState(name, short, state string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name + " [workspace/repo-slug] <pr-id> <task-id>",
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := validateID("task-id", args[2]); err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			jsonBody, _ := json.Marshal(map[string]string{"state": state})
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks/%s", args[0], args[1], args[2])
+			if _, err := client.PutContext(cmd.Context(), path, string(jsonBody)); err != nil {
+				return err
+			}
+			if state == "RESOLVED" {
+				output.PrintMessage("Task #%s on PR #%s resolved.", args[2], args[1])
+			} else {
+				output.PrintMessage("Task #%s on PR #%s reopened.", args[2], args[1])
+			}
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+func newCmdTaskDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [workspace/repo-slug] <pr-id> <task-id>",
+		Short: "Delete a pull request task",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := validateID("task-id", args[2]); err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks/%s", args[0], args[1], args[2])
+			if _, err := client.DeleteContext(cmd.Context(), path); err != nil {
+				return err
+			}
+			output.PrintMessage("Task #%s deleted from PR #%s.", args[2], args[1])
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = prArgsCompletion
+	cmdutil.SetRepoArgs(cmd, 2)
+	return cmd
+}
+
+// fetchTasks returns every task of a pull request.
+func fetchTasks(ctx context.Context, client *api.Client, repoSlug, id string) ([]Task, error) {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks?pagelen=100", repoSlug, id)
+	return api.GetAll[Task](ctx, client, path, 0)
+}
+
+// tasksSummary counts tasks by state, for example "2 open, 1 resolved".
+func tasksSummary(tasks []Task) string {
+	if len(tasks) == 0 {
+		return "none"
+	}
+	var open, resolved int
+	for _, t := range tasks {
+		if t.State == "RESOLVED" {
+			resolved++
+		} else {
+			open++
+		}
+	}
+	return fmt.Sprintf("%d open, %d resolved", open, resolved)
+}
+
+// taskStateFilter maps a --state value to the API's task state.
+func taskStateFilter(state string) (string, error) {
+	switch strings.ToLower(state) {
+	case "open", "unresolved":
+		return "UNRESOLVED", nil
+	case "resolved":
+		return "RESOLVED", nil
+	}
+	return "", errors.InvalidInput("state", fmt.Sprintf("%q (expected open or resolved)", state))
+}
+
+// taskStateText is the state shown for a task in tables.
+func taskStateText(state string) string {
+	if state == "RESOLVED" {
+		return "RESOLVED"
+	}
+	return "OPEN"
+}
+
+// validateID checks that the argument field is a numeric ID, so it can be
+// used safely in an API path.
+func validateID(field, id string) error {
+	if _, err := strconv.Atoi(id); err != nil {
+		return errors.InvalidInput(field, fmt.Sprintf("%q is not a number", id))
+	}
+	return nil
+}
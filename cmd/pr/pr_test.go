@@ -1,9 +1,27 @@
 package pr
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/comments"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
 )
 
 func TestNewCmdPR_HasSubcommands(t *testing.T) {
@@ -23,6 +41,16 @@ func TestNewCmdPR_HasSubcommands(t *testing.T) {
 		"diff":     false,
 		"activity": false,
 		"edit":     false,
+		"checkout": false,
+		"status":   false,
+		"checks":   false,
+		"ready":    false,
+		"reviewers": false,
+		"request-changes": false,
+		"tasks":    false,
+		"task":     false,
+		"review":   false,
+		"apply":    false,
 	}
 
 	for _, sub := range subcommands {
@@ -66,7 +94,7 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	expectedFlags := []string{"state", "page", "json"}
+	expectedFlags := []string{"state", "page", "all", "limit", "json"}
 	for _, name := range expectedFlags {
 		if listCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on list command", name)
@@ -121,7 +149,7 @@ func TestNewCmdCreate_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdCreate_TitleRequired(t *testing.T) {
+func TestNewCmdCreate_TitleOptional(t *testing.T) {
 	cmd := NewCmdPR()
 	createCmd, _, err := cmd.Find([]string{"create"})
 	if err != nil {
@@ -133,10 +161,59 @@ func TestNewCmdCreate_TitleRequired(t *testing.T) {
 		t.Fatal("title flag not found")
 	}
 
-	// Check that title is required
-	annotations := flag.Annotations
-	if annotations == nil {
-		t.Error("title flag should be required but has no annotations")
+	// The title is prompted for or filled from commits when omitted
+	if _, ok := flag.Annotations[cobra.BashCompOneRequiredFlag]; ok {
+		t.Error("title flag should not be required")
+	}
+	for _, name := range []string{"fill", "editor", "description-file"} {
+		if createCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag %q not found", name)
+		}
+	}
+}
+
+func TestFillFromCommits(t *testing.T) {
+	single := []git.Commit{{Subject: "Fix login redirect", Body: "The redirect dropped the query."}}
+	title, desc := fillFromCommits("bugfix/login", single)
+	if title != "Fix login redirect" || desc != "The redirect dropped the query." {
+		t.Errorf("fillFromCommits(single) = %q, %q", title, desc)
+	}
+
+	several := []git.Commit{{Subject: "Add form"}, {Subject: "Add validation"}}
+	title, desc = fillFromCommits("feature/signup-form", several)
+	if title != "Signup form" {
+		t.Errorf("fillFromCommits(several) title = %q, want %q", title, "Signup form")
+	}
+	if desc != "- Add form\n- Add validation\n" {
+		t.Errorf("fillFromCommits(several) description = %q", desc)
+	}
+
+	if title, _ := fillFromCommits("my_branch", nil); title != "My branch" {
+		t.Errorf("fillFromCommits(none) title = %q, want %q", title, "My branch")
+	}
+}
+
+func TestFindPRTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	if out, err := exec.Command("git", "init", tmpDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".bitbucket", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(tmpDir, ".bitbucket", "sub")); err != nil {
+		t.Fatalf("failed to change dir: %v", err)
+	}
+	if got := findPRTemplate(); got != "" {
+		t.Errorf("findPRTemplate() = %q, want none", got)
+	}
+
+	tmpl := "## Summary\n\n## Testing\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".bitbucket", "pull_request_template.md"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := findPRTemplate(); got != tmpl {
+		t.Errorf("findPRTemplate() = %q, want %q", got, tmpl)
 	}
 }
 
@@ -275,16 +352,21 @@ func TestNewCmdComment_BodyFlagShorthand(t *testing.T) {
 	}
 }
 
-func TestNewCmdDiff_NoFlags(t *testing.T) {
+func TestNewCmdDiff_HasExpectedFlags(t *testing.T) {
 	cmd := NewCmdPR()
 	diffCmd, _, err := cmd.Find([]string{"diff"})
 	if err != nil {
 		t.Fatalf("failed to find diff command: %v", err)
 	}
 
-	// diff should have no custom local flags (only inherited ones)
-	if diffCmd.LocalFlags().HasFlags() {
-		t.Error("diff command should have no custom local flags")
+	expectedFlags := []string{"stat", "name-only", "side-by-side", "path", "no-pager", "patch"}
+	for _, name := range expectedFlags {
+		if diffCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s not found on diff command", name)
+		}
+	}
+	if diffCmd.Flags().Lookup("json") != nil {
+		t.Error("diff command should not have --json flag")
 	}
 }
 
@@ -447,7 +529,13 @@ func TestAllSubcommands_HaveRunE(t *testing.T) {
 	cmd := NewCmdPR()
 	subcommands := cmd.Commands()
 
-	for _, sub := range subcommands {
+	for i := 0; i < len(subcommands); i++ {
+		sub := subcommands[i]
+		// Command groups such as "reviewers" run through their subcommands
+		if sub.HasSubCommands() {
+			subcommands = append(subcommands, sub.Commands()...)
+			continue
+		}
 		if sub.RunE == nil {
 			t.Errorf("subcommand %q has no RunE function", sub.Name())
 		}
@@ -657,8 +745,8 @@ func TestSubcommands_Count(t *testing.T) {
 	cmd := NewCmdPR()
 	subcommands := cmd.Commands()
 
-	if len(subcommands) != 12 {
-		t.Errorf("expected 12 subcommands, got %d", len(subcommands))
+	if len(subcommands) != 22 {
+		t.Errorf("expected 22 subcommands, got %d", len(subcommands))
 	}
 }
 
@@ -680,6 +768,16 @@ func TestAllCommandConstructors(t *testing.T) {
 		{"diff", newCmdDiff},
 		{"activity", newCmdActivity},
 		{"edit", newCmdEdit},
+		{"checkout", newCmdCheckout},
+		{"status", newCmdStatus},
+		{"checks", newCmdChecks},
+		{"ready", newCmdReady},
+		{"reviewers", newCmdReviewers},
+		{"request-changes", newCmdRequestChanges},
+		{"tasks", newCmdTasks},
+		{"task", newCmdTask},
+		{"review", newCmdReview},
+		{"apply", newCmdApply},
 	}
 
 	for _, tt := range tests {
@@ -741,6 +839,42 @@ func TestNewCmdCreate_CloseBranchFlag(t *testing.T) {
 	}
 }
 
+func TestDraftFlags(t *testing.T) {
+	for name, cmd := range map[string]*cobra.Command{
+		"create": newCmdCreate(),
+		"edit":   newCmdEdit(),
+		"list":   newCmdList(),
+	} {
+		flag := cmd.Flags().Lookup("draft")
+		if flag == nil {
+			t.Errorf("%s: draft flag not found", name)
+		} else if flag.DefValue != "false" {
+			t.Errorf("%s: expected draft default false, got %s", name, flag.DefValue)
+		}
+	}
+	if newCmdReady().Flags().Lookup("undo") == nil {
+		t.Error("ready: undo flag not found")
+	}
+}
+
+func TestDisplayState(t *testing.T) {
+	tests := []struct {
+		state string
+		draft bool
+		want  string
+	}{
+		{"OPEN", false, "OPEN"},
+		{"OPEN", true, "DRAFT"},
+		{"MERGED", true, "MERGED"},
+	}
+	for _, tt := range tests {
+		pr := PullRequest{State: tt.state, Draft: tt.draft}
+		if got := displayState(pr); got != tt.want {
+			t.Errorf("displayState(%s, draft=%t) = %s, want %s", tt.state, tt.draft, got, tt.want)
+		}
+	}
+}
+
 func TestNewCmdMerge_StrategyFlag(t *testing.T) {
 	cmd := newCmdMerge()
 	flag := cmd.Flags().Lookup("strategy")
@@ -784,3 +918,851 @@ func TestNewCmdDiff_Use(t *testing.T) {
 		t.Error("diff command should have Use field set")
 	}
 }
+
+func TestCheckoutBranchName(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "init"},
+		{"branch", "feature"},
+		{"branch", "tracked"},
+		{"config", "branch.tracked.remote", "origin"},
+		{"config", "branch.tracked.merge", "refs/heads/tracked"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		requested  string
+		branch     string
+		force      bool
+		want       string
+		wantExists bool
+		wantErr    bool
+	}{
+		{"new branch", "", "fresh", false, "fresh", false, false},
+		{"tracking branch", "", "tracked", false, "tracked", true, false},
+		{"collision", "", "feature", false, "pr-7-feature", false, false},
+		{"requested collision", "feature", "other", false, "", false, true},
+		{"forced", "feature", "other", true, "feature", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exists, err := checkoutBranchName(tt.requested, 7, "origin", tt.branch, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkoutBranchName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || exists != tt.wantExists {
+				t.Errorf("checkoutBranchName() = %q, %v, want %q, %v", got, exists, tt.want, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestSummarizeBuildStatus(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"SUCCESSFUL", "SUCCESSFUL"}, "SUCCESSFUL"},
+		{[]string{"SUCCESSFUL", "INPROGRESS"}, "INPROGRESS"},
+		{[]string{"INPROGRESS", "FAILED", "SUCCESSFUL"}, "FAILED"},
+		{[]string{"STOPPED"}, "FAILED"},
+	}
+	for _, tt := range tests {
+		statuses := make([]CommitStatus, len(tt.states))
+		for i, s := range tt.states {
+			statuses[i].State = s
+		}
+		if got := summarizeBuildStatus(statuses); got != tt.want {
+			t.Errorf("summarizeBuildStatus(%v) = %q, want %q", tt.states, got, tt.want)
+		}
+	}
+}
+
+func TestChecksFinished(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   bool
+	}{
+		{nil, false},
+		{[]string{"INPROGRESS", "SUCCESSFUL"}, false},
+		{[]string{"SUCCESSFUL"}, true},
+		{[]string{"FAILED", "SUCCESSFUL"}, true},
+	}
+	for _, tt := range tests {
+		statuses := make([]CommitStatus, len(tt.states))
+		for i, s := range tt.states {
+			statuses[i].State = s
+		}
+		if got := checksFinished(statuses); got != tt.want {
+			t.Errorf("checksFinished(%v) = %v, want %v", tt.states, got, tt.want)
+		}
+	}
+}
+
+func TestNewCmdStatus_JSONFlag(t *testing.T) {
+	cmd := newCmdStatus()
+	if cmd.Flags().Lookup("json") == nil {
+		t.Error("expected --json flag on status command")
+	}
+}
+
+func TestBBQLString(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"feature/login", `"feature/login"`},
+		{`say-"hi"`, `"say-\"hi\""`},
+		{`back\slash`, `"back\\slash"`},
+	}
+	for _, tt := range tests {
+		if got := bbqlString(tt.in); got != tt.want {
+			t.Errorf("bbqlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFillBuildStatuses_FetchesEachCommitOnce(t *testing.T) {
+	t.Setenv("BB_API_URL", "")
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/repositories/ws/repo/commit/aaa/statuses":
+			w.Write([]byte(`{"values":[{"state":"SUCCESSFUL"}]}`))
+		case "/repositories/ws/repo/commit/bbb/statuses":
+			w.Write([]byte(`{"values":[{"state":"FAILED"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.Client(), &config.Config{APIURL: server.URL}, &config.TokenData{AccessToken: "token"})
+
+	current := []prStatusItem{{ID: 1, commit: "aaa"}}
+	created := []prStatusItem{{ID: 1, commit: "aaa"}, {ID: 2, commit: "bbb"}, {ID: 3}}
+	review := []prStatusItem{{ID: 2, commit: "bbb"}}
+	if err := fillBuildStatuses(context.Background(), client, "ws/repo", current, created, review); err != nil {
+		t.Fatalf("fillBuildStatuses() error: %v", err)
+	}
+
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("%s requested %d times, want 1", path, n)
+		}
+	}
+	if len(requests) != 2 {
+		t.Errorf("got %d status requests, want 2", len(requests))
+	}
+	got := []string{current[0].BuildStatus, created[0].BuildStatus, created[1].BuildStatus, created[2].BuildStatus, review[0].BuildStatus}
+	want := []string{"SUCCESSFUL", "SUCCESSFUL", "FAILED", "", "FAILED"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("build statuses = %q, want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestNewCmdChecks_HasExpectedFlags(t *testing.T) {
+	cmd := newCmdChecks()
+	for _, name := range []string{"watch", "interval", "timeout", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag %q not found", name)
+		}
+	}
+}
+
+func TestChecksSummary(t *testing.T) {
+	statuses := []CommitStatus{{State: "SUCCESSFUL"}, {State: "FAILED"}, {State: "SUCCESSFUL"}, {State: "INPROGRESS"}}
+	if got, want := checksSummary(statuses), "2 passed, 1 failed, 1 in progress"; got != want {
+		t.Errorf("checksSummary() = %q, want %q", got, want)
+	}
+	if got := checksSummary(nil); got != "none" {
+		t.Errorf("checksSummary(nil) = %q, want none", got)
+	}
+}
+
+func TestCheckDuration(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 10, 0, 0, time.UTC)
+	tests := []struct {
+		status CommitStatus
+		want   string
+	}{
+		{CommitStatus{State: "SUCCESSFUL", CreatedOn: "2024-05-01T12:00:00.123456+00:00", UpdatedOn: "2024-05-01T12:01:30.5+00:00"}, "1m30s"},
+		{CommitStatus{State: "INPROGRESS", CreatedOn: "2024-05-01T12:05:00Z"}, "5m0s"},
+		{CommitStatus{State: "FAILED"}, "-"},
+	}
+	for _, tt := range tests {
+		if got := checkDuration(tt.status, now); got != tt.want {
+			t.Errorf("checkDuration(%+v) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestEnsurePushed(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BB_GIT_REMOTE", "")
+	remoteDir := t.TempDir()
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	gitCmd := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitCmd("init", "--bare", remoteDir)
+	gitCmd("init")
+	gitCmd("checkout", "-b", "feature")
+	gitCmd("commit", "--allow-empty", "-m", "first")
+	gitCmd("remote", "add", "origin", remoteDir)
+
+	if err := ensurePushed("ws/repo", "feature", false, false); err == nil {
+		t.Fatal("ensurePushed() expected an error for a branch missing from the remote")
+	}
+	if err := ensurePushed("ws/repo", "feature", true, false); err != nil {
+		t.Fatalf("ensurePushed() with push error: %v", err)
+	}
+	if ok, err := git.RemoteBranchExists("origin", "feature"); !ok || err != nil {
+		t.Errorf("expected feature to be pushed to origin, got %v, %v", ok, err)
+	}
+
+	gitCmd("commit", "--allow-empty", "-m", "second")
+	if err := ensurePushed("ws/repo", "feature", false, false); err != nil {
+		t.Errorf("ensurePushed() with unpushed commits should only warn, got %v", err)
+	}
+	if err := ensurePushed("ws/repo", "feature", true, false); err != nil {
+		t.Fatalf("ensurePushed() with push error: %v", err)
+	}
+	if n, err := git.CommitsAhead("origin/feature", "feature"); err != nil || n != 0 {
+		t.Errorf("CommitsAhead() after push = %d, %v, want 0", n, err)
+	}
+	if err := ensurePushed("ws/repo", "remote-only", false, false); err != nil {
+		t.Errorf("ensurePushed() for a branch missing locally = %v, want nil", err)
+	}
+
+	// Someone else deleted the branch; the local origin/feature is stale.
+	gitCmd("--git-dir", remoteDir, "branch", "-D", "feature")
+	if err := ensurePushed("ws/repo", "feature", false, false); err == nil {
+		t.Error("ensurePushed() expected an error for a branch deleted from the remote")
+	}
+}
+
+func TestNewCmdReviewers_Subcommands(t *testing.T) {
+	cmd := newCmdReviewers()
+	for _, name := range []string{"list", "add", "remove"} {
+		sub, _, err := cmd.Find([]string{name})
+		if err != nil || sub.Name() != name {
+			t.Errorf("expected subcommand %q", name)
+		}
+	}
+	add, _, _ := cmd.Find([]string{"add"})
+	if err := add.Args(add, []string{"42"}); err == nil {
+		t.Error("reviewers add should require a pull request and users")
+	}
+	if err := add.Args(add, []string{"ws/repo", "42", "ann"}); err != nil {
+		t.Errorf("reviewers add with a repository: %v", err)
+	}
+}
+
+func TestSplitUsers(t *testing.T) {
+	got := splitUsers(" ann, Bob Smith,,bob@example.com ")
+	want := []string{"ann", "Bob Smith", "bob@example.com"}
+	if len(got) != len(want) {
+		t.Fatalf("splitUsers() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("splitUsers()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReviewerStatuses(t *testing.T) {
+	var pr PullRequest
+	data := `{"reviewers":[{"uuid":"{a}","display_name":"Ann"},{"uuid":"{b}","display_name":"Bob"},{"uuid":"{d}","display_name":"Dee"}],
+		"participants":[{"user":{"uuid":"{a}"},"role":"REVIEWER","approved":true},
+			{"user":{"uuid":"{b}"},"role":"REVIEWER","approved":false,"state":"changes_requested"},
+			{"user":{"uuid":"{c}"},"role":"PARTICIPANT","approved":true}]}`
+	if err := json.Unmarshal([]byte(data), &pr); err != nil {
+		t.Fatal(err)
+	}
+	got := reviewerStatuses(&pr)
+	want := []string{"approved", "changes_requested", "pending"}
+	if len(got) != len(want) {
+		t.Fatalf("reviewerStatuses() = %+v, want %d reviewers", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Status != w {
+			t.Errorf("reviewerStatuses()[%d].Status = %q, want %q", i, got[i].Status, w)
+		}
+	}
+	if s := reviewStatusText(got[1].Status); s != "changes requested" {
+		t.Errorf("reviewStatusText() = %q, want %q", s, "changes requested")
+	}
+}
+
+func TestNewCmdRequestChanges_UndoFlag(t *testing.T) {
+	cmd := newCmdRequestChanges()
+	flag := cmd.Flags().Lookup("undo")
+	if flag == nil {
+		t.Fatal("undo flag not found")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("expected undo default false, got %s", flag.DefValue)
+	}
+}
+
+func TestNewCmdMerge_AutoFlags(t *testing.T) {
+	cmd := newCmdMerge()
+	for _, name := range []string{"auto", "dry-run", "interval", "timeout"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag %q not found", name)
+		}
+	}
+	if def := cmd.Flags().Lookup("timeout").DefValue; def != "1h0m0s" {
+		t.Errorf("expected timeout default 1h0m0s, got %s", def)
+	}
+	cmd.SetArgs([]string{"ws/repo", "1", "--auto", "--dry-run"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected --auto and --dry-run to be mutually exclusive")
+	}
+}
+
+func TestWatchCommands_RejectZeroInterval(t *testing.T) {
+	tests := []struct {
+		cmd  *cobra.Command
+		args []string
+	}{
+		{newCmdMerge(), []string{"ws/repo", "1", "--auto", "--interval", "0"}},
+		{newCmdChecks(), []string{"ws/repo", "1", "--watch", "--interval", "0"}},
+	}
+	for _, tt := range tests {
+		tt.cmd.SetArgs(tt.args)
+		tt.cmd.SilenceErrors = true
+		tt.cmd.SilenceUsage = true
+		if err := tt.cmd.Execute(); err == nil || !strings.Contains(err.Error(), "interval") {
+			t.Errorf("%s %v: error = %v, want invalid interval", tt.cmd.Name(), tt.args, err)
+		}
+	}
+}
+
+func TestWatchCommands_RejectNonPositiveTimeout(t *testing.T) {
+	tests := []struct {
+		newCmd func() *cobra.Command
+		args   []string
+	}{
+		{newCmdMerge, []string{"ws/repo", "1", "--auto"}},
+		{newCmdChecks, []string{"ws/repo", "1", "--watch"}},
+	}
+	for _, tt := range tests {
+		for _, timeout := range []string{"0", "-5m"} {
+			cmd := tt.newCmd()
+			cmd.SetArgs(append(tt.args, "--timeout", timeout))
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "timeout") {
+				t.Errorf("%s --timeout %s: error = %v, want invalid timeout", cmd.Name(), timeout, err)
+			}
+		}
+	}
+}
+
+func TestRequirementsFor(t *testing.T) {
+	two := 2
+	restrictions := []branchRestriction{
+		{Kind: "require_approvals_to_merge", BranchMatchKind: "glob", Pattern: "main", Value: &two},
+		{Kind: "require_passing_builds_to_merge", BranchMatchKind: "glob", Pattern: "*"},
+		{Kind: "require_tasks_to_be_completed", BranchMatchKind: "glob", Pattern: "release/*"},
+		{Kind: "require_no_changes_requested", BranchMatchKind: "branching_model", BranchType: "production"},
+		{Kind: "require_default_reviewer_approvals_to_merge", BranchMatchKind: "branching_model", BranchType: "release"},
+		{Kind: "push", BranchMatchKind: "glob", Pattern: "main"},
+		{Kind: "push", BranchMatchKind: "branching_model", BranchType: "development"},
+	}
+	var model branchingModel
+	if err := json.Unmarshal([]byte(`{
+		"development": {"branch": {"name": "develop"}},
+		"production": {"branch": {"name": "main"}},
+		"branch_types": [{"kind": "release", "prefix": "release/"}, {"kind": "feature", "prefix": "feature/"}]
+	}`), &model); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		branch string
+		model  *branchingModel
+		want   mergeRequirements
+	}{
+		{"main", &model, mergeRequirements{Approvals: 2, PassingBuilds: 1, NoChangesRequested: true}},
+		{"release/1.0", &model, mergeRequirements{PassingBuilds: 1, TasksCompleted: true, DefaultReviewerApprovals: 1}},
+		{"develop", &model, mergeRequirements{PassingBuilds: 1}},
+		{"main", nil, mergeRequirements{Approvals: 2, PassingBuilds: 1, Unchecked: []string{
+			"require_no_changes_requested on production branches",
+			"require_default_reviewer_approvals_to_merge on release branches",
+		}}},
+	}
+	for _, tt := range tests {
+		if got := requirementsFor(restrictions, tt.branch, tt.model); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("requirementsFor(%s, model=%v) = %+v, want %+v", tt.branch, tt.model != nil, got, tt.want)
+		}
+	}
+}
+
+func TestFetchMergeRequirements(t *testing.T) {
+	t.Setenv("BB_API_URL", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/repo/branch-restrictions":
+			w.Write([]byte(`{"values":[
+				{"kind":"require_default_reviewer_approvals_to_merge","branch_match_kind":"branching_model","branch_type":"development","value":1}
+			]}`))
+		case "/repositories/ws/repo/effective-branching-model":
+			w.Write([]byte(`{"development":{"branch":{"name":"develop"}}}`))
+		case "/repositories/ws/repo/effective-default-reviewers":
+			w.Write([]byte(`{"values":[{"user":{"uuid":"{ann}"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.Client(), &config.Config{APIURL: server.URL}, &config.TokenData{AccessToken: "token"})
+
+	req, err := fetchMergeRequirements(context.Background(), client, "ws/repo", "develop")
+	if err != nil {
+		t.Fatalf("fetchMergeRequirements() error: %v", err)
+	}
+	want := mergeRequirements{DefaultReviewerApprovals: 1, DefaultReviewers: map[string]bool{"{ann}": true}}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("fetchMergeRequirements() = %+v, want %+v", req, want)
+	}
+}
+
+func TestMatchBranchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, branch string
+		want            bool
+	}{
+		{"main", "main", true},
+		{"main", "maintenance", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "hotfix/1.0", false},
+		{"*", "feature/a/b", true},
+		{"v1.?", "v1.x", false},
+	}
+	for _, tt := range tests {
+		if got := matchBranchPattern(tt.pattern, tt.branch); got != tt.want {
+			t.Errorf("matchBranchPattern(%q, %q) = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestMergeBlockers(t *testing.T) {
+	var pr PullRequest
+	data := `{"id":1,"state":"OPEN","task_count":1,
+		"participants":[{"user":{"uuid":"{a}"},"approved":true},{"user":{"uuid":"{b}"},"state":"changes_requested"}]}`
+	if err := json.Unmarshal([]byte(data), &pr); err != nil {
+		t.Fatal(err)
+	}
+	statuses := []CommitStatus{{State: "SUCCESSFUL"}, {State: "INPROGRESS"}}
+
+	if got := mergeBlockers(&pr, nil, mergeRequirements{}); len(got) != 0 {
+		t.Errorf("mergeBlockers() without requirements = %q, want none", got)
+	}
+
+	got := mergeBlockers(&pr, statuses, mergeRequirements{Approvals: 2, PassingBuilds: 2, TasksCompleted: true, NoChangesRequested: true})
+	want := []string{"needs 2 approvals (has 1)", "1 change request", "1 open task", "1 build in progress"}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("mergeBlockers() = %q, want %q", got, want)
+	}
+
+	statuses[1].State = "FAILED"
+	if got := mergeBlockers(&pr, statuses, mergeRequirements{}); len(got) != 1 || got[0] != "1 failed build" {
+		t.Errorf("mergeBlockers() with a failed build = %q", got)
+	}
+	if !hasFailedBuild(statuses) {
+		t.Error("hasFailedBuild() = false, want true")
+	}
+
+	// Only approvals from default reviewers count towards that requirement.
+	req := mergeRequirements{DefaultReviewerApprovals: 1, DefaultReviewers: map[string]bool{"{b}": true}}
+	if got := mergeBlockers(&pr, nil, req); len(got) != 1 || got[0] != "needs 1 approval from default reviewers (has 0)" {
+		t.Errorf("mergeBlockers() without a default reviewer approval = %q", got)
+	}
+	req.DefaultReviewers["{a}"] = true
+	if got := mergeBlockers(&pr, nil, req); len(got) != 0 {
+		t.Errorf("mergeBlockers() with a default reviewer approval = %q, want none", got)
+	}
+
+	pr.State = "DECLINED"
+	if got := mergeBlockers(&pr, nil, mergeRequirements{}); len(got) != 1 || got[0] != "pull request is declined" {
+		t.Errorf("mergeBlockers() for a declined PR = %q", got)
+	}
+}
+
+func TestAwaitingFirstBuild(t *testing.T) {
+	passed := []CommitStatus{{State: "SUCCESSFUL"}}
+	tests := []struct {
+		statuses []CommitStatus
+		req      mergeRequirements
+		elapsed  time.Duration
+		want     bool
+	}{
+		{nil, mergeRequirements{}, 0, true},
+		{nil, mergeRequirements{}, buildSettlePeriod - time.Second, true},
+		{nil, mergeRequirements{}, buildSettlePeriod, false},
+		{passed, mergeRequirements{}, 0, false},
+		{nil, mergeRequirements{PassingBuilds: 1}, 0, false},
+	}
+	for _, tt := range tests {
+		if got := awaitingFirstBuild(tt.statuses, tt.req, tt.elapsed); got != tt.want {
+			t.Errorf("awaitingFirstBuild(%d statuses, %+v, %s) = %v, want %v", len(tt.statuses), tt.req, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestNewCmdTask_Subcommands(t *testing.T) {
+	cmd := newCmdTask()
+	for _, name := range []string{"create", "resolve", "reopen", "delete"} {
+		sub, _, err := cmd.Find([]string{name})
+		if err != nil || sub.Name() != name {
+			t.Errorf("expected subcommand %q", name)
+		}
+	}
+	resolve, _, _ := cmd.Find([]string{"resolve"})
+	if err := resolve.Args(resolve, []string{"42"}); err == nil {
+		t.Error("task resolve should require a pull request and a task")
+	}
+	if err := resolve.Args(resolve, []string{"ws/repo", "42", "7"}); err != nil {
+		t.Errorf("task resolve with a repository: %v", err)
+	}
+	create, _, _ := cmd.Find([]string{"create"})
+	for _, name := range []string{"body", "body-file", "editor", "comment"} {
+		if create.Flags().Lookup(name) == nil {
+			t.Errorf("task create: %s flag not found", name)
+		}
+	}
+}
+
+func TestTaskStateFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"open", "UNRESOLVED", false},
+		{"Resolved", "RESOLVED", false},
+		{"unresolved", "UNRESOLVED", false},
+		{"done", "", true},
+	}
+	for _, tt := range tests {
+		got, err := taskStateFilter(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("taskStateFilter(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestTasksSummary(t *testing.T) {
+	if got := tasksSummary(nil); got != "none" {
+		t.Errorf("tasksSummary(nil) = %q, want %q", got, "none")
+	}
+	tasks := []Task{{State: "UNRESOLVED"}, {State: "RESOLVED"}, {State: "UNRESOLVED"}}
+	if got := tasksSummary(tasks); got != "2 open, 1 resolved" {
+		t.Errorf("tasksSummary() = %q, want %q", got, "2 open, 1 resolved")
+	}
+}
+
+func TestNewCmdComment_Subcommands(t *testing.T) {
+	root := NewCmdPR()
+	cmd, args, err := root.Find([]string{"comment", "42", "--reply-to", "7", "-b", "Done"})
+	if err != nil || cmd.Name() != "comment" {
+		t.Fatalf("Find(comment 42) = %v, %v", cmd, err)
+	}
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if err := cmd.Args(cmd, cmd.Flags().Args()); err != nil {
+		t.Errorf("comment 42: %v", err)
+	}
+
+	for _, name := range []string{"edit", "delete", "resolve", "unresolve"} {
+		sub, _, err := root.Find([]string{"comment", name, "42", "7"})
+		if err != nil || sub.Name() != name {
+			t.Errorf("expected comment subcommand %q", name)
+			continue
+		}
+		if err := sub.Args(sub, []string{"42"}); err == nil {
+			t.Errorf("comment %s should require a pull request and a comment", name)
+		}
+	}
+}
+
+func TestInlineContext(t *testing.T) {
+	files := diff.Parse(`diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+ func main() {}
+`)
+	to := 2
+	c := comments.Comment{Inline: &comments.Inline{Path: "main.go", To: &to}}
+
+	got := inlineContext(&c, files, 1)
+	want := []string{
+		"      2 -var x = 1",
+		">     2 +var x = 2",
+		"      3  func main() {}",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("inlineContext() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	c.Inline.Outdated = true
+	if got := inlineContext(&c, files, 1); got != nil {
+		t.Errorf("inlineContext() for an outdated comment = %q", got)
+	}
+}
+
+const reviewDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+ func main() {}
+`
+
+func TestParseReview(t *testing.T) {
+	doc := `# Review of pull request #42
+verdict: Request changes
+
+Nice work overall.
+
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
+>> Why was this one?
++var x = 2
+>> Use a constant.
+>>
+>> Or a flag.
+ func main() {}
+>>
+`
+	r, err := parseReview(doc, reviewDiff)
+	if err != nil {
+		t.Fatalf("parseReview() error = %v", err)
+	}
+	if r.Verdict != "request-changes" || r.Summary != "Nice work overall." {
+		t.Errorf("verdict, summary = %q, %q", r.Verdict, r.Summary)
+	}
+	want := []reviewComment{
+		{Path: "main.go", From: 2, Body: "Why was this one?"},
+		{Path: "main.go", To: 2, Body: "Use a constant.\n\nOr a flag."},
+	}
+	if fmt.Sprint(r.Comments) != fmt.Sprint(want) {
+		t.Errorf("comments = %+v, want %+v", r.Comments, want)
+	}
+}
+
+func TestParseReview_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"changed diff", strings.Replace(reviewDiff, "x = 2", "x = 3", 1), "does not match"},
+		{"comment on header", strings.Replace(reviewDiff, "+++ b/main.go\n", "+++ b/main.go\n>> here\n", 1), "line 4"},
+		{"bad verdict", "verdict: maybe\n" + reviewDiff, "verdict"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseReview(tt.doc, reviewDiff)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseReview() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReviewTemplate_RoundTrip(t *testing.T) {
+	pr := &PullRequest{ID: 42, Title: "Bump x"}
+	r, err := parseReview(reviewTemplate(pr, reviewDiff), reviewDiff)
+	if err != nil {
+		t.Fatalf("parseReview(template) error = %v", err)
+	}
+	if r.Verdict != "comment" || r.Summary != "" || len(r.Comments) != 0 {
+		t.Errorf("untouched template parsed as %+v", r)
+	}
+}
+
+func TestSubmitReview_RetrySkipsPostedComments(t *testing.T) {
+	t.Setenv("BB_API_URL", "")
+	doc := `verdict: approve
+
+Nice work overall.
+
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
+>> Why was this one?
++var x = 2
+>> Use a constant.
+ func main() {}
+`
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Content struct {
+				Raw string `json:"raw"`
+			} `json:"content"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Content.Raw == "Use a constant." {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		posted = append(posted, body.Content.Raw)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.Client(), &config.Config{APIURL: server.URL}, &config.TokenData{AccessToken: "token"})
+
+	r, err := parseReview(doc, reviewDiff)
+	if err != nil {
+		t.Fatalf("parseReview() error = %v", err)
+	}
+	if err := submitReview(context.Background(), client, "myws/myrepo", "42", r); err == nil {
+		t.Fatal("submitReview() succeeded, want the second comment to fail")
+	}
+	if fmt.Sprint(posted) != "[Why was this one?]" || r.posted != 1 || r.summaryPosted {
+		t.Fatalf("posted %q, r.posted = %d, r.summaryPosted = %v", posted, r.posted, r.summaryPosted)
+	}
+
+	rest, err := parseReview(r.unposted(doc), reviewDiff)
+	if err != nil {
+		t.Fatalf("parseReview(unposted) error = %v", err)
+	}
+	want := []reviewComment{{Path: "main.go", To: 2, Body: "Use a constant."}}
+	if fmt.Sprint(rest.Comments) != fmt.Sprint(want) || rest.Summary != "Nice work overall." || rest.Verdict != "approve" {
+		t.Errorf("unposted review = %+v", rest)
+	}
+
+	r.posted, r.summaryPosted = 2, true
+	rest, err = parseReview(r.unposted(doc), reviewDiff)
+	if err != nil {
+		t.Fatalf("parseReview(unposted) error = %v", err)
+	}
+	if len(rest.Comments) != 0 || rest.Summary != "" || rest.Verdict != "approve" {
+		t.Errorf("review with everything posted = %+v, want only the verdict", rest)
+	}
+}
+
+func TestNewCmdDiff_PatchExclusive(t *testing.T) {
+	for _, flag := range []string{"--stat", "--name-only", "--side-by-side", "--path=*.go"} {
+		cmd := newCmdDiff()
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"myws/myrepo", "42", "--patch", flag})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "none of the others") {
+			t.Errorf("--patch with %s: got error %v, want a mutually exclusive flags error", flag, err)
+		}
+	}
+}
+
+func TestNewCmdApply_Flags(t *testing.T) {
+	cmd := newCmdApply()
+	flag := cmd.Flags().Lookup("signoff")
+	if flag == nil {
+		t.Fatal("signoff flag not found")
+	}
+	if flag.Shorthand != "s" {
+		t.Errorf("expected signoff shorthand 's', got %q", flag.Shorthand)
+	}
+}
+
+func TestNewCmdApply_UncommittedChanges(t *testing.T) {
+	dir := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		c.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitCmd("init")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd("add", "a.txt")
+	gitCmd("commit", "-m", "add a")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	cmd := newCmdApply()
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"myws/myrepo", "42"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Errorf("got error %v, want an uncommitted changes error", err)
+	}
+}
+
+func TestApplyConflictError(t *testing.T) {
+	err := applyConflictError("42", 1, &git.ApplyError{
+		Patch:     "0002 Fix the parser",
+		Conflicts: []string{"parser.go", "lexer.go"},
+	})
+	msg := err.Error()
+	for _, want := range []string{
+		"Could not apply 0002 Fix the parser of PR #42 (1 commit applied before it)",
+		"Conflicts:\n  parser.go\n  lexer.go",
+		"git am --continue",
+		"git am --abort",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not contain %q", msg, want)
+		}
+	}
+
+	err = applyConflictError("42", 0, &git.ApplyError{})
+	if !strings.HasPrefix(err.Message, "Could not apply a patch of PR #42") {
+		t.Errorf("Message = %q", err.Message)
+	}
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/config"
 	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -69,9 +71,7 @@ func NewCmdRepo() *cobra.Command {
 
 func newCmdList() *cobra.Command {
 	var workspace string
-	var page int
-	var jsonOut bool
-
+	var pagination cmdutil.PaginationOptions
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List repositories in a workspace",
@@ -87,27 +87,12 @@ func newCmdList() *cobra.Command {
 				return errors.InvalidInput("workspace", "no workspace specified. Use --workspace flag or set a default with 'bb config set-default-workspace'")
 			}
 
-			path := fmt.Sprintf("/repositories/%s?pagelen=25&page=%d", url.PathEscape(workspace), page)
-			data, err := client.Get(path)
+			path := fmt.Sprintf("/repositories/%s?pagelen=25&page=%d", url.PathEscape(workspace), pagination.Page)
+			repos, raw, hasMore, err := cmdutil.FetchListWithRaw[Repository](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return errors.Wrap(err, "Failed to parse repository list response")
-			}
-
-			var repos []Repository
-			if err := json.Unmarshal(paginated.Values, &repos); err != nil {
-				return errors.Wrap(err, "Failed to parse repository data")
-			}
-
-			if jsonOut {
-				output.PrintJSON(repos)
-				return nil
-			}
-
 			table := output.NewTable("NAME", "SLUG", "PRIVATE", "LANGUAGE", "MAIN BRANCH")
 			for _, r := range repos {
 				mainBranch := "–"
@@ -116,35 +101,39 @@ func newCmdList() *cobra.Command {
 				}
 				table.AddRow(r.Name, r.FullName, fmt.Sprintf("%v", r.IsPrivate), r.Language, mainBranch)
 			}
-			table.Print()
+			if err := output.Render(output.WithRaw(repos, raw), table); err != nil {
+				return err
+			}
 
-			if paginated.Next != "" {
-				output.PrintMessage("\nMore results available. Use --page %d to see the next page.", page+1)
+			if output.IsTable() && hasMore && !pagination.FollowPages() {
+				output.PrintMessage("\nMore results available. Use --page %d to see the next page, or --all to fetch everything.", pagination.Page+1)
 			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace slug")
 	cmd.RegisterFlagCompletionFunc("workspace", completion.WorkspaceNames)
-	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "view <workspace/repo-slug>",
+		Use:   "view [workspace/repo-slug]",
 		Short: "View repository details",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s", args[0])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -154,9 +143,8 @@ func newCmdView() *cobra.Command {
 				return errors.Wrap(err, "Failed to parse repository details")
 			}
 
-			if jsonOut {
-				output.PrintJSON(repo)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(repo, json.RawMessage(data)))
 			}
 
 			mainBranch := "–"
@@ -184,8 +172,10 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -226,7 +216,7 @@ func newCmdCreate() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/%s", url.PathEscape(workspace), url.PathEscape(args[0]))
-			data, err := client.Put(path, string(jsonBody))
+			data, err := client.PutContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -251,16 +241,20 @@ func newCmdCreate() *cobra.Command {
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug>",
+		Use:   "delete [workspace/repo-slug]",
 		Short: "Delete a repository",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 			path := fmt.Sprintf("/repositories/%s", args[0])
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -269,6 +263,8 @@ func newCmdDelete() *cobra.Command {
 		},
 		ValidArgsFunction: completion.RepositoryNamesWithDescriptions,
 	}
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -277,10 +273,14 @@ func newCmdFork() *cobra.Command {
 	var targetWorkspace string
 
 	cmd := &cobra.Command{
-		Use:   "fork <workspace/repo-slug>",
+		Use:   "fork [workspace/repo-slug]",
 		Short: "Fork a repository",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -295,7 +295,7 @@ func newCmdFork() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/repositories/%s/forks", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -312,6 +312,8 @@ func newCmdFork() *cobra.Command {
 	cmd.Flags().StringVarP(&targetWorkspace, "target-workspace", "t", "", "Target workspace for the fork")
 	cmd.RegisterFlagCompletionFunc("target-workspace", completion.WorkspaceNames)
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -349,17 +351,18 @@ func newCmdClone() *cobra.Command {
 				remoteURL := strings.TrimSpace(string(gitOutput))
 
 				// Parse Bitbucket URL to extract workspace/repo
-				workspace, repoSlug, err := parseBitbucketURL(remoteURL)
+				remote, err := git.ParseBitbucketRemote(remoteURL, client.GetConfig().GitHostnames()...)
 				if err != nil {
 					return &errors.BBError{
 						Message:    "Current repository is not a Bitbucket repository",
 						Suggestion: fmt.Sprintf("Remote URL: %s\nRun 'bb repo clone <workspace/repo-slug>' to clone a specific repository.", remoteURL),
 					}
 				}
+				workspace, repoSlug := remote.Workspace, remote.Repo
 
 				// Check if user has a fork
 				path := fmt.Sprintf("/repositories/%s/%s/forks", workspace, repoSlug)
-				forksData, err := client.Get(path)
+				forksData, err := client.GetContext(cmd.Context(), path)
 				if err != nil {
 					return errors.Wrap(err, "Failed to check for forks")
 				}
@@ -375,7 +378,7 @@ func newCmdClone() *cobra.Command {
 				}
 
 				// Get current user to match forks
-				userData, err := client.Get("/user")
+				userData, err := client.GetContext(cmd.Context(), "/user")
 				if err != nil {
 					return errors.Wrap(err, "Failed to get current user")
 				}
@@ -420,7 +423,7 @@ func newCmdClone() *cobra.Command {
 
 			// Fetch repository details to get clone URL
 			path := fmt.Sprintf("/repositories/%s", args[0])
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				// Provide helpful error messages for common failure scenarios
 				if errors.IsNotFound(err) {
@@ -530,41 +533,19 @@ func newCmdClone() *cobra.Command {
 	return cmd
 }
 
-// parseBitbucketURL extracts workspace and repo slug from a Bitbucket remote URL
-func parseBitbucketURL(remoteURL string) (workspace, repoSlug string, err error) {
-	// Handle HTTPS URLs: https://bitbucket.org/workspace/repo.git
-	if strings.HasPrefix(remoteURL, "https://bitbucket.org/") {
-		parts := strings.TrimPrefix(remoteURL, "https://bitbucket.org/")
-		parts = strings.TrimSuffix(parts, ".git")
-		pathParts := strings.SplitN(parts, "/", 2)
-		if len(pathParts) == 2 {
-			return pathParts[0], pathParts[1], nil
-		}
-	}
-
-	// Handle SSH URLs: git@bitbucket.org:workspace/repo.git
-	if strings.HasPrefix(remoteURL, "git@bitbucket.org:") {
-		parts := strings.TrimPrefix(remoteURL, "git@bitbucket.org:")
-		parts = strings.TrimSuffix(parts, ".git")
-		pathParts := strings.SplitN(parts, "/", 2)
-		if len(pathParts) == 2 {
-			return pathParts[0], pathParts[1], nil
-		}
-	}
-
-	return "", "", fmt.Errorf("not a valid Bitbucket URL")
-}
-
 func newCmdCommits() *cobra.Command {
-	var jsonOut bool
 	var branch string
 	var page int
 
 	cmd := &cobra.Command{
-		Use:   "commits <workspace/repo-slug>",
+		Use:   "commits [workspace/repo-slug]",
 		Short: "List recent commits",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -575,7 +556,7 @@ func newCmdCommits() *cobra.Command {
 			}
 			path += fmt.Sprintf("?pagelen=20&page=%d", page)
 
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -597,11 +578,6 @@ func newCmdCommits() *cobra.Command {
 				return errors.Wrap(err, "Failed to parse commit data")
 			}
 
-			if jsonOut {
-				output.PrintJSON(commits)
-				return nil
-			}
-
 			table := output.NewTable("HASH", "AUTHOR", "DATE", "MESSAGE")
 			for _, c := range commits {
 				table.AddRow(
@@ -611,36 +587,50 @@ func newCmdCommits() *cobra.Command {
 					output.Truncate(c.Message, 60),
 				)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(commits, paginated.Values), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch name")
 	cmd.Flags().IntVarP(&page, "page", "p", 1, "Page number")
 	cmd.ValidArgsFunction = completion.RepositoryNamesWithDescriptions
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdDiff() *cobra.Command {
+	var opts cmdutil.DiffOptions
+
 	cmd := &cobra.Command{
-		Use:   "diff <workspace/repo-slug> <spec>",
+		Use:   "diff [workspace/repo-slug] <spec>",
 		Short: "View a diff (e.g., commit hash or branch..branch)",
-		Args:  cobra.ExactArgs(2),
+		Long: `View a diff between two commits or branches, given as a commit hash or as
+branch..branch. On a terminal the diff is colored and shown through
+$BB_PAGER or $PAGER (less by default); set BB_PAGER=cat or use --no-pager
+to print it directly.`,
+		Example: `  bb repo diff myworkspace/myrepo main..feature
+  bb repo diff main..feature --stat
+  bb repo diff main..feature --path '*.go' --side-by-side`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/repositories/%s/diff/%s", args[0], url.PathEscape(args[1]))
-			data, err := client.Get(path)
+
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
-			fmt.Println(string(data))
-			return nil
+			spec := url.PathEscape(args[1])
+			path := fmt.Sprintf("/repositories/%s/diff/%s", args[0], spec)
+			statPath := fmt.Sprintf("/repositories/%s/diffstat/%s?pagelen=100", args[0], spec)
+			return cmdutil.PrintDiff(cmd.Context(), client, path, statPath, &opts)
 		},
 		ValidArgsFunction: completion.RepositoryNamesWithDescriptions,
 	}
+	cmdutil.AddDiffFlags(cmd, &opts)
+	cmdutil.AddRepoFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
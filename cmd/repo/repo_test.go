@@ -56,7 +56,7 @@ func TestNewCmdList_HasExpectedFlags(t *testing.T) {
 		t.Fatalf("failed to find list command: %v", err)
 	}
 
-	expectedFlags := []string{"workspace", "page", "json"}
+	expectedFlags := []string{"workspace", "page", "all", "limit", "json"}
 	for _, name := range expectedFlags {
 		if listCmd.Flags().Lookup(name) == nil {
 			t.Errorf("expected flag --%s not found on list command", name)
@@ -134,17 +134,21 @@ func TestNewCmdCommits_HasExpectedFlags(t *testing.T) {
 	}
 }
 
-func TestNewCmdDiff_NoExtraFlags(t *testing.T) {
+func TestNewCmdDiff_HasExpectedFlags(t *testing.T) {
 	cmd := NewCmdRepo()
 	diffCmd, _, err := cmd.Find([]string{"diff"})
 	if err != nil {
 		t.Fatalf("failed to find diff command: %v", err)
 	}
 
-	// Diff command should be simple with no custom flags
 	if diffCmd.Flags().Lookup("json") != nil {
 		t.Error("diff command should not have --json flag")
 	}
+	for _, name := range []string{"stat", "name-only", "side-by-side", "path", "no-pager"} {
+		if diffCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s not found on diff command", name)
+		}
+	}
 }
 
 func TestNewCmdCreate_DefaultFlagValues(t *testing.T) {
@@ -496,8 +500,8 @@ func TestNewCmdView_CommandStructure(t *testing.T) {
 		t.Fatalf("failed to find view command: %v", err)
 	}
 
-	if viewCmd.Use != "view <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", viewCmd.Use, "view <workspace/repo-slug>")
+	if viewCmd.Use != "view [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", viewCmd.Use, "view [workspace/repo-slug]")
 	}
 
 	if viewCmd.RunE == nil {
@@ -528,8 +532,8 @@ func TestNewCmdDelete_CommandStructure(t *testing.T) {
 		t.Fatalf("failed to find delete command: %v", err)
 	}
 
-	if deleteCmd.Use != "delete <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", deleteCmd.Use, "delete <workspace/repo-slug>")
+	if deleteCmd.Use != "delete [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", deleteCmd.Use, "delete [workspace/repo-slug]")
 	}
 
 	if deleteCmd.RunE == nil {
@@ -544,8 +548,8 @@ func TestNewCmdFork_CommandStructure(t *testing.T) {
 		t.Fatalf("failed to find fork command: %v", err)
 	}
 
-	if forkCmd.Use != "fork <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", forkCmd.Use, "fork <workspace/repo-slug>")
+	if forkCmd.Use != "fork [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", forkCmd.Use, "fork [workspace/repo-slug]")
 	}
 
 	if forkCmd.RunE == nil {
@@ -560,8 +564,8 @@ func TestNewCmdCommits_CommandStructure(t *testing.T) {
 		t.Fatalf("failed to find commits command: %v", err)
 	}
 
-	if commitsCmd.Use != "commits <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", commitsCmd.Use, "commits <workspace/repo-slug>")
+	if commitsCmd.Use != "commits [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", commitsCmd.Use, "commits [workspace/repo-slug]")
 	}
 
 	if commitsCmd.RunE == nil {
@@ -576,8 +580,8 @@ func TestNewCmdDiff_CommandStructure(t *testing.T) {
 		t.Fatalf("failed to find diff command: %v", err)
 	}
 
-	if diffCmd.Use != "diff <workspace/repo-slug> <spec>" {
-		t.Errorf("Use = %q, want %q", diffCmd.Use, "diff <workspace/repo-slug> <spec>")
+	if diffCmd.Use != "diff [workspace/repo-slug] <spec>" {
+		t.Errorf("Use = %q, want %q", diffCmd.Use, "diff [workspace/repo-slug] <spec>")
 	}
 
 	if diffCmd.RunE == nil {
@@ -598,8 +602,8 @@ func TestNewCmdList_Direct_HasUseAndShort(t *testing.T) {
 
 func TestNewCmdView_Direct_HasUseAndShort(t *testing.T) {
 	cmd := newCmdView()
-	if cmd.Use != "view <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "view <workspace/repo-slug>")
+	if cmd.Use != "view [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "view [workspace/repo-slug]")
 	}
 	if cmd.Short == "" {
 		t.Error("view command should have Short description")
@@ -618,8 +622,8 @@ func TestNewCmdCreate_Direct_HasUseAndShort(t *testing.T) {
 
 func TestNewCmdDelete_Direct_HasUseAndShort(t *testing.T) {
 	cmd := newCmdDelete()
-	if cmd.Use != "delete <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "delete <workspace/repo-slug>")
+	if cmd.Use != "delete [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "delete [workspace/repo-slug]")
 	}
 	if cmd.Short == "" {
 		t.Error("delete command should have Short description")
@@ -628,8 +632,8 @@ func TestNewCmdDelete_Direct_HasUseAndShort(t *testing.T) {
 
 func TestNewCmdFork_Direct_HasUseAndShort(t *testing.T) {
 	cmd := newCmdFork()
-	if cmd.Use != "fork <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "fork <workspace/repo-slug>")
+	if cmd.Use != "fork [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "fork [workspace/repo-slug]")
 	}
 	if cmd.Short == "" {
 		t.Error("fork command should have Short description")
@@ -638,8 +642,8 @@ func TestNewCmdFork_Direct_HasUseAndShort(t *testing.T) {
 
 func TestNewCmdCommits_Direct_HasUseAndShort(t *testing.T) {
 	cmd := newCmdCommits()
-	if cmd.Use != "commits <workspace/repo-slug>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "commits <workspace/repo-slug>")
+	if cmd.Use != "commits [workspace/repo-slug]" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "commits [workspace/repo-slug]")
 	}
 	if cmd.Short == "" {
 		t.Error("commits command should have Short description")
@@ -648,8 +652,8 @@ func TestNewCmdCommits_Direct_HasUseAndShort(t *testing.T) {
 
 func TestNewCmdDiff_Direct_HasUseAndShort(t *testing.T) {
 	cmd := newCmdDiff()
-	if cmd.Use != "diff <workspace/repo-slug> <spec>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "diff <workspace/repo-slug> <spec>")
+	if cmd.Use != "diff [workspace/repo-slug] <spec>" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "diff [workspace/repo-slug] <spec>")
 	}
 	if cmd.Short == "" {
 		t.Error("diff command should have Short description")
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -16,12 +19,16 @@ import (
 	environmentCmd "github.com/PhilipKram/bitbucket-cli/cmd/environment"
 	issueCmd "github.com/PhilipKram/bitbucket-cli/cmd/issue"
 	"github.com/PhilipKram/bitbucket-cli/internal/buildinfo"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
 	"github.com/PhilipKram/bitbucket-cli/internal/update"
 	mcpCmd "github.com/PhilipKram/bitbucket-cli/cmd/mcp"
 	pipelineCmd "github.com/PhilipKram/bitbucket-cli/cmd/pipeline"
 	prCmd "github.com/PhilipKram/bitbucket-cli/cmd/pr"
 	repoCmd "github.com/PhilipKram/bitbucket-cli/cmd/repo"
 	snippetCmd "github.com/PhilipKram/bitbucket-cli/cmd/snippet"
+	statusCmd "github.com/PhilipKram/bitbucket-cli/cmd/status"
 	userCmd "github.com/PhilipKram/bitbucket-cli/cmd/user"
 	variableCmd "github.com/PhilipKram/bitbucket-cli/cmd/variable"
 	workspaceCmd "github.com/PhilipKram/bitbucket-cli/cmd/workspace"
@@ -41,6 +48,11 @@ func init() {
 
 var updateCh = make(chan *update.UpdateInfo, 1)
 
+var (
+	profile string
+	format  string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "bb",
 	Short: "Bitbucket CLI - a command-line tool for Bitbucket Cloud",
@@ -55,10 +67,23 @@ Get started:
   bb auth login --web                                 # OAuth via browser
   echo "$TOKEN" | bb auth login --with-token          # CI/scripts`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if profile != "" {
+			if err := config.ValidateProfileName(profile); err != nil {
+				return err
+			}
+			config.SetProfile(profile)
+		}
+		if err := cmdutil.ConfigureOutput(cmd); err != nil {
+			return err
+		}
+		config.SetPassphrasePrompt(func(profile string) (string, error) {
+			return cmdutil.PromptSecret(fmt.Sprintf("Credential store passphrase (profile %s): ", profile))
+		})
 		go func() {
 			updateCh <- update.CheckForUpdate(version)
 		}()
+		return nil
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		info := <-updateCh
@@ -72,11 +97,29 @@ Get started:
 	},
 }
 
+// Execute runs the command line. Interrupting the program cancels the
+// context passed to commands, aborting their API requests; interrupting it
+// again kills it.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Commands waiting on something else, such as a prompt, the editor
+		// or the OAuth callback, do not see the cancellation, so the next
+		// signal gets the default behavior and ends the process.
+		<-ctx.Done()
+		stop()
+	}()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Use the named auth profile (overrides BB_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "", "Output format: table, json, yaml, csv, tsv or ndjson (default from 'bb config set-format')")
+	rootCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return output.Formats(), cobra.ShellCompDirectiveNoFileComp
+	})
+
 	rootCmd.AddCommand(authCmd.NewCmdAuth())
 	rootCmd.AddCommand(repoCmd.NewCmdRepo())
 	rootCmd.AddCommand(prCmd.NewCmdPR())
@@ -89,6 +132,7 @@ func init() {
 	rootCmd.AddCommand(downloadCmd.NewCmdDownload())
 	rootCmd.AddCommand(variableCmd.NewCmdVariable())
 	rootCmd.AddCommand(environmentCmd.NewCmdEnvironment())
+	rootCmd.AddCommand(statusCmd.NewCmdStatus())
 	rootCmd.AddCommand(browseCmd.NewCmdBrowse())
 	rootCmd.AddCommand(apiCmd.NewCmdAPI())
 	rootCmd.AddCommand(configCmd.NewCmdConfig())
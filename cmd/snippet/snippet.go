@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -43,8 +44,7 @@ func NewCmdSnippet() *cobra.Command {
 
 func newCmdList() *cobra.Command {
 	var workspace string
-	var jsonOut bool
-
+	var pagination cmdutil.PaginationOptions
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List snippets",
@@ -58,28 +58,13 @@ func newCmdList() *cobra.Command {
 			if workspace != "" {
 				path = fmt.Sprintf("/snippets/%s", url.PathEscape(workspace))
 			}
-			path += "?pagelen=25"
+			path += fmt.Sprintf("?pagelen=25&page=%d", pagination.Page)
 
-			data, err := client.Get(path)
+			snippets, raw, _, err := cmdutil.FetchListWithRaw[Snippet](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var snippets []Snippet
-			if err := json.Unmarshal(paginated.Values, &snippets); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(snippets)
-				return nil
-			}
-
 			table := output.NewTable("ID", "TITLE", "PRIVATE", "CREATOR", "CREATED")
 			for _, s := range snippets {
 				created := ""
@@ -88,19 +73,17 @@ func newCmdList() *cobra.Command {
 				}
 				table.AddRow(s.ID, s.Title, fmt.Sprintf("%v", s.IsPrivate), s.Creator.DisplayName, created)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(snippets, raw), table)
 		},
 	}
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace slug (omit for personal snippets)")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
 	var workspace string
-	var jsonOut bool
-
 	cmd := &cobra.Command{
 		Use:   "view <snippet-id>",
 		Short: "View snippet details",
@@ -117,7 +100,7 @@ func newCmdView() *cobra.Command {
 				return fmt.Errorf("--workspace is required")
 			}
 
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -127,9 +110,8 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(snippet)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(snippet, json.RawMessage(data)))
 			}
 
 			output.PrintMessage("ID:      %s", snippet.ID)
@@ -142,7 +124,7 @@ func newCmdView() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace slug (required)")
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
@@ -181,7 +163,7 @@ func newCmdCreate() *cobra.Command {
 
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/snippets/%s", url.PathEscape(workspace))
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -220,7 +202,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("--workspace is required")
 			}
 			path := fmt.Sprintf("/snippets/%s/%s", url.PathEscape(workspace), args[0])
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
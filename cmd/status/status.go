@@ -0,0 +1,229 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
+	"github.com/PhilipKram/bitbucket-cli/internal/completion"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// CommitStatus is a build status reported against a commit.
+type CommitStatus struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	RefName     string `json:"refname"`
+	CreatedOn   string `json:"created_on"`
+	UpdatedOn   string `json:"updated_on"`
+}
+
+// States lists the build states Bitbucket accepts.
+var States = []string{"INPROGRESS", "SUCCESSFUL", "FAILED", "STOPPED"}
+
+var commitHashPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+
+// NewCmdStatus returns the top-level "status" command with subcommands.
+func NewCmdStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report and list commit build statuses",
+		Long: `Report build results from external CI systems against commits, and list
+the statuses reported for a commit. Pull requests show the statuses of their
+latest commit; see 'bb pr checks'.`,
+	}
+
+	cmd.AddCommand(newCmdSet())
+	cmd.AddCommand(newCmdList())
+
+	cmdutil.AddRepoFlag(cmd)
+	return cmd
+}
+
+// resolveCommit returns the full hash of commit, a hash or a ref such as
+// HEAD, using the local checkout when there is one. Hashes unknown locally
+// are passed to the API unchanged.
+func resolveCommit(commit string) (string, error) {
+	if hash, err := git.ResolveCommit(commit); err == nil {
+		return hash, nil
+	}
+	if commitHashPattern.MatchString(commit) {
+		return commit, nil
+	}
+	return "", errors.InvalidInput("commit", fmt.Sprintf("%q is neither a commit hash nor a ref in the current repository", commit))
+}
+
+// normalizeState upper-cases state and checks it is a valid build state.
+func normalizeState(state string) (string, error) {
+	state = strings.ToUpper(strings.TrimSpace(state))
+	for _, s := range States {
+		if state == s {
+			return state, nil
+		}
+	}
+	return "", errors.InvalidInput("state", fmt.Sprintf("%q (expected one of %s)", state, strings.Join(States, ", ")))
+}
+
+func newCmdSet() *cobra.Command {
+	var state, key, name, statusURL, description, refName string
+
+	cmd := &cobra.Command{
+		Use:   "set [workspace/repo-slug] <commit>",
+		Short: "Create or update a build status on a commit",
+		Long: `Report a build status against a commit. Statuses are identified by --key:
+setting a status with an existing key replaces it, so a CI job typically
+reports INPROGRESS when it starts and SUCCESSFUL or FAILED when it finishes.
+
+The commit may be a hash or a ref such as HEAD, resolved in the current
+checkout.`,
+		Example: `  bb status set HEAD --state INPROGRESS --key jenkins-build --url "$BUILD_URL"
+  bb status set myworkspace/my-repo 1a2b3c4 --state SUCCESSFUL --key jenkins-build \
+    --name "Jenkins #42" --url "$BUILD_URL" --description "All tests passed"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			state, err := normalizeState(state)
+			if err != nil {
+				return err
+			}
+			if u, err := url.Parse(statusURL); err != nil || u.Scheme == "" || u.Host == "" {
+				return errors.InvalidInput("url", fmt.Sprintf("%q (expected an absolute URL)", statusURL))
+			}
+			commit, err := resolveCommit(args[1])
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			body := map[string]string{
+				"key":   key,
+				"state": state,
+				"url":   statusURL,
+			}
+			if name != "" {
+				body["name"] = name
+			}
+			if description != "" {
+				body["description"] = description
+			}
+			if refName != "" {
+				body["refname"] = refName
+			}
+			jsonBody, _ := json.Marshal(body)
+			path := fmt.Sprintf("/repositories/%s/commit/%s/statuses/build", args[0], commit)
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
+			if err != nil {
+				return err
+			}
+
+			var status CommitStatus
+			if err := json.Unmarshal(data, &status); err != nil {
+				return err
+			}
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(status, json.RawMessage(data)))
+			}
+			output.PrintMessage("Status '%s' set to %s on commit %s.", status.Key, status.State, shortHash(commit))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&state, "state", "s", "", "Build state: "+strings.Join(States, ", ")+" (required)")
+	cmd.Flags().StringVarP(&key, "key", "k", "", "Unique key of the build, e.g. the CI job name (required)")
+	cmd.Flags().StringVarP(&statusURL, "url", "u", "", "Link to the build results (required)")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Display name of the build")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Description of the build result")
+	cmd.Flags().StringVar(&refName, "ref", "", "Branch or tag the build ran on")
+	cmd.MarkFlagRequired("state")
+	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagRequired("url")
+	cmd.RegisterFlagCompletionFunc("state", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return States, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+func newCmdList() *cobra.Command {
+	var pagination cmdutil.PaginationOptions
+
+	cmd := &cobra.Command{
+		Use:   "list [workspace/repo-slug] <commit>",
+		Short: "List build statuses of a commit",
+		Example: `  bb status list HEAD
+  bb status list myworkspace/my-repo 1a2b3c4 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+			commit, err := resolveCommit(args[1])
+			if err != nil {
+				return err
+			}
+
+			client, err := api.NewClient()
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/repositories/%s/commit/%s/statuses?pagelen=25&page=%d", args[0], commit, pagination.Page)
+			statuses, raw, _, err := cmdutil.FetchListWithRaw[CommitStatus](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
+			}
+
+			if len(statuses) == 0 && output.IsTable() {
+				output.PrintMessage("No statuses reported for commit %s.", shortHash(commit))
+				return nil
+			}
+			table := output.NewTable("STATE", "KEY", "NAME", "UPDATED", "URL")
+			for _, s := range statuses {
+				updated := s.UpdatedOn
+				if len(updated) > 10 {
+					updated = updated[:10]
+				}
+				table.AddRow(s.State, s.Key, output.Truncate(s.Name, 40), updated, s.URL)
+			}
+			return output.Render(output.WithRaw(statuses, raw), table)
+		},
+	}
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.AddJSONFlag(cmd)
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completion.RepositoryNamesWithDescriptions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cmdutil.SetRepoArgs(cmd, 1)
+	return cmd
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNewCmdStatus_HasSubcommands(t *testing.T) {
+	cmd := NewCmdStatus()
+
+	expected := map[string]bool{
+		"set":  false,
+		"list": false,
+	}
+	for _, sub := range cmd.Commands() {
+		if _, ok := expected[sub.Name()]; ok {
+			expected[sub.Name()] = true
+		}
+	}
+	for name, found := range expected {
+		if !found {
+			t.Errorf("expected subcommand %q not found", name)
+		}
+	}
+}
+
+func TestNewCmdSet_RequiredFlags(t *testing.T) {
+	cmd := newCmdSet()
+	for _, name := range []string{"state", "key", "url"} {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("expected flag %q not found", name)
+		}
+		if _, ok := flag.Annotations["cobra_annotation_bash_completion_one_required_flag"]; !ok {
+			t.Errorf("expected flag %q to be required", name)
+		}
+	}
+	for _, name := range []string{"name", "description", "ref", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag %q not found", name)
+		}
+	}
+}
+
+func TestNewCmdList_HasExpectedFlags(t *testing.T) {
+	cmd := newCmdList()
+	for _, name := range []string{"page", "all", "limit", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag %q not found", name)
+		}
+	}
+}
+
+func TestNormalizeState(t *testing.T) {
+	for in, want := range map[string]string{"successful": "SUCCESSFUL", " INPROGRESS ": "INPROGRESS", "Failed": "FAILED"} {
+		got, err := normalizeState(in)
+		if err != nil {
+			t.Errorf("normalizeState(%q) error: %v", in, err)
+		} else if got != want {
+			t.Errorf("normalizeState(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if _, err := normalizeState("PASSED"); err == nil {
+		t.Error("normalizeState(PASSED) expected an error")
+	}
+}
+
+func TestResolveCommit_OutsideRepo(t *testing.T) {
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	hash := "1a2b3c4d5e6f"
+	if got, err := resolveCommit(hash); err != nil || got != hash {
+		t.Errorf("resolveCommit(%q) = %q, %v, want the hash unchanged", hash, got, err)
+	}
+	if _, err := resolveCommit("HEAD"); err == nil {
+		t.Error("resolveCommit(HEAD) expected an error outside a git repository")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -58,8 +59,6 @@ func NewCmdUser() *cobra.Command {
 }
 
 func newCmdMe() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
 		Use:   "me",
 		Short: "Show current authenticated user",
@@ -68,7 +67,7 @@ func newCmdMe() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			data, err := client.Get("/user")
+			data, err := client.GetContext(cmd.Context(), "/user")
 			if err != nil {
 				return err
 			}
@@ -78,9 +77,8 @@ func newCmdMe() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(user)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(user, json.RawMessage(data)))
 			}
 
 			output.PrintMessage("Display Name: %s", user.DisplayName)
@@ -91,13 +89,11 @@ func newCmdMe() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
 func newCmdView() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
 		Use:   "view <uuid-or-username>",
 		Short: "View a user's profile",
@@ -108,7 +104,7 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 			path := fmt.Sprintf("/users/%s", url.PathEscape(args[0]))
-			data, err := client.Get(path)
+			data, err := client.GetContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -118,9 +114,8 @@ func newCmdView() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(user)
-				return nil
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(user, json.RawMessage(data)))
 			}
 
 			output.PrintMessage("Display Name: %s", user.DisplayName)
@@ -131,12 +126,12 @@ func newCmdView() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
 func newCmdEmails() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "emails",
@@ -146,40 +141,26 @@ func newCmdEmails() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			data, err := client.Get("/user/emails")
+			path := fmt.Sprintf("/user/emails?page=%d", pagination.Page)
+			emails, raw, _, err := cmdutil.FetchListWithRaw[Email](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var emails []Email
-			if err := json.Unmarshal(paginated.Values, &emails); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(emails)
-				return nil
-			}
-
 			table := output.NewTable("EMAIL", "PRIMARY", "CONFIRMED")
 			for _, e := range emails {
 				table.AddRow(e.Email, fmt.Sprintf("%v", e.IsPrimary), fmt.Sprintf("%v", e.IsConfirmed))
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(emails, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
 func newCmdSSHKeys() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "ssh-keys",
@@ -189,26 +170,12 @@ func newCmdSSHKeys() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			data, err := client.Get("/user/ssh-keys?pagelen=50")
+			path := fmt.Sprintf("/user/ssh-keys?pagelen=50&page=%d", pagination.Page)
+			keys, raw, _, err := cmdutil.FetchListWithRaw[SSHKey](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var keys []SSHKey
-			if err := json.Unmarshal(paginated.Values, &keys); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(keys)
-				return nil
-			}
-
 			table := output.NewTable("UUID", "LABEL", "COMMENT", "CREATED")
 			for _, k := range keys {
 				created := ""
@@ -217,11 +184,11 @@ func newCmdSSHKeys() *cobra.Command {
 				}
 				table.AddRow(k.UUID, k.Label, k.Comment, created)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(keys, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
@@ -242,7 +209,7 @@ func newCmdSSHKeyAdd() *cobra.Command {
 				"label": label,
 			}
 			jsonBody, _ := json.Marshal(body)
-			_, err = client.Post("/user/ssh-keys", string(jsonBody))
+			_, err = client.PostContext(cmd.Context(), "/user/ssh-keys", string(jsonBody))
 			if err != nil {
 				return err
 			}
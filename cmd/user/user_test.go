@@ -127,3 +127,18 @@ func TestNewCmdSSHKeyAdd_KeyFlagRequired(t *testing.T) {
 		t.Errorf("key flag should have shorthand 'k', got %q", flag.Shorthand)
 	}
 }
+
+func TestListCommands_HavePaginationFlags(t *testing.T) {
+	for _, name := range []string{"emails", "ssh-keys"} {
+		cmd := NewCmdUser()
+		sub, _, err := cmd.Find([]string{name})
+		if err != nil {
+			t.Fatalf("failed to find %s command: %v", name, err)
+		}
+		for _, flag := range []string{"page", "all", "limit"} {
+			if sub.Flags().Lookup(flag) == nil {
+				t.Errorf("%s command should have --%s flag", name, flag)
+			}
+		}
+	}
+}
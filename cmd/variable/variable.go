@@ -1,6 +1,7 @@
 package variable
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -8,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
 
@@ -33,27 +35,14 @@ func NewCmdVariable() *cobra.Command {
 	cmd.AddCommand(newCmdUpdate())
 	cmd.AddCommand(newCmdDelete())
 
+	cmdutil.AddRepoFlag(cmd)
 	return cmd
 }
 
 // listVariables fetches all pipeline variables for a repository.
-func listVariables(client *api.Client, repo string) ([]Variable, error) {
+func listVariables(ctx context.Context, client *api.Client, repo string) ([]Variable, error) {
 	path := fmt.Sprintf("/repositories/%s/pipelines_config/variables?pagelen=100", repo)
-	data, err := client.Get(path)
-	if err != nil {
-		return nil, err
-	}
-
-	var paginated api.PaginatedResponse
-	if err := json.Unmarshal(data, &paginated); err != nil {
-		return nil, err
-	}
-
-	var variables []Variable
-	if err := json.Unmarshal(paginated.Values, &variables); err != nil {
-		return nil, err
-	}
-	return variables, nil
+	return api.GetAll[Variable](ctx, client, path, 0)
 }
 
 // findVariableByKey searches the variable list for a matching key.
@@ -67,26 +56,26 @@ func findVariableByKey(variables []Variable, key string) (*Variable, error) {
 }
 
 func newCmdList() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
-		Use:   "list <workspace/repo-slug>",
+		Use:   "list [workspace/repo-slug]",
 		Short: "List pipeline variables",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := api.NewClient()
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			variables, err := listVariables(client, args[0])
+			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(variables)
-				return nil
+			path := fmt.Sprintf("/repositories/%s/pipelines_config/variables?pagelen=100&page=%d", args[0], pagination.Page)
+			variables, raw, _, err := cmdutil.FetchListWithRaw[Variable](cmd.Context(), client, path, &pagination)
+			if err != nil {
+				return err
 			}
 
 			table := output.NewTable("KEY", "VALUE", "SECURED", "UUID")
@@ -97,28 +86,31 @@ func newCmdList() *cobra.Command {
 				}
 				table.AddRow(v.Key, value, fmt.Sprintf("%v", v.Secured), v.UUID)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(variables, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdGet() *cobra.Command {
-	var jsonOut bool
-
 	cmd := &cobra.Command{
-		Use:   "get <workspace/repo-slug> <variable-key>",
+		Use:   "get [workspace/repo-slug] <variable-key>",
 		Short: "Get a pipeline variable by key",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
-			variables, err := listVariables(client, args[0])
+			variables, err := listVariables(cmd.Context(), client, args[0])
 			if err != nil {
 				return err
 			}
@@ -128,9 +120,8 @@ func newCmdGet() *cobra.Command {
 				return err
 			}
 
-			if jsonOut {
-				output.PrintJSON(v)
-				return nil
+			if !output.IsTable() {
+				return output.Print(v)
 			}
 
 			value := v.Value
@@ -144,7 +135,8 @@ func newCmdGet() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
 
@@ -154,10 +146,14 @@ func newCmdSet() *cobra.Command {
 	var secured bool
 
 	cmd := &cobra.Command{
-		Use:   "set <workspace/repo-slug>",
+		Use:   "set [workspace/repo-slug]",
 		Short: "Create a new pipeline variable",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
@@ -171,7 +167,7 @@ func newCmdSet() *cobra.Command {
 			jsonBody, _ := json.Marshal(body)
 
 			path := fmt.Sprintf("/repositories/%s/pipelines_config/variables", args[0])
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -190,6 +186,7 @@ func newCmdSet() *cobra.Command {
 	cmd.Flags().BoolVar(&secured, "secured", false, "Mark variable as secured")
 	cmd.MarkFlagRequired("key")
 	cmd.MarkFlagRequired("value")
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
@@ -199,17 +196,21 @@ func newCmdUpdate() *cobra.Command {
 	var secured bool
 
 	cmd := &cobra.Command{
-		Use:   "update <workspace/repo-slug>",
+		Use:   "update [workspace/repo-slug]",
 		Short: "Update an existing pipeline variable",
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
 			// List variables to find UUID by key
-			variables, err := listVariables(client, args[0])
+			variables, err := listVariables(cmd.Context(), client, args[0])
 			if err != nil {
 				return err
 			}
@@ -228,7 +229,7 @@ func newCmdUpdate() *cobra.Command {
 
 			path := fmt.Sprintf("/repositories/%s/pipelines_config/variables/%s",
 				args[0], url.PathEscape(existing.UUID))
-			data, err := client.Put(path, string(jsonBody))
+			data, err := client.PutContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -247,22 +248,27 @@ func newCmdUpdate() *cobra.Command {
 	cmd.Flags().BoolVar(&secured, "secured", false, "Mark variable as secured")
 	cmd.MarkFlagRequired("key")
 	cmd.MarkFlagRequired("value")
+	cmdutil.SetRepoArgs(cmd, 0)
 	return cmd
 }
 
 func newCmdDelete() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <workspace/repo-slug> <variable-key>",
+		Use:   "delete [workspace/repo-slug] <variable-key>",
 		Short: "Delete a pipeline variable",
-		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := cmdutil.ResolveRepoArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			client, err := api.NewClient()
 			if err != nil {
 				return err
 			}
 
 			// List variables to find UUID by key
-			variables, err := listVariables(client, args[0])
+			variables, err := listVariables(cmd.Context(), client, args[0])
 			if err != nil {
 				return err
 			}
@@ -274,7 +280,7 @@ func newCmdDelete() *cobra.Command {
 
 			path := fmt.Sprintf("/repositories/%s/pipelines_config/variables/%s",
 				args[0], url.PathEscape(existing.UUID))
-			_, err = client.Delete(path)
+			_, err = client.DeleteContext(cmd.Context(), path)
 			if err != nil {
 				return err
 			}
@@ -283,5 +289,6 @@ func newCmdDelete() *cobra.Command {
 			return nil
 		},
 	}
+	cmdutil.SetRepoArgs(cmd, 1)
 	return cmd
 }
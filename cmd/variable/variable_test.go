@@ -61,6 +61,19 @@ func TestNewCmdList_HasJSONFlag(t *testing.T) {
 	}
 }
 
+func TestNewCmdList_HasPaginationFlags(t *testing.T) {
+	cmd := NewCmdVariable()
+	listCmd, _, err := cmd.Find([]string{"list"})
+	if err != nil {
+		t.Fatalf("failed to find list command: %v", err)
+	}
+	for _, name := range []string{"page", "all", "limit"} {
+		if listCmd.Flags().Lookup(name) == nil {
+			t.Errorf("list command should have --%s flag", name)
+		}
+	}
+}
+
 func TestNewCmdGet_RequiresArgs(t *testing.T) {
 	cmd := NewCmdVariable()
 	getCmd, _, err := cmd.Find([]string{"get"})
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 	"github.com/PhilipKram/bitbucket-cli/internal/completion"
 	"github.com/PhilipKram/bitbucket-cli/internal/output"
 )
@@ -65,7 +66,7 @@ func NewCmdWorkspace() *cobra.Command {
 }
 
 func newCmdList() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -75,35 +76,21 @@ func newCmdList() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			data, err := client.Get("/workspaces?pagelen=50")
+			path := fmt.Sprintf("/workspaces?pagelen=50&page=%d", pagination.Page)
+			workspaces, raw, _, err := cmdutil.FetchListWithRaw[Workspace](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var workspaces []Workspace
-			if err := json.Unmarshal(paginated.Values, &workspaces); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(workspaces)
-				return nil
-			}
-
 			table := output.NewTable("NAME", "SLUG", "PRIVATE")
 			for _, w := range workspaces {
 				table.AddRow(w.Name, w.Slug, fmt.Sprintf("%v", w.IsPrivate))
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(workspaces, raw), table)
 		},
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
@@ -117,7 +104,7 @@ func newCmdView() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			data, err := client.Get(fmt.Sprintf("/workspaces/%s", url.PathEscape(args[0])))
+			data, err := client.GetContext(cmd.Context(), fmt.Sprintf("/workspaces/%s", url.PathEscape(args[0])))
 			if err != nil {
 				return err
 			}
@@ -125,6 +112,9 @@ func newCmdView() *cobra.Command {
 			if err := json.Unmarshal(data, &ws); err != nil {
 				return err
 			}
+			if !output.IsTable() {
+				return output.Print(output.WithRaw(ws, json.RawMessage(data)))
+			}
 			output.PrintMessage("Name:    %s", ws.Name)
 			output.PrintMessage("Slug:    %s", ws.Slug)
 			output.PrintMessage("UUID:    %s", ws.UUID)
@@ -134,11 +124,12 @@ func newCmdView() *cobra.Command {
 		},
 		ValidArgsFunction: completion.WorkspaceNamesWithDescriptions,
 	}
+	cmdutil.AddJSONFlag(cmd)
 	return cmd
 }
 
 func newCmdMembers() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "members <workspace-slug>",
@@ -149,42 +140,27 @@ func newCmdMembers() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/workspaces/%s/members?pagelen=50", url.PathEscape(args[0]))
-			data, err := client.Get(path)
+			path := fmt.Sprintf("/workspaces/%s/members?pagelen=50&page=%d", url.PathEscape(args[0]), pagination.Page)
+			members, raw, _, err := cmdutil.FetchListWithRaw[WorkspaceMember](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var members []WorkspaceMember
-			if err := json.Unmarshal(paginated.Values, &members); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(members)
-				return nil
-			}
-
 			table := output.NewTable("DISPLAY NAME", "NICKNAME", "UUID")
 			for _, m := range members {
 				table.AddRow(m.User.DisplayName, m.User.Nickname, m.User.UUID)
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(members, raw), table)
 		},
 		ValidArgsFunction: completion.WorkspaceNamesWithDescriptions,
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
 func newCmdProjects() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "projects <workspace-slug>",
@@ -195,37 +171,22 @@ func newCmdProjects() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/workspaces/%s/projects?pagelen=50", url.PathEscape(args[0]))
-			data, err := client.Get(path)
+			path := fmt.Sprintf("/workspaces/%s/projects?pagelen=50&page=%d", url.PathEscape(args[0]), pagination.Page)
+			projects, raw, _, err := cmdutil.FetchListWithRaw[Project](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
 
-			var paginated api.PaginatedResponse
-			if err := json.Unmarshal(data, &paginated); err != nil {
-				return err
-			}
-
-			var projects []Project
-			if err := json.Unmarshal(paginated.Values, &projects); err != nil {
-				return err
-			}
-
-			if jsonOut {
-				output.PrintJSON(projects)
-				return nil
-			}
-
 			table := output.NewTable("KEY", "NAME", "DESCRIPTION", "PRIVATE")
 			for _, p := range projects {
 				table.AddRow(p.Key, p.Name, output.Truncate(p.Description, 40), fmt.Sprintf("%v", p.IsPrivate))
 			}
-			table.Print()
-			return nil
+			return output.Render(output.WithRaw(projects, raw), table)
 		},
 		ValidArgsFunction: completion.WorkspaceNamesWithDescriptions,
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
 
@@ -250,7 +211,7 @@ func newCmdProjectCreate() *cobra.Command {
 			}
 			jsonBody, _ := json.Marshal(body)
 			path := fmt.Sprintf("/workspaces/%s/projects", url.PathEscape(args[0]))
-			data, err := client.Post(path, string(jsonBody))
+			data, err := client.PostContext(cmd.Context(), path, string(jsonBody))
 			if err != nil {
 				return err
 			}
@@ -270,7 +231,7 @@ func newCmdProjectCreate() *cobra.Command {
 }
 
 func newCmdPermissions() *cobra.Command {
-	var jsonOut bool
+	var pagination cmdutil.PaginationOptions
 
 	cmd := &cobra.Command{
 		Use:   "permissions <workspace-slug>",
@@ -281,26 +242,16 @@ func newCmdPermissions() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			path := fmt.Sprintf("/workspaces/%s/permissions?pagelen=50", url.PathEscape(args[0]))
-			data, err := client.Get(path)
+			path := fmt.Sprintf("/workspaces/%s/permissions?pagelen=50&page=%d", url.PathEscape(args[0]), pagination.Page)
+			permissions, _, err := cmdutil.FetchList[json.RawMessage](cmd.Context(), client, path, &pagination)
 			if err != nil {
 				return err
 			}
-
-			if jsonOut {
-				var raw interface{}
-				json.Unmarshal(data, &raw)
-				output.PrintJSON(raw)
-				return nil
-			}
-
-			var raw interface{}
-			json.Unmarshal(data, &raw)
-			output.PrintJSON(raw)
-			return nil
+			return output.Print(permissions)
 		},
 		ValidArgsFunction: completion.WorkspaceNamesWithDescriptions,
 	}
-	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmdutil.AddJSONFlag(cmd)
+	cmdutil.AddPaginationFlags(cmd, &pagination)
 	return cmd
 }
@@ -138,3 +138,18 @@ func TestNewCmdPermissions_HasJsonFlag(t *testing.T) {
 		t.Error("permissions command should have --json flag")
 	}
 }
+
+func TestListCommands_HavePaginationFlags(t *testing.T) {
+	for _, name := range []string{"list", "members", "projects", "permissions"} {
+		cmd := NewCmdWorkspace()
+		sub, _, err := cmd.Find([]string{name})
+		if err != nil {
+			t.Fatalf("failed to find %s command: %v", name, err)
+		}
+		for _, flag := range []string{"page", "all", "limit"} {
+			if sub.Flags().Lookup(flag) == nil {
+				t.Errorf("%s command should have --%s flag", name, flag)
+			}
+		}
+	}
+}
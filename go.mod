@@ -2,9 +2,17 @@ module github.com/PhilipKram/bitbucket-cli
 
 go 1.24.7
 
-require github.com/spf13/cobra v1.10.2
+require (
+	github.com/alecthomas/chroma/v2 v2.23.1
+	github.com/itchyny/gojq v0.12.17
+	github.com/spf13/cobra v1.10.2
+	golang.org/x/term v0.36.0
+)
 
 require (
+	github.com/dlclark/regexp2 v1.11.5 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/itchyny/timefmt-go v0.1.6 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/sys v0.37.0 // indirect
 )
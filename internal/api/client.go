@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -26,6 +27,9 @@ type Client struct {
 	httpClient *http.Client
 	token      *config.TokenData
 	cfg        *config.Config
+	baseURL    string
+	retry      RetryPolicy
+	sleep      func(time.Duration)
 }
 
 // PaginatedResponse is the standard paginated response envelope from Bitbucket.
@@ -57,14 +61,17 @@ func NewClient() (*Client, error) {
 	oauthKey := os.Getenv("BB_OAUTH_KEY")
 	oauthSecret := os.Getenv("BB_OAUTH_SECRET")
 	if oauthKey != "" && oauthSecret != "" {
-		token, err := auth.ClientCredentialsLogin(oauthKey, oauthSecret)
+		cfg := &config.Config{OAuthKey: oauthKey, OAuthSecret: oauthSecret}
+		token, err := auth.EndpointsFor(cfg).ClientCredentialsLogin(oauthKey, oauthSecret)
 		if err != nil {
 			return nil, fmt.Errorf("failed to authenticate with BB_OAUTH_KEY/BB_OAUTH_SECRET: %w", err)
 		}
 		return &Client{
 			httpClient: &http.Client{Timeout: timeout},
 			token:      token,
-			cfg:        &config.Config{OAuthKey: oauthKey, OAuthSecret: oauthSecret},
+			cfg:        cfg,
+			baseURL:    cfg.APIBaseURL(),
+			retry:      DefaultRetryPolicy(),
 		}, nil
 	}
 
@@ -75,6 +82,9 @@ func NewClient() (*Client, error) {
 
 	token, err := config.LoadToken()
 	if err != nil {
+		if profile := config.ActiveProfile(); profile != config.DefaultProfile {
+			return nil, fmt.Errorf("not authenticated for profile %q. Run 'bb auth login --profile %s' first", profile, profile)
+		}
 		return nil, fmt.Errorf("not authenticated. Run 'bb auth login' first")
 	}
 
@@ -82,11 +92,14 @@ func NewClient() (*Client, error) {
 		httpClient: &http.Client{Timeout: timeout},
 		token:      token,
 		cfg:        cfg,
+		baseURL:    cfg.APIBaseURL(),
+		retry:      DefaultRetryPolicy(),
 	}, nil
 }
 
 // NewClientFromToken creates a Client from a raw access token string.
-// No disk I/O or env vars are read. Token refresh is not supported — if the
+// No disk I/O is performed; only the BB_HTTP_TIMEOUT, BB_MAX_RETRIES and
+// BB_API_URL env vars are read. Token refresh is not supported — if the
 // token expires, Bitbucket returns 401 and the caller must re-authenticate.
 // Intended for MCP OAuth mode where each request carries its own token.
 func NewClientFromToken(accessToken string) *Client {
@@ -96,20 +109,25 @@ func NewClientFromToken(accessToken string) *Client {
 			timeout = time.Duration(secs) * time.Second
 		}
 	}
+	cfg := &config.Config{}
 	return &Client{
 		httpClient: &http.Client{Timeout: timeout},
 		token:      &config.TokenData{AccessToken: accessToken},
-		cfg:        &config.Config{},
+		cfg:        cfg,
+		baseURL:    cfg.APIBaseURL(),
+		retry:      DefaultRetryPolicy(),
 	}
 }
 
 // NewClientWith creates a Client from externally provided config, token, and HTTP client.
 // This is intended for testing and advanced usage where you don't want to read from disk.
+// Retries are disabled; enable them with SetRetryPolicy.
 func NewClientWith(httpClient *http.Client, cfg *config.Config, token *config.TokenData) *Client {
 	return &Client{
 		httpClient: httpClient,
 		token:      token,
 		cfg:        cfg,
+		baseURL:    cfg.APIBaseURL(),
 	}
 }
 
@@ -118,12 +136,24 @@ func (c *Client) GetConfig() *config.Config {
 	return c.cfg
 }
 
+// BaseURL returns the API base URL that request paths are resolved against.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
 func (c *Client) setAuth(req *http.Request) {
 	req.Header.Set("Authorization", "Bearer "+c.token.AccessToken)
 }
 
-func (c *Client) doRequest(method, urlStr string, body io.Reader, contentType string) (*http.Response, error) {
-	// Buffer the body so it can be replayed on 401 retry.
+// doRequest sends a request, refreshing the access token once on 401 and
+// retrying transient failures according to the client's RetryPolicy.
+// Cancelling ctx aborts both in-flight requests and pending retry waits.
+func (c *Client) doRequest(ctx context.Context, method, urlStr string, body io.Reader, contentType string) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Buffer the body so it can be replayed on 401 refresh and retries.
 	var bodyBytes []byte
 	if body != nil {
 		var err error
@@ -133,43 +163,85 @@ func (c *Client) doRequest(method, urlStr string, body io.Reader, contentType st
 		}
 	}
 
-	req, err := http.NewRequest(method, urlStr, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, err
-	}
-	c.setAuth(req)
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, errors.NetworkError(err)
-	}
-
-	// Attempt token refresh on 401
-	if resp.StatusCode == http.StatusUnauthorized && c.token.RefreshToken != "" {
-		resp.Body.Close()
-		if err := c.refreshToken(); err != nil {
-			return nil, fmt.Errorf("session expired, please run 'bb auth login' again: %w", err)
-		}
-		// Retry the request with the new token and a fresh body reader
-		req2, err := http.NewRequest(method, urlStr, bytes.NewReader(bodyBytes))
+	retryable := c.retry.allowsMethod(method)
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, urlStr, bytes.NewReader(bodyBytes))
 		if err != nil {
 			return nil, err
 		}
-		c.setAuth(req2)
+		c.setAuth(req)
 		if contentType != "" {
-			req2.Header.Set("Content-Type", contentType)
+			req.Header.Set("Content-Type", contentType)
 		}
-		resp2, err := c.httpClient.Do(req2)
+
+		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if retryable && attempt < c.retry.MaxAttempts {
+				if err := c.wait(ctx, c.retry.backoff(attempt)); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			return nil, errors.NetworkError(err)
 		}
-		return resp2, nil
+
+		// Attempt token refresh on 401, then replay the request once
+		if resp.StatusCode == http.StatusUnauthorized && c.token.RefreshToken != "" && !refreshed {
+			resp.Body.Close()
+			if err := c.refreshToken(); err != nil {
+				return nil, fmt.Errorf("session expired, please run 'bb auth login' again: %w", err)
+			}
+			refreshed = true
+			attempt--
+			continue
+		}
+
+		if retryable && attempt < c.retry.MaxAttempts {
+			if delay, ok, fromServer := c.retry.retryDelay(attempt, resp); ok && (!fromServer || fitsDeadline(ctx, delay)) {
+				if fromServer && delay >= serverDelayNotice {
+					fmt.Fprintf(os.Stderr, "Bitbucket asked to wait %s before retrying (press Ctrl-C to cancel)\n", delay.Round(time.Second))
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if err := c.wait(ctx, delay); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		return resp, nil
 	}
+}
 
-	return resp, nil
+// serverDelayNotice is the shortest server-requested wait that is announced
+// on stderr, so that long rate limit waits do not look like a hang.
+const serverDelayNotice = 5 * time.Second
+
+// fitsDeadline reports whether waiting d leaves ctx time to retry.
+func fitsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > d
+}
+
+// wait blocks for d or until ctx is done, returning ctx.Err() in the latter case.
+func (c *Client) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		c.sleep(d)
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (c *Client) refreshToken() error {
@@ -178,7 +250,7 @@ func (c *Client) refreshToken() error {
 		return fmt.Errorf("OAuth credentials not configured")
 	}
 	oldRefresh := c.token.RefreshToken
-	newToken, err := auth.RefreshAccessToken(cfg.OAuthKey, cfg.OAuthSecret, oldRefresh)
+	newToken, err := auth.EndpointsFor(cfg).RefreshAccessToken(cfg.OAuthKey, cfg.OAuthSecret, oldRefresh)
 	if err != nil {
 		return err
 	}
@@ -192,18 +264,22 @@ func (c *Client) refreshToken() error {
 
 // Get performs a GET request to the Bitbucket API.
 func (c *Client) Get(path string) ([]byte, error) {
-	u := config.BitbucketAPI + path
-	resp, err := c.doRequest("GET", u, nil, "")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	return handleResponse(resp)
+	return c.GetContext(context.Background(), path)
+}
+
+// GetContext is like Get but aborts the request when ctx is cancelled.
+func (c *Client) GetContext(ctx context.Context, path string) ([]byte, error) {
+	return c.GetRawContext(ctx, c.baseURL+path)
 }
 
 // GetRaw performs a GET to an absolute URL (for pagination "next" links).
 func (c *Client) GetRaw(rawURL string) ([]byte, error) {
-	resp, err := c.doRequest("GET", rawURL, nil, "")
+	return c.GetRawContext(context.Background(), rawURL)
+}
+
+// GetRawContext is like GetRaw but aborts the request when ctx is cancelled.
+func (c *Client) GetRawContext(ctx context.Context, rawURL string) ([]byte, error) {
+	resp, err := c.doRequest(ctx, "GET", rawURL, nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -215,14 +291,19 @@ func (c *Client) GetRaw(rawURL string) ([]byte, error) {
 // body with Content-Type "application/json"; otherwise, no request body or
 // Content-Type header is sent.
 func (c *Client) Post(path string, jsonBody string) ([]byte, error) {
-	u := config.BitbucketAPI + path
+	return c.PostContext(context.Background(), path, jsonBody)
+}
+
+// PostContext is like Post but aborts the request when ctx is cancelled.
+func (c *Client) PostContext(ctx context.Context, path string, jsonBody string) ([]byte, error) {
+	u := c.baseURL + path
 	var body io.Reader
 	var contentType string
 	if jsonBody != "" {
 		body = strings.NewReader(jsonBody)
 		contentType = "application/json"
 	}
-	resp, err := c.doRequest("POST", u, body, contentType)
+	resp, err := c.doRequest(ctx, "POST", u, body, contentType)
 	if err != nil {
 		return nil, err
 	}
@@ -232,8 +313,13 @@ func (c *Client) Post(path string, jsonBody string) ([]byte, error) {
 
 // PostForm performs a POST with form-encoded body.
 func (c *Client) PostForm(path string, data url.Values) ([]byte, error) {
-	u := config.BitbucketAPI + path
-	resp, err := c.doRequest("POST", u, strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
+	return c.PostFormContext(context.Background(), path, data)
+}
+
+// PostFormContext is like PostForm but aborts the request when ctx is cancelled.
+func (c *Client) PostFormContext(ctx context.Context, path string, data url.Values) ([]byte, error) {
+	u := c.baseURL + path
+	resp, err := c.doRequest(ctx, "POST", u, strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
 	if err != nil {
 		return nil, err
 	}
@@ -243,8 +329,13 @@ func (c *Client) PostForm(path string, data url.Values) ([]byte, error) {
 
 // Put performs a PUT with JSON body.
 func (c *Client) Put(path string, jsonBody string) ([]byte, error) {
-	u := config.BitbucketAPI + path
-	resp, err := c.doRequest("PUT", u, strings.NewReader(jsonBody), "application/json")
+	return c.PutContext(context.Background(), path, jsonBody)
+}
+
+// PutContext is like Put but aborts the request when ctx is cancelled.
+func (c *Client) PutContext(ctx context.Context, path string, jsonBody string) ([]byte, error) {
+	u := c.baseURL + path
+	resp, err := c.doRequest(ctx, "PUT", u, strings.NewReader(jsonBody), "application/json")
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +346,14 @@ func (c *Client) Put(path string, jsonBody string) ([]byte, error) {
 // PostMultipart performs a multipart/form-data POST request, typically used for file uploads.
 // It streams the file content through an io.Pipe to avoid buffering the entire file in memory.
 func (c *Client) PostMultipart(path, fieldName, fileName string, fileReader io.Reader) ([]byte, error) {
+	return c.PostMultipartContext(context.Background(), path, fieldName, fileName, fileReader)
+}
+
+// PostMultipartContext is like PostMultipart but aborts the upload when ctx is cancelled.
+func (c *Client) PostMultipartContext(ctx context.Context, path, fieldName, fileName string, fileReader io.Reader) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	pr, pw := io.Pipe()
 	writer := multipart.NewWriter(pw)
 
@@ -274,12 +373,12 @@ func (c *Client) PostMultipart(path, fieldName, fileName string, fileReader io.R
 		errCh <- writer.Close()
 	}()
 
-	u := config.BitbucketAPI + path
+	u := c.baseURL + path
 	contentType := writer.FormDataContentType()
 
 	// Build the request manually since doRequest buffers the body (needed for 401 retry),
 	// but for large uploads we accept that a 401 retry will fail.
-	req, err := http.NewRequest("POST", u, pr)
+	req, err := http.NewRequestWithContext(ctx, "POST", u, pr)
 	if err != nil {
 		return nil, err
 	}
@@ -288,6 +387,9 @@ func (c *Client) PostMultipart(path, fieldName, fileName string, fileReader io.R
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, errors.NetworkError(err)
 	}
 	defer resp.Body.Close()
@@ -307,8 +409,13 @@ func (c *Client) PostMultipart(path, fieldName, fileName string, fileReader io.R
 
 // Delete performs a DELETE request.
 func (c *Client) Delete(path string) ([]byte, error) {
-	u := config.BitbucketAPI + path
-	resp, err := c.doRequest("DELETE", u, nil, "")
+	return c.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is like Delete but aborts the request when ctx is cancelled.
+func (c *Client) DeleteContext(ctx context.Context, path string) ([]byte, error) {
+	u := c.baseURL + path
+	resp, err := c.doRequest(ctx, "DELETE", u, nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -322,32 +429,18 @@ func (c *Client) Delete(path string) ([]byte, error) {
 
 // GetPaginated fetches a single page of paginated results from the Bitbucket API
 // and decodes them directly into a slice of T using a streaming JSON decoder.
+// Use NewPaginator or GetAll to follow "next" links across pages.
 func GetPaginated[T any](c *Client, path string) ([]T, error) {
-	u := config.BitbucketAPI + path
-	resp, err := c.doRequest("GET", u, nil, "")
+	return GetPaginatedContext[T](context.Background(), c, path)
+}
+
+// GetPaginatedContext is like GetPaginated but aborts the request when ctx is cancelled.
+func GetPaginatedContext[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	values, _, err := getPage[T](ctx, c, c.baseURL+path)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read error response: %w", err)
-		}
-		return nil, errors.ParseAPIError(resp, body)
-	}
-
-	var result struct {
-		Values []T `json:"values"`
-	}
-
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode paginated response: %w", err)
-	}
-
-	return result.Values, nil
+	return values, nil
 }
 
 func handleResponse(resp *http.Response) ([]byte, error) {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
@@ -124,7 +125,7 @@ func TestClient_Post_EmptyBody(t *testing.T) {
 	})
 
 	// Use doRequest directly since Post prepends BitbucketAPI
-	resp, err := client.doRequest("POST", server.URL+"/approve", nil, "")
+	resp, err := client.doRequest(context.Background(), "POST", server.URL+"/approve", nil, "")
 	if err != nil {
 		t.Fatalf("Post() error: %v", err)
 	}
@@ -152,7 +153,7 @@ func TestClient_Post_WithJsonBody(t *testing.T) {
 		AccessToken: "test-token",
 	})
 
-	resp, err := client.doRequest("POST", server.URL+"/test", strings.NewReader(`{"title":"test"}`), "application/json")
+	resp, err := client.doRequest(context.Background(), "POST", server.URL+"/test", strings.NewReader(`{"title":"test"}`), "application/json")
 	if err != nil {
 		t.Fatalf("Post() error: %v", err)
 	}
@@ -448,7 +449,7 @@ func TestClient_Delete_NoContent(t *testing.T) {
 	})
 
 	// Use doRequest directly since Delete prepends BitbucketAPI
-	resp, err := client.doRequest("DELETE", server.URL+"/test", nil, "")
+	resp, err := client.doRequest(context.Background(), "DELETE", server.URL+"/test", nil, "")
 	if err != nil {
 		t.Fatalf("Delete() error: %v", err)
 	}
@@ -811,7 +812,7 @@ func TestClient_Put_Success(t *testing.T) {
 	})
 
 	// Use doRequest directly to bypass BitbucketAPI prefix
-	resp, err := client.doRequest("PUT", server.URL+"/test", strings.NewReader(`{"name":"updated"}`), "application/json")
+	resp, err := client.doRequest(context.Background(), "PUT", server.URL+"/test", strings.NewReader(`{"name":"updated"}`), "application/json")
 	if err != nil {
 		t.Fatalf("Put() error: %v", err)
 	}
@@ -851,7 +852,7 @@ func TestClient_PostForm_Success(t *testing.T) {
 	data.Set("key", "value")
 
 	// Use doRequest directly to bypass BitbucketAPI prefix
-	resp, err := client.doRequest("POST", server.URL+"/test", strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
+	resp, err := client.doRequest(context.Background(), "POST", server.URL+"/test", strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
 	if err != nil {
 		t.Fatalf("PostForm() error: %v", err)
 	}
@@ -904,7 +905,7 @@ func TestClient_Post_Success(t *testing.T) {
 		AccessToken: "test-token",
 	})
 
-	resp, err := client.doRequest("POST", server.URL+"/test", strings.NewReader(`{"test":"data"}`), "application/json")
+	resp, err := client.doRequest(context.Background(), "POST", server.URL+"/test", strings.NewReader(`{"test":"data"}`), "application/json")
 	if err != nil {
 		t.Fatalf("Post() error: %v", err)
 	}
@@ -936,7 +937,7 @@ func TestClient_DoRequest_NilBody(t *testing.T) {
 		AccessToken: "test-token",
 	})
 
-	resp, err := client.doRequest("GET", server.URL+"/test", nil, "")
+	resp, err := client.doRequest(context.Background(), "GET", server.URL+"/test", nil, "")
 	if err != nil {
 		t.Fatalf("doRequest() with nil body error: %v", err)
 	}
@@ -963,7 +964,7 @@ func TestClient_DoRequest_WithContentType(t *testing.T) {
 		AccessToken: "test-token",
 	})
 
-	resp, err := client.doRequest("POST", server.URL+"/test", strings.NewReader("plain text"), "text/plain")
+	resp, err := client.doRequest(context.Background(), "POST", server.URL+"/test", strings.NewReader("plain text"), "text/plain")
 	if err != nil {
 		t.Fatalf("doRequest() error: %v", err)
 	}
@@ -1087,7 +1088,7 @@ func TestClient_Delete_WithBody(t *testing.T) {
 		AccessToken: "test-token",
 	})
 
-	resp, err := client.doRequest("DELETE", server.URL+"/test", nil, "")
+	resp, err := client.doRequest(context.Background(), "DELETE", server.URL+"/test", nil, "")
 	if err != nil {
 		t.Fatalf("Delete() error: %v", err)
 	}
@@ -1301,7 +1302,7 @@ func TestClient_AllHTTPMethods(t *testing.T) {
 				body = strings.NewReader(`{"test":"data"}`)
 			}
 
-			resp, err := client.doRequest(method, server.URL+"/test", body, "application/json")
+			resp, err := client.doRequest(context.Background(), method, server.URL+"/test", body, "application/json")
 			if err != nil {
 				t.Fatalf("%s request error: %v", method, err)
 			}
@@ -1586,3 +1587,67 @@ func TestNewClientFromToken_NoRefresh(t *testing.T) {
 		t.Error("Expected empty OAuth credentials on token-based client")
 	}
 }
+
+func TestGetContext_Cancelled(t *testing.T) {
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		},
+	}
+	client := NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetContext(ctx, "/user")
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestGetContext_CancelDuringRetryWait(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 503},
+		{status: 200, body: `{}`},
+	}}
+	client := NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetContext(ctx, "/user")
+	if !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if transport.calls() != 1 {
+		t.Errorf("expected 1 attempt before cancellation, got %d", transport.calls())
+	}
+}
+
+func TestClient_Get_WithConfiguredBaseURL(t *testing.T) {
+	t.Setenv("BB_API_URL", "")
+	var gotURL string
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			gotURL = req.URL.String()
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+	cfg := &config.Config{APIURL: "http://localhost:8080/2.0/"}
+	client := NewClientWith(&http.Client{Transport: transport}, cfg, &config.TokenData{AccessToken: "test-token"})
+
+	if _, err := client.Get("/user"); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if gotURL != "http://localhost:8080/2.0/user" {
+		t.Errorf("request URL = %q, want configured base URL", gotURL)
+	}
+	if client.BaseURL() != "http://localhost:8080/2.0" {
+		t.Errorf("BaseURL() = %q", client.BaseURL())
+	}
+}
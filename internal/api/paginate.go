@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+)
+
+// Paginator walks a paginated Bitbucket collection by following the "next"
+// links of each PaginatedResponse envelope.
+type Paginator[T any] struct {
+	client  *Client
+	nextURL string
+	limit   int
+	fetched int
+}
+
+// NewPaginator creates a Paginator starting at the given API path (relative to
+// the client's API base URL, including any query string). A limit greater than zero
+// caps the total number of items returned across all pages.
+func NewPaginator[T any](c *Client, path string, limit int) *Paginator[T] {
+	return &Paginator[T]{
+		client:  c,
+		nextURL: c.baseURL + path,
+		limit:   limit,
+	}
+}
+
+// HasNext reports whether another page can be fetched.
+func (p *Paginator[T]) HasNext() bool {
+	if p.limit > 0 && p.fetched >= p.limit {
+		return false
+	}
+	return p.nextURL != ""
+}
+
+// Next fetches the next page of results. It returns an empty slice once the
+// collection is exhausted or the item limit has been reached.
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if !p.HasNext() {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	values, next, err := getPage[T](ctx, p.client, p.nextURL)
+	if err != nil {
+		return nil, err
+	}
+	p.nextURL = next
+
+	if p.limit > 0 && p.fetched+len(values) > p.limit {
+		values = values[:p.limit-p.fetched]
+	}
+	p.fetched += len(values)
+	return values, nil
+}
+
+// All fetches every remaining page and returns the concatenated results,
+// stopping early when the item limit is reached or ctx is cancelled.
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		values, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, values...)
+	}
+	return all, nil
+}
+
+// GetAll fetches all pages of a paginated collection starting at path, up to
+// limit items (zero means no limit).
+func GetAll[T any](ctx context.Context, c *Client, path string, limit int) ([]T, error) {
+	return NewPaginator[T](c, path, limit).All(ctx)
+}
+
+// getPage fetches a single page from an absolute URL and decodes its values
+// and "next" link using a streaming JSON decoder.
+func getPage[T any](ctx context.Context, c *Client, urlStr string) ([]T, string, error) {
+	resp, err := c.doRequest(ctx, "GET", urlStr, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read error response: %w", err)
+		}
+		return nil, "", errors.ParseAPIError(resp, body)
+	}
+
+	var result struct {
+		Next   string `json:"next"`
+		Values []T    `json:"values"`
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("failed to decode paginated response: %w", err)
+	}
+
+	return result.Values, result.Next, nil
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+)
+
+type pageItem struct {
+	ID int `json:"id"`
+}
+
+// newPagedClient returns a client whose transport serves totalPages pages of
+// pageLen items each, linked together via "next" URLs.
+func newPagedClient(t *testing.T, totalPages, pageLen int, calls *int32) *Client {
+	t.Helper()
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(calls, 1)
+			page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			values := make([]pageItem, pageLen)
+			for i := range values {
+				values[i] = pageItem{ID: (page-1)*pageLen + i + 1}
+			}
+			resp := map[string]interface{}{"page": page, "values": values}
+			if page < totalPages {
+				resp["next"] = fmt.Sprintf("%s/items?page=%d", config.BitbucketAPI, page+1)
+			}
+			body, _ := json.Marshal(resp)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+	return NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+}
+
+func TestGetAll_FollowsNextLinks(t *testing.T) {
+	var calls int32
+	client := newPagedClient(t, 3, 2, &calls)
+
+	items, err := GetAll[pageItem](context.Background(), client, "/items?page=1", 0)
+	if err != nil {
+		t.Fatalf("GetAll() error: %v", err)
+	}
+	if len(items) != 6 {
+		t.Fatalf("expected 6 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.ID != i+1 {
+			t.Errorf("items[%d].ID = %d, want %d", i, item.ID, i+1)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+}
+
+func TestGetAll_Limit(t *testing.T) {
+	var calls int32
+	client := newPagedClient(t, 5, 2, &calls)
+
+	items, err := GetAll[pageItem](context.Background(), client, "/items?page=1", 3)
+	if err != nil {
+		t.Fatalf("GetAll() error: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 requests, got %d", calls)
+	}
+}
+
+func TestPaginator_SinglePage(t *testing.T) {
+	var calls int32
+	client := newPagedClient(t, 2, 2, &calls)
+
+	p := NewPaginator[pageItem](client, "/items?page=1", 0)
+	items, err := p.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 items, got %d", len(items))
+	}
+	if !p.HasNext() {
+		t.Error("expected HasNext() to be true after first page")
+	}
+
+	if _, err := p.Next(context.Background()); err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	if p.HasNext() {
+		t.Error("expected HasNext() to be false after last page")
+	}
+}
+
+func TestPaginator_ContextCancelled(t *testing.T) {
+	var calls int32
+	client := newPagedClient(t, 3, 2, &calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GetAll[pageItem](ctx, client, "/items?page=1", 0)
+	if err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+	if calls != 0 {
+		t.Errorf("expected no requests after cancellation, got %d", calls)
+	}
+}
+
+func TestPaginator_ErrorResponse(t *testing.T) {
+	transport := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 404,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"type":"error","error":{"message":"Repository not found"}}`))),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+	client := NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+
+	_, err := GetAll[pageItem](context.Background(), client, "/items", 0)
+	if err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+)
+
+// RetryPolicy controls how the client retries transient failures such as
+// rate limiting (429), server errors (5xx) and network errors.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values of 1 or less disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the initial backoff delay, doubled on every attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay.
+	MaxDelay time.Duration
+
+	// MaxServerDelay caps the wait requested by the server through
+	// Retry-After or X-RateLimit-Reset. A longer wait, or one past the
+	// request context's deadline, aborts retrying instead.
+	MaxServerDelay time.Duration
+
+	// RetryNonIdempotent enables retries for POST and PATCH requests, which
+	// may otherwise be applied twice by the server.
+	RetryNonIdempotent bool
+}
+
+// Default retry settings. Override the attempt count with BB_MAX_RETRIES.
+const (
+	defaultMaxRetries = 2
+	defaultBaseDelay  = 500 * time.Millisecond
+	defaultMaxDelay   = 30 * time.Second
+
+	// Bitbucket's rate limits are hourly, so a reset can be up to an hour
+	// away.
+	defaultMaxServerDelay = time.Hour
+)
+
+// DefaultRetryPolicy returns the retry policy used by NewClient and
+// NewClientFromToken. BB_MAX_RETRIES sets the number of retries after the
+// first attempt (0 disables retries).
+func DefaultRetryPolicy() RetryPolicy {
+	retries := defaultMaxRetries
+	if env := os.Getenv("BB_MAX_RETRIES"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n >= 0 {
+			retries = n
+		}
+	}
+	return RetryPolicy{
+		MaxAttempts: retries + 1,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+
+		MaxServerDelay: defaultMaxServerDelay,
+	}
+}
+
+// SetRetryPolicy replaces the client's retry policy.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
+// allowsMethod reports whether requests with the given method may be retried.
+func (p RetryPolicy) allowsMethod(method string) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return p.RetryNonIdempotent
+	}
+}
+
+// backoff returns the jittered exponential delay before the given retry
+// attempt (1-based). The delay is drawn uniformly from [d/2, d].
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(d-half)+1))
+}
+
+// retryDelay determines how long to wait before retrying after resp. The
+// second return value is false if the response should not be retried. The
+// third is true when the wait was requested by the server.
+func (p RetryPolicy) retryDelay(attempt int, resp *http.Response) (time.Duration, bool, bool) {
+	if !isRetryableStatus(resp.StatusCode) {
+		return 0, false, false
+	}
+	if wait, ok := serverRetryDelay(resp, time.Now()); ok {
+		if wait > p.MaxServerDelay {
+			return 0, false, true
+		}
+		return wait, true, true
+	}
+	return p.backoff(attempt), true, false
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// serverRetryDelay extracts the wait requested by the server from the
+// Retry-After header (seconds or HTTP date) or, failing that, from
+// X-RateLimit-Reset (Unix epoch seconds or RFC 3339).
+func serverRetryDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(t.Sub(now)), true
+		}
+	}
+	if t, ok := errors.RateLimitReset(resp.Header); ok {
+		return nonNegative(t.Sub(now)), true
+	}
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
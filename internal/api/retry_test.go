@@ -0,0 +1,302 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+)
+
+// scriptedResponse describes one canned reply from scriptedTransport.
+type scriptedResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+	err     error
+}
+
+// scriptedTransport is an http.RoundTripper test double that replays a fixed
+// sequence of responses and records the requests it received.
+type scriptedTransport struct {
+	mu        sync.Mutex
+	responses []scriptedResponse
+	requests  []*http.Request
+	bodies    []string
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	s.requests = append(s.requests, req)
+	s.bodies = append(s.bodies, string(body))
+
+	idx := len(s.requests) - 1
+	if idx >= len(s.responses) {
+		idx = len(s.responses) - 1
+	}
+	r := s.responses[idx]
+	if r.err != nil {
+		return nil, r.err
+	}
+	header := make(http.Header)
+	for k, v := range r.headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader([]byte(r.body))),
+		Request:    req,
+	}, nil
+}
+
+func (s *scriptedTransport) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// newRetryClient returns a client using transport with the given policy and a
+// sleep function that records delays instead of blocking.
+func newRetryClient(transport http.RoundTripper, policy RetryPolicy) (*Client, *[]time.Duration) {
+	var delays []time.Duration
+	client := NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+	client.SetRetryPolicy(policy)
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+	return client, &delays
+}
+
+func testPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second, MaxServerDelay: time.Minute}
+}
+
+func TestRetry_ServerErrorThenSuccess(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 503, body: `{"error":{"message":"unavailable"}}`},
+		{status: 502},
+		{status: 200, body: `{"ok":true}`},
+	}}
+	client, delays := newRetryClient(transport, testPolicy())
+
+	data, err := client.GetRaw("https://api.example.com/test")
+	if err != nil {
+		t.Fatalf("GetRaw() error: %v", err)
+	}
+	if string(data) != `{"ok":true}` {
+		t.Errorf("unexpected body %q", data)
+	}
+	if transport.calls() != 3 {
+		t.Errorf("expected 3 attempts, got %d", transport.calls())
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("expected 2 backoff sleeps, got %d", len(*delays))
+	}
+	// Second delay is drawn from [100ms, 200ms]
+	if d := (*delays)[1]; d < 100*time.Millisecond || d > 200*time.Millisecond {
+		t.Errorf("second backoff %v outside jitter range", d)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 500, body: `{"error":{"message":"boom"}}`},
+	}}
+	client, _ := newRetryClient(transport, testPolicy())
+
+	_, err := client.GetRaw("https://api.example.com/test")
+	if err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	var bbErr *errors.BBError
+	if !stderrors.As(err, &bbErr) || bbErr.StatusCode != 500 {
+		t.Errorf("expected BBError with StatusCode 500, got: %v", err)
+	}
+	if transport.calls() != 3 {
+		t.Errorf("expected 3 attempts, got %d", transport.calls())
+	}
+}
+
+func TestRetry_HonoursRetryAfter(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 429, headers: map[string]string{"Retry-After": "2"}},
+		{status: 200, body: `{}`},
+	}}
+	client, delays := newRetryClient(transport, testPolicy())
+
+	if _, err := client.GetRaw("https://api.example.com/test"); err != nil {
+		t.Fatalf("GetRaw() error: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+		t.Errorf("expected a single 2s delay, got %v", *delays)
+	}
+}
+
+func TestRetry_HonoursRateLimitReset(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(3*time.Second).Unix(), 10)
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 429, headers: map[string]string{"X-RateLimit-Reset": reset}},
+		{status: 200, body: `{}`},
+	}}
+	client, delays := newRetryClient(transport, testPolicy())
+
+	if _, err := client.GetRaw("https://api.example.com/test"); err != nil {
+		t.Fatalf("GetRaw() error: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] <= 0 || (*delays)[0] > 4*time.Second {
+		t.Errorf("expected a single delay of about 3s, got %v", *delays)
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxDelay(t *testing.T) {
+	// Server-requested waits are not capped by the backoff limit.
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 429, headers: map[string]string{"Retry-After": "30"}},
+		{status: 200, body: `{}`},
+	}}
+	client, delays := newRetryClient(transport, testPolicy())
+
+	if _, err := client.GetRaw("https://api.example.com/test"); err != nil {
+		t.Fatalf("GetRaw() error: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 30*time.Second {
+		t.Errorf("expected a single 30s delay, got %v", *delays)
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxServerDelay(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 429, headers: map[string]string{"Retry-After": "3600"}},
+		{status: 200, body: `{}`},
+	}}
+	client, _ := newRetryClient(transport, testPolicy())
+
+	_, err := client.GetRaw("https://api.example.com/test")
+	if !errors.IsRateLimit(err) {
+		t.Fatalf("expected rate limit error, got: %v", err)
+	}
+	if transport.calls() != 1 {
+		t.Errorf("expected no retry when Retry-After exceeds MaxServerDelay, got %d attempts", transport.calls())
+	}
+}
+
+func TestRetry_RetryAfterBeyondDeadline(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 429, headers: map[string]string{"Retry-After": "30"}},
+		{status: 200, body: `{}`},
+	}}
+	client, _ := newRetryClient(transport, testPolicy())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := client.GetContext(ctx, "https://api.example.com/test")
+	if !errors.IsRateLimit(err) {
+		t.Fatalf("expected rate limit error, got: %v", err)
+	}
+	if transport.calls() != 1 {
+		t.Errorf("expected no retry when Retry-After passes the deadline, got %d attempts", transport.calls())
+	}
+}
+
+func TestRetry_NonIdempotentNotRetriedByDefault(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 503},
+		{status: 200, body: `{}`},
+	}}
+	client, _ := newRetryClient(transport, testPolicy())
+
+	_, err := client.Post("/repositories/ws/repo/pullrequests", `{"title":"x"}`)
+	if err == nil {
+		t.Fatal("expected POST to fail without retry")
+	}
+	if transport.calls() != 1 {
+		t.Errorf("expected 1 attempt for POST, got %d", transport.calls())
+	}
+}
+
+func TestRetry_NonIdempotentOptIn(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{status: 503},
+		{status: 201, body: `{"id":1}`},
+	}}
+	policy := testPolicy()
+	policy.RetryNonIdempotent = true
+	client, _ := newRetryClient(transport, policy)
+
+	if _, err := client.Post("/repositories/ws/repo/pullrequests", `{"title":"x"}`); err != nil {
+		t.Fatalf("Post() error: %v", err)
+	}
+	if transport.calls() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", transport.calls())
+	}
+	if transport.bodies[1] != `{"title":"x"}` {
+		t.Errorf("retry body = %q, want original body", transport.bodies[1])
+	}
+}
+
+func TestRetry_NetworkError(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{
+		{err: stderrors.New("connection reset")},
+		{status: 200, body: `{}`},
+	}}
+	client, _ := newRetryClient(transport, testPolicy())
+
+	if _, err := client.GetRaw("https://api.example.com/test"); err != nil {
+		t.Fatalf("GetRaw() error: %v", err)
+	}
+	if transport.calls() != 2 {
+		t.Errorf("expected 2 attempts, got %d", transport.calls())
+	}
+}
+
+func TestRetry_DisabledByDefaultForNewClientWith(t *testing.T) {
+	transport := &scriptedTransport{responses: []scriptedResponse{{status: 503}}}
+	client := NewClientWith(&http.Client{Transport: transport}, &config.Config{}, &config.TokenData{AccessToken: "test-token"})
+
+	if _, err := client.GetRaw("https://api.example.com/test"); err == nil {
+		t.Fatal("expected error")
+	}
+	if transport.calls() != 1 {
+		t.Errorf("expected 1 attempt, got %d", transport.calls())
+	}
+}
+
+func TestDefaultRetryPolicy_Env(t *testing.T) {
+	t.Setenv("BB_MAX_RETRIES", "5")
+	if p := DefaultRetryPolicy(); p.MaxAttempts != 6 {
+		t.Errorf("MaxAttempts = %d, want 6", p.MaxAttempts)
+	}
+
+	t.Setenv("BB_MAX_RETRIES", "0")
+	if p := DefaultRetryPolicy(); p.allowsMethod(http.MethodGet) {
+		t.Error("expected retries disabled with BB_MAX_RETRIES=0")
+	}
+
+	t.Setenv("BB_MAX_RETRIES", "invalid")
+	if p := DefaultRetryPolicy(); p.MaxAttempts != defaultMaxRetries+1 {
+		t.Errorf("MaxAttempts = %d, want default %d", p.MaxAttempts, defaultMaxRetries+1)
+	}
+}
+
+func TestServerRetryDelay_HTTPDate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("Retry-After", now.Add(5*time.Second).Format(http.TimeFormat))
+
+	d, ok := serverRetryDelay(resp, now)
+	if !ok || d != 5*time.Second {
+		t.Errorf("serverRetryDelay() = %v, %v; want 5s, true", d, ok)
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+)
+
+// Endpoints are the OAuth 2.0 URLs used to authorize users and obtain tokens.
+type Endpoints struct {
+	AuthURL  string
+	TokenURL string
+}
+
+// EndpointsFor returns the OAuth endpoints for cfg, honouring BB_AUTH_URL and
+// BB_TOKEN_URL. A nil cfg yields the environment or Bitbucket Cloud defaults.
+func EndpointsFor(cfg *config.Config) Endpoints {
+	return Endpoints{
+		AuthURL:  cfg.OAuthAuthorizeURL(),
+		TokenURL: cfg.OAuthTokenURL(),
+	}
+}
+
+// AuthorizeURL builds the authorization URL a user visits to grant access.
+// The state parameter is omitted when empty.
+func (e Endpoints) AuthorizeURL(clientID, redirectURI, state string) string {
+	u := fmt.Sprintf("%s?client_id=%s&response_type=code&redirect_uri=%s",
+		e.AuthURL, url.QueryEscape(clientID), url.QueryEscape(redirectURI))
+	if state != "" {
+		u += "&state=" + url.QueryEscape(state)
+	}
+	return u
+}
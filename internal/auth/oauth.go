@@ -55,10 +55,16 @@ func isDocker() bool {
 	return err == nil
 }
 
+// Login performs the OAuth 2.0 Authorization Code flow against the default
+// endpoints. See Endpoints.Login.
+func Login(clientID, clientSecret string) (*config.TokenData, error) {
+	return EndpointsFor(nil).Login(clientID, clientSecret)
+}
+
 // Login performs the OAuth 2.0 Authorization Code flow.
 // It starts a local HTTP server to receive the callback, opens the browser
 // for user authorization, and exchanges the code for tokens.
-func Login(clientID, clientSecret string) (*config.TokenData, error) {
+func (e Endpoints) Login(clientID, clientSecret string) (*config.TokenData, error) {
 	// In WSL or Docker, bind to 0.0.0.0 so the host browser can reach the callback server.
 	listenAddr := fmt.Sprintf("127.0.0.1:%d", OAuthCallbackPort)
 	if isWSL() || isDocker() {
@@ -108,8 +114,7 @@ func Login(clientID, clientSecret string) (*config.TokenData, error) {
 	}()
 	defer server.Shutdown(context.Background())
 
-	authURL := fmt.Sprintf("%s?client_id=%s&response_type=code&redirect_uri=%s",
-		config.AuthURL, url.QueryEscape(clientID), url.QueryEscape(redirectURI))
+	authURL := e.AuthorizeURL(clientID, redirectURI, "")
 
 	// Attempt to open the browser automatically; fall back to printing the URL.
 	if err := openBrowser(authURL); err != nil {
@@ -139,17 +144,21 @@ func Login(clientID, clientSecret string) (*config.TokenData, error) {
 	}
 
 	// Exchange authorization code for tokens
-	return exchangeCode(clientID, clientSecret, code, redirectURI)
+	return e.exchangeCode(clientID, clientSecret, code, redirectURI)
 }
 
 func exchangeCode(clientID, clientSecret, code, redirectURI string) (*config.TokenData, error) {
+	return EndpointsFor(nil).exchangeCode(clientID, clientSecret, code, redirectURI)
+}
+
+func (e Endpoints) exchangeCode(clientID, clientSecret, code, redirectURI string) (*config.TokenData, error) {
 	data := url.Values{
 		"grant_type":   {"authorization_code"},
 		"code":         {code},
 		"redirect_uri": {redirectURI},
 	}
 
-	req, err := http.NewRequest("POST", config.TokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", e.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -200,11 +209,17 @@ func exchangeCode(clientID, clientSecret, code, redirectURI string) (*config.Tok
 // interaction — it authenticates as the OAuth consumer owner's account.
 // Ideal for Docker containers, CI, and headless environments.
 func ClientCredentialsLogin(clientID, clientSecret string) (*config.TokenData, error) {
+	return EndpointsFor(nil).ClientCredentialsLogin(clientID, clientSecret)
+}
+
+// ClientCredentialsLogin obtains an access token from e.TokenURL using the
+// OAuth 2.0 client_credentials grant.
+func (e Endpoints) ClientCredentialsLogin(clientID, clientSecret string) (*config.TokenData, error) {
 	data := url.Values{
 		"grant_type": {"client_credentials"},
 	}
 
-	req, err := http.NewRequest("POST", config.TokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", e.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -243,12 +258,17 @@ func ClientCredentialsLogin(clientID, clientSecret string) (*config.TokenData, e
 
 // RefreshAccessToken uses the refresh token to obtain a new access token.
 func RefreshAccessToken(clientID, clientSecret, refreshToken string) (*config.TokenData, error) {
+	return EndpointsFor(nil).RefreshAccessToken(clientID, clientSecret, refreshToken)
+}
+
+// RefreshAccessToken uses the refresh token to obtain a new access token from e.TokenURL.
+func (e Endpoints) RefreshAccessToken(clientID, clientSecret, refreshToken string) (*config.TokenData, error) {
 	data := url.Values{
 		"grant_type":    {"refresh_token"},
 		"refresh_token": {refreshToken},
 	}
 
-	req, err := http.NewRequest("POST", config.TokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", e.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +344,12 @@ func base64URLEncode(b []byte) string {
 // ExchangeCodeServerSide exchanges an authorization code for tokens on the server side.
 // Unlike exchangeCode, this is exported and supports an optional PKCE code_verifier.
 func ExchangeCodeServerSide(clientID, clientSecret, code, redirectURI string) (*config.TokenData, error) {
-	return exchangeCode(clientID, clientSecret, code, redirectURI)
+	return EndpointsFor(nil).ExchangeCodeServerSide(clientID, clientSecret, code, redirectURI)
+}
+
+// ExchangeCodeServerSide exchanges an authorization code for tokens at e.TokenURL.
+func (e Endpoints) ExchangeCodeServerSide(clientID, clientSecret, code, redirectURI string) (*config.TokenData, error) {
+	return e.exchangeCode(clientID, clientSecret, code, redirectURI)
 }
 
 // openBrowser attempts to open the given URL in the user's default browser.
@@ -64,6 +64,19 @@ func readBodyFile(path string) (string, error) {
 }
 
 func openEditor() (string, error) {
+	text, err := EditText("")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("body cannot be blank")
+	}
+	return text, nil
+}
+
+// EditText opens $VISUAL or $EDITOR (falling back to vi) on a temporary file
+// holding initial and returns the saved contents, which may be blank.
+func EditText(initial string) (string, error) {
 	editor := os.Getenv("VISUAL")
 	if editor == "" {
 		editor = os.Getenv("EDITOR")
@@ -77,8 +90,12 @@ func openEditor() (string, error) {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
-	tmpFile.Close()
 	defer os.Remove(tmpPath)
+	_, err = tmpFile.WriteString(initial)
+	tmpFile.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
 
 	cmd := exec.Command(editor, tmpPath)
 	cmd.Stdin = os.Stdin
@@ -92,10 +109,5 @@ func openEditor() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read editor output: %w", err)
 	}
-
-	text := string(data)
-	if strings.TrimSpace(text) == "" {
-		return "", fmt.Errorf("body cannot be blank")
-	}
-	return text, nil
+	return string(data), nil
 }
@@ -103,3 +103,20 @@ func TestResolveBody_BodyFileEmpty(t *testing.T) {
 		t.Errorf("got %q, want %q", err.Error(), want)
 	}
 }
+
+func TestEditText(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho edited >> \"$1\"\n"), 0755); err != nil {
+		t.Fatalf("failed to write editor script: %v", err)
+	}
+	t.Setenv("VISUAL", script)
+
+	got, err := EditText("template\n")
+	if err != nil {
+		t.Fatalf("EditText() error: %v", err)
+	}
+	if got != "template\nedited\n" {
+		t.Errorf("EditText() = %q, want the initial text followed by the edit", got)
+	}
+}
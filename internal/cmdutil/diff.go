@@ -0,0 +1,113 @@
+package cmdutil
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// DiffOptions holds the display flags of the diff commands.
+type DiffOptions struct {
+	Stat       bool
+	NameOnly   bool
+	SideBySide bool
+	NoPager    bool
+	Paths      []string
+}
+
+// AddDiffFlags adds the display flags of the diff commands to cmd.
+func AddDiffFlags(cmd *cobra.Command, opts *DiffOptions) {
+	cmd.Flags().BoolVar(&opts.Stat, "stat", false, "Show a summary of the changed files instead of the diff")
+	cmd.Flags().BoolVar(&opts.NameOnly, "name-only", false, "Only list the names of the changed files")
+	cmd.Flags().BoolVarP(&opts.SideBySide, "side-by-side", "y", false, "Show the old and new lines in two columns")
+	cmd.Flags().StringSliceVar(&opts.Paths, "path", nil, "Only show files matching a glob such as 'src/**/*.go' (repeatable)")
+	cmd.Flags().BoolVar(&opts.NoPager, "no-pager", false, "Do not pipe the output through a pager")
+	cmd.MarkFlagsMutuallyExclusive("stat", "name-only", "side-by-side")
+}
+
+// diffStat is an entry of the Bitbucket diffstat endpoints.
+type diffStat struct {
+	Status       string `json:"status"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Old          *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+// PrintDiff prints the diff at diffPath as opts asks, using the diffstat at
+// statPath for --stat and --name-only. Output to a terminal is colored and
+// paged.
+func PrintDiff(ctx context.Context, client *api.Client, diffPath, statPath string, opts *DiffOptions) error {
+	if opts.Stat || opts.NameOnly {
+		entries, err := api.GetAll[diffStat](ctx, client, statPath, 0)
+		if err != nil {
+			return err
+		}
+		stats := make([]diff.Stat, len(entries))
+		for i, e := range entries {
+			stats[i] = diff.Stat{Status: e.Status, Added: e.LinesAdded, Removed: e.LinesRemoved}
+			if e.Old != nil {
+				stats[i].OldPath = e.Old.Path
+			}
+			if e.New != nil {
+				stats[i].Path = e.New.Path
+			}
+		}
+		stats = diff.FilterStats(stats, opts.Paths)
+
+		w, done := startPager(opts)
+		defer done()
+		if opts.NameOnly {
+			output.WriteDiffNames(w, stats)
+		} else {
+			output.WriteDiffStat(w, stats)
+		}
+		return nil
+	}
+
+	data, err := client.GetContext(ctx, diffPath)
+	if err != nil {
+		return err
+	}
+	text := diff.FilterText(string(data), opts.Paths)
+
+	w, done := startPager(opts)
+	defer done()
+	if opts.SideBySide {
+		output.WriteSideBySideDiff(w, diff.Parse(text), terminalWidth())
+	} else {
+		output.WriteDiff(w, text)
+	}
+	return nil
+}
+
+func startPager(opts *DiffOptions) (io.Writer, func()) {
+	if opts.NoPager {
+		return os.Stdout, func() {}
+	}
+	return output.StartPager()
+}
+
+// terminalWidth returns the width of the terminal stdout is on, else
+// $COLUMNS, or a default wide enough for two columns of code.
+func terminalWidth() int {
+	if n, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && n > 0 {
+		return n
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 160
+}
@@ -0,0 +1,56 @@
+package cmdutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func TestAddDiffFlags(t *testing.T) {
+	var opts DiffOptions
+	cmd := &cobra.Command{Use: "diff", RunE: func(*cobra.Command, []string) error { return nil }}
+	AddDiffFlags(cmd, &opts)
+
+	for _, name := range []string{"stat", "name-only", "side-by-side", "path", "no-pager"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s", name)
+		}
+	}
+
+	cmd.SetArgs([]string{"--path", "*.go", "--path", "docs/**", "-y"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.SideBySide || len(opts.Paths) != 2 {
+		t.Errorf("got options %+v", opts)
+	}
+}
+
+func TestAddDiffFlags_Exclusive(t *testing.T) {
+	var opts DiffOptions
+	cmd := &cobra.Command{Use: "diff", RunE: func(*cobra.Command, []string) error { return nil }}
+	AddDiffFlags(cmd, &opts)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	cmd.SetArgs([]string{"--stat", "--name-only"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for --stat with --name-only")
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		t.Skip("stdout is a terminal, whose width takes precedence over $COLUMNS")
+	}
+	t.Setenv("COLUMNS", "100")
+	if got := terminalWidth(); got != 100 {
+		t.Errorf("terminalWidth() = %d, want 100", got)
+	}
+	t.Setenv("COLUMNS", "")
+	if got := terminalWidth(); got != 160 {
+		t.Errorf("terminalWidth() = %d, want 160", got)
+	}
+}
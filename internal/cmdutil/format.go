@@ -0,0 +1,144 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+// AddJSONFlag registers --json on cmd as a shorthand for --format json, along
+// with --jq and --template for filtering the JSON output. --json=a,b.c
+// selects fields from the raw API response and --json= lists them.
+func AddJSONFlag(cmd *cobra.Command) {
+	f := cmd.Flags().VarPF(&jsonFlag{}, "json", "", "Output as JSON (same as --format json); use --json=id,title to select fields or --json= to list them")
+	f.NoOptDefVal = "true"
+	AddFilterFlags(cmd)
+}
+
+// jsonFlag is the value of --json. On its own the flag behaves like a bool;
+// given a value it holds a comma-separated field list.
+type jsonFlag struct {
+	enabled bool
+	fields  []string
+	list    bool
+}
+
+func (f *jsonFlag) String() string {
+	switch {
+	case !f.enabled:
+		return "false"
+	case f.list:
+		return ""
+	case f.fields != nil:
+		return strings.Join(f.fields, ",")
+	}
+	return "true"
+}
+
+func (f *jsonFlag) Set(s string) error {
+	*f = jsonFlag{enabled: true}
+	switch strings.ToLower(s) {
+	case "true":
+	case "false":
+		f.enabled = false
+	default:
+		f.fields = output.ParseFields(s)
+		f.list = len(f.fields) == 0
+	}
+	return nil
+}
+
+// Type reports "bool": --json never consumes the following argument, so
+// fields must be attached with '='.
+func (f *jsonFlag) Type() string { return "bool" }
+
+// AddFilterFlags registers --jq and --template on cmd. Commands that always
+// print JSON, like 'bb api', use it without AddJSONFlag.
+func AddFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("jq", "q", "", "Filter JSON output using a jq expression")
+	cmd.Flags().String("template", "", "Format JSON output using a Go template")
+}
+
+// ConfigureOutput resolves the output format, the --json field selection and
+// the --jq / --template filters for cmd and installs them in the output
+// package.
+func ConfigureOutput(cmd *cobra.Command) error {
+	format, err := ResolveFormat(cmd)
+	if err != nil {
+		return err
+	}
+	output.SetFormat(format)
+	var fields []string
+	var list bool
+	if f := cmd.Flags().Lookup("json"); f != nil {
+		if jf, ok := f.Value.(*jsonFlag); ok && jf.enabled {
+			fields, list = jf.fields, jf.list
+		}
+	}
+	output.SetFields(fields, list)
+	if err := output.SetJQ(flagString(cmd, "jq")); err != nil {
+		return err
+	}
+	if err := output.SetTemplate(flagString(cmd, "template")); err != nil {
+		return err
+	}
+	return nil
+}
+
+func flagString(cmd *cobra.Command, name string) string {
+	if f := cmd.Flags().Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// ResolveFormat determines the output format for cmd: --json, then the global
+// --format flag, then the profile's configured default. An invalid configured
+// default falls back to the table format rather than failing every command.
+// --jq and --template operate on JSON and select it implicitly.
+//
+// When --json selects fields, --format may pick another structured format
+// for them, e.g. --json=id,title --format csv.
+func ResolveFormat(cmd *cobra.Command) (output.Format, error) {
+	jq, tmpl := flagString(cmd, "jq"), flagString(cmd, "template")
+	if jq != "" || tmpl != "" {
+		if jq != "" && tmpl != "" {
+			return "", fmt.Errorf("--jq cannot be combined with --template")
+		}
+		filter := "--jq"
+		if tmpl != "" {
+			filter = "--template"
+		}
+		if ff := cmd.Flags().Lookup("format"); ff != nil && ff.Changed && ff.Value.String() != string(output.FormatJSON) {
+			return "", fmt.Errorf("%s cannot be combined with --format %s", filter, ff.Value.String())
+		}
+		return output.FormatJSON, nil
+	}
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Changed && f.Value.String() != "false" {
+		ff := cmd.Flags().Lookup("format")
+		if jf, ok := f.Value.(*jsonFlag); ok && (jf.fields != nil || jf.list) {
+			if ff != nil && ff.Changed {
+				format, err := output.ParseFormat(ff.Value.String())
+				if err != nil || format != output.FormatTable {
+					return format, err
+				}
+			}
+			return output.FormatJSON, nil
+		}
+		if ff != nil && ff.Changed && ff.Value.String() != string(output.FormatJSON) {
+			return "", fmt.Errorf("--json cannot be combined with --format %s", ff.Value.String())
+		}
+		return output.FormatJSON, nil
+	}
+	if f := cmd.Flags().Lookup("format"); f != nil && f.Changed {
+		return output.ParseFormat(f.Value.String())
+	}
+	if format, err := output.ParseFormat(config.ConfiguredFormat()); err == nil {
+		return format, nil
+	}
+	return output.FormatTable, nil
+}
@@ -0,0 +1,154 @@
+package cmdutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/output"
+)
+
+func newFormatCmd(t *testing.T, defaultFormat string, args ...string) *cobra.Command {
+	t.Helper()
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	t.Setenv(config.EnvProfile, "")
+	config.ResetConfigDirCache()
+	t.Cleanup(config.ResetConfigDirCache)
+	if defaultFormat != "" {
+		dir := filepath.Join(tmp, config.AppName)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		data := []byte(`{"default_format": "` + defaultFormat + `"}`)
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := &cobra.Command{Use: "list"}
+	cmd.Flags().String("format", "", "")
+	AddJSONFlag(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags() error: %v", err)
+	}
+	return cmd
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultFormat string
+		args          []string
+		want          output.Format
+	}{
+		{"no config", "", nil, output.FormatTable},
+		{"configured default", "yaml", nil, output.FormatYAML},
+		{"invalid configured default", "xml", nil, output.FormatTable},
+		{"flag overrides config", "yaml", []string{"--format", "csv"}, output.FormatCSV},
+		{"json shorthand", "yaml", []string{"--json"}, output.FormatJSON},
+		{"json with matching format", "", []string{"--json", "--format", "json"}, output.FormatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newFormatCmd(t, tt.defaultFormat, tt.args...)
+			got, err := ResolveFormat(cmd)
+			if err != nil {
+				t.Fatalf("ResolveFormat() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFormat_Errors(t *testing.T) {
+	if _, err := ResolveFormat(newFormatCmd(t, "", "--format", "xml")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := ResolveFormat(newFormatCmd(t, "", "--json", "--format", "csv")); err == nil {
+		t.Error("expected an error when --json conflicts with --format")
+	}
+}
+
+func TestResolveFormat_Filters(t *testing.T) {
+	for _, args := range [][]string{
+		{"--jq", ".[].id"},
+		{"-q", ".[].id", "--format", "json"},
+		{"--template", "{{.}}"},
+	} {
+		got, err := ResolveFormat(newFormatCmd(t, "csv", args...))
+		if err != nil {
+			t.Fatalf("ResolveFormat(%v) error: %v", args, err)
+		}
+		if got != output.FormatJSON {
+			t.Errorf("ResolveFormat(%v) = %q, want json", args, got)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--jq", ".", "--template", "{{.}}"},
+		{"--jq", ".", "--format", "yaml"},
+		{"--template", "{{.}}", "--format", "csv"},
+	} {
+		if _, err := ResolveFormat(newFormatCmd(t, "", args...)); err == nil {
+			t.Errorf("ResolveFormat(%v) should fail", args)
+		}
+	}
+}
+
+func TestConfigureOutput_InvalidFilter(t *testing.T) {
+	t.Cleanup(func() {
+		output.SetFormat(output.FormatTable)
+		_ = output.SetJQ("")
+		_ = output.SetTemplate("")
+	})
+	if err := ConfigureOutput(newFormatCmd(t, "", "--jq", ".[")); err == nil {
+		t.Error("expected an error for an invalid jq expression")
+	}
+	if err := ConfigureOutput(newFormatCmd(t, "", "--template", "{{.x")); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+	if err := ConfigureOutput(newFormatCmd(t, "", "--jq", ".[0]")); err != nil {
+		t.Fatalf("ConfigureOutput() error: %v", err)
+	}
+	if !output.Filtered() || output.CurrentFormat() != output.FormatJSON {
+		t.Errorf("ConfigureOutput() did not install the jq filter")
+	}
+}
+
+func TestConfigureOutput_JSONFields(t *testing.T) {
+	t.Cleanup(func() {
+		output.SetFormat(output.FormatTable)
+		output.SetFields(nil, false)
+	})
+
+	tests := []struct {
+		args []string
+		want output.Format
+	}{
+		{[]string{"--json"}, output.FormatJSON},
+		{[]string{"--json=id,title"}, output.FormatJSON},
+		{[]string{"--json=id,title", "--format", "csv"}, output.FormatCSV},
+		{[]string{"--json="}, output.FormatJSON},
+	}
+	for _, tt := range tests {
+		cmd := newFormatCmd(t, "", tt.args...)
+		if err := ConfigureOutput(cmd); err != nil {
+			t.Fatalf("ConfigureOutput(%v) error: %v", tt.args, err)
+		}
+		if got := output.CurrentFormat(); got != tt.want {
+			t.Errorf("ConfigureOutput(%v) format = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	// --json never consumes the next argument.
+	cmd := newFormatCmd(t, "", "--json", "myworkspace/repo")
+	if got := cmd.Flags().Args(); len(got) != 1 || got[0] != "myworkspace/repo" {
+		t.Errorf("positional args = %v, want [myworkspace/repo]", got)
+	}
+}
@@ -0,0 +1,80 @@
+package cmdutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+)
+
+// PaginationOptions holds the values of the --page, --all and --limit flags
+// shared by list commands.
+type PaginationOptions struct {
+	Page  int
+	All   bool
+	Limit int
+}
+
+// AddPaginationFlags registers --page/-p, --all and --limit on cmd.
+func AddPaginationFlags(cmd *cobra.Command, opts *PaginationOptions) {
+	cmd.Flags().IntVarP(&opts.Page, "page", "p", 1, "Page number")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Fetch all pages by following pagination links")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "Maximum number of items to fetch across pages")
+}
+
+// Validate checks the flag combination for consistency.
+func (o *PaginationOptions) Validate() error {
+	if o.Page < 1 {
+		return fmt.Errorf("--page must be at least 1")
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+	return nil
+}
+
+// FollowPages reports whether results should be fetched across multiple pages.
+func (o *PaginationOptions) FollowPages() bool {
+	return o.All || o.Limit > 0
+}
+
+// FetchList fetches a paginated collection according to opts. Without --all or
+// --limit only the page at path is fetched, preserving the previous single-page
+// behavior. The returned bool reports whether more results are available.
+func FetchList[T any](ctx context.Context, client *api.Client, path string, opts *PaginationOptions) ([]T, bool, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, false, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p := api.NewPaginator[T](client, path, opts.Limit)
+	if !opts.FollowPages() {
+		items, err := p.Next(ctx)
+		return items, p.HasNext(), err
+	}
+
+	items, err := p.All(ctx)
+	return items, p.HasNext(), err
+}
+
+// FetchListWithRaw is like FetchList but also returns each item's raw JSON,
+// for use with output.WithRaw so --json field selection sees every field the
+// API returned.
+func FetchListWithRaw[T any](ctx context.Context, client *api.Client, path string, opts *PaginationOptions) ([]T, []json.RawMessage, bool, error) {
+	raw, hasMore, err := FetchList[json.RawMessage](ctx, client, path, opts)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	items := make([]T, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &items[i]); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	return items, raw, hasMore, nil
+}
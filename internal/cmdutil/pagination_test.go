@@ -0,0 +1,63 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAddPaginationFlags(t *testing.T) {
+	var opts PaginationOptions
+	cmd := &cobra.Command{Use: "list"}
+	AddPaginationFlags(cmd, &opts)
+
+	for _, name := range []string{"page", "all", "limit"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s to be registered", name)
+		}
+	}
+	if f := cmd.Flags().Lookup("page"); f != nil && f.Shorthand != "p" {
+		t.Errorf("expected page shorthand 'p', got %q", f.Shorthand)
+	}
+
+	if err := cmd.ParseFlags([]string{"--all", "--limit", "40"}); err != nil {
+		t.Fatalf("ParseFlags() error: %v", err)
+	}
+	if !opts.All || opts.Limit != 40 || opts.Page != 1 {
+		t.Errorf("unexpected options after parsing: %+v", opts)
+	}
+}
+
+func TestPaginationOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    PaginationOptions
+		wantErr bool
+	}{
+		{"defaults", PaginationOptions{Page: 1}, false},
+		{"all", PaginationOptions{Page: 1, All: true}, false},
+		{"limit", PaginationOptions{Page: 2, Limit: 10}, false},
+		{"zero page", PaginationOptions{Page: 0}, true},
+		{"negative limit", PaginationOptions{Page: 1, Limit: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaginationOptions_FollowPages(t *testing.T) {
+	if (&PaginationOptions{Page: 1}).FollowPages() {
+		t.Error("expected single-page mode by default")
+	}
+	if !(&PaginationOptions{Page: 1, All: true}).FollowPages() {
+		t.Error("expected --all to follow pages")
+	}
+	if !(&PaginationOptions{Page: 1, Limit: 5}).FollowPages() {
+		t.Error("expected --limit to follow pages")
+	}
+}
@@ -0,0 +1,139 @@
+package cmdutil
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// PromptSecret asks for a secret on the terminal with echo disabled. It fails
+// if stdin is not a terminal, so scripts get an error instead of a hang, and
+// if echo cannot be disabled, so the secret is never shown on screen.
+func PromptSecret(prompt string) (string, error) {
+	name := strings.TrimSuffix(strings.TrimSpace(prompt), ":")
+	if !stdinIsTerminal() {
+		return "", fmt.Errorf("cannot prompt for %s: stdin is not a terminal", name)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	if runtime.GOOS == "windows" {
+		// There is no stty; read the line through the console API instead.
+		secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return "", fmt.Errorf("cannot prompt for %s: %w", name, err)
+		}
+		return string(secret), nil
+	}
+
+	restore, err := disableEcho()
+	if err != nil {
+		return "", fmt.Errorf("cannot prompt for %s: failed to turn off terminal echo: %w", name, err)
+	}
+	defer restore()
+
+	// Turn echo back on before exiting when the prompt is interrupted,
+	// rather than leaving the terminal without it.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupts:
+			restore()
+			fmt.Fprintln(os.Stderr)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stdinIsTerminal reports whether stdin is a terminal. Tests replace it.
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// disableEcho turns off echo on the terminal and returns a function turning
+// it back on. Tests replace it.
+var disableEcho = func() (func(), error) {
+	if err := stty("-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty("echo") }, nil
+}
+
+// stdin is shared by the line prompts so input buffered by one prompt is
+// not lost to the next.
+var stdin = bufio.NewReader(os.Stdin)
+
+// CanPrompt reports whether stdin and stdout are both terminals, so
+// interactive prompts can be shown.
+func CanPrompt() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		fi, err := f.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Prompt asks question on the terminal and returns the trimmed answer, or def
+// when the answer is blank.
+func Prompt(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// Confirm asks a yes/no question on the terminal; def is the answer to a
+// blank reply.
+func Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := Prompt(question+" ("+hint+")", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,73 @@
+package cmdutil
+
+import (
+	"bufio"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPromptAndConfirm(t *testing.T) {
+	orig := stdin
+	defer func() { stdin = orig }()
+	stdin = bufio.NewReader(strings.NewReader("  My title \n\nyes\n\nn\n"))
+
+	if got, err := Prompt("Title", "default"); err != nil || got != "My title" {
+		t.Errorf("Prompt() = %q, %v, want %q", got, err, "My title")
+	}
+	if got, err := Prompt("Title", "default"); err != nil || got != "default" {
+		t.Errorf("Prompt() with blank answer = %q, %v, want the default", got, err)
+	}
+	for _, want := range []bool{true, true, false} {
+		if got, err := Confirm("Continue?", true); err != nil || got != want {
+			t.Errorf("Confirm() = %v, %v, want %v", got, err, want)
+		}
+	}
+	if _, err := Prompt("Title", ""); err == nil {
+		t.Error("Prompt() expected an error at end of input")
+	}
+}
+
+func TestPromptSecret(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("reads from the console on Windows")
+	}
+	origStdin, origTerminal, origEcho := stdin, stdinIsTerminal, disableEcho
+	defer func() { stdin, stdinIsTerminal, disableEcho = origStdin, origTerminal, origEcho }()
+
+	// The secret and the following answer come from the shared reader.
+	stdin = bufio.NewReader(strings.NewReader("s3cret\nyes\n"))
+	stdinIsTerminal = func() bool { return true }
+	echo := true
+	disableEcho = func() (func(), error) {
+		echo = false
+		return func() { echo = true }, nil
+	}
+
+	got, err := PromptSecret("Passphrase: ")
+	if err != nil || got != "s3cret" {
+		t.Fatalf("PromptSecret() = %q, %v, want %q", got, err, "s3cret")
+	}
+	if !echo {
+		t.Error("PromptSecret() did not turn echo back on")
+	}
+	if ok, err := Confirm("Continue?", false); err != nil || !ok {
+		t.Errorf("Confirm() after PromptSecret() = %v, %v, want true", ok, err)
+	}
+
+	// Without a way to hide the input, nothing is read.
+	stdin = bufio.NewReader(strings.NewReader("s3cret\n"))
+	disableEcho = func() (func(), error) { return nil, errors.New("stty: not a tty") }
+	if _, err := PromptSecret("Passphrase: "); err == nil || !strings.Contains(err.Error(), "terminal echo") {
+		t.Errorf("PromptSecret() error = %v, want an echo error", err)
+	}
+	if line, _ := stdin.ReadString('\n'); line != "s3cret\n" {
+		t.Error("PromptSecret() read the secret with echo on")
+	}
+
+	stdinIsTerminal = func() bool { return false }
+	if _, err := PromptSecret("Passphrase: "); err == nil || !strings.Contains(err.Error(), "not a terminal") {
+		t.Errorf("PromptSecret() error = %v, want a terminal error", err)
+	}
+}
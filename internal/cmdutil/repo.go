@@ -0,0 +1,99 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+	"github.com/PhilipKram/bitbucket-cli/internal/git"
+)
+
+const repoArgsAnnotation = "bb_repo_args"
+
+// AddRepoFlag registers --repo/-R as a persistent flag on cmd, so it is
+// available to cmd and every subcommand.
+func AddRepoFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("repo", "R", "", "Repository as workspace/repo-slug (default: detected from the git remote)")
+}
+
+// SetRepoArgs makes the leading <workspace/repo-slug> argument of a
+// repo-scoped command optional. cmd takes n further arguments; when it gets
+// only those, ResolveRepoArgs falls back to --repo/-R (see AddRepoFlag) and
+// then to the git remote of the current checkout.
+func SetRepoArgs(cmd *cobra.Command, n int) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[repoArgsAnnotation] = strconv.Itoa(n)
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) != n && len(args) != n+1 {
+			return fmt.Errorf("accepts %d or %d arg(s), received %d", n, n+1, len(args))
+		}
+		if len(args) == n+1 && repoFlag(cmd) != "" {
+			return fmt.Errorf("the repository cannot be given both as an argument and with --repo")
+		}
+		return nil
+	}
+}
+
+// ResolveRepoArgs returns the arguments of a command set up with
+// SetRepoArgs with the repository filled in as args[0]. The repository comes
+// from the first argument when present, then --repo, then the git remote.
+func ResolveRepoArgs(cmd *cobra.Command, args []string) ([]string, error) {
+	n, _ := strconv.Atoi(cmd.Annotations[repoArgsAnnotation])
+	if len(args) > n {
+		return args, nil
+	}
+	repo := repoFlag(cmd)
+	if repo != "" {
+		if err := ValidateRepo(repo); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if repo, err = DetectRepo(); err != nil {
+			return nil, err
+		}
+	}
+	return append([]string{repo}, args...), nil
+}
+
+// ValidateRepo checks that repo has the form workspace/repo-slug.
+func ValidateRepo(repo string) error {
+	parts := strings.SplitN(repo, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Contains(parts[1], "/") {
+		return errors.InvalidInput("repository", fmt.Sprintf("%q: expected format workspace/repo-slug", repo))
+	}
+	return nil
+}
+
+// DetectRepo determines workspace/repo-slug from the configured git remote
+// of the repository in the current directory.
+func DetectRepo() (string, error) {
+	cfg, _ := config.LoadConfig()
+	remote := cfg.GitRemoteName()
+	url, err := git.GetRemoteURL(remote)
+	if err == nil {
+		var info *git.RemoteInfo
+		if info, err = git.ParseBitbucketRemote(url, cfg.GitHostnames()...); err == nil {
+			return info.Workspace + "/" + info.Repo, nil
+		}
+	}
+	return "", &errors.BBError{
+		Message: fmt.Sprintf("Could not determine the repository from git remote %q", remote),
+		Suggestion: "Pass <workspace/repo-slug>, use --repo, run the command inside a Bitbucket checkout, " +
+			"or choose another remote with 'bb config set-remote' or BB_GIT_REMOTE.",
+		Err: err,
+	}
+}
+
+func repoFlag(cmd *cobra.Command) string {
+	if f := cmd.Flag("repo"); f != nil {
+		return strings.TrimSpace(f.Value.String())
+	}
+	return ""
+}
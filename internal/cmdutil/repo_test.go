@@ -0,0 +1,150 @@
+package cmdutil
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+)
+
+func newRepoCmd(n int) *cobra.Command {
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+	AddRepoFlag(cmd)
+	SetRepoArgs(cmd, n)
+	return cmd
+}
+
+// inTempGitRepo runs the test from a fresh git repository with the given
+// origin URL and an isolated config directory.
+func inTempGitRepo(t *testing.T, originURL string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvGitRemote, "")
+	config.ResetConfigDirCache()
+	t.Cleanup(config.ResetConfigDirCache)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	if err := exec.Command("git", "init").Run(); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	if originURL != "" {
+		if err := exec.Command("git", "remote", "add", "origin", originURL).Run(); err != nil {
+			t.Fatalf("failed to add remote: %v", err)
+		}
+	}
+}
+
+func TestSetRepoArgs_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		repo    string
+		wantErr bool
+	}{
+		{"without repo", []string{"1"}, "", false},
+		{"with repo", []string{"ws/repo", "1"}, "", false},
+		{"with repo flag", []string{"1"}, "ws/repo", false},
+		{"repo arg and flag", []string{"ws/repo", "1"}, "ws/repo", true},
+		{"too few", nil, "", true},
+		{"too many", []string{"ws/repo", "1", "2"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRepoCmd(1)
+			if tt.repo != "" {
+				_ = cmd.Flag("repo").Value.Set(tt.repo)
+			}
+			err := cmd.Args(cmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Args(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveRepoArgs_Explicit(t *testing.T) {
+	cmd := newRepoCmd(1)
+	got, err := ResolveRepoArgs(cmd, []string{"ws/repo", "7"})
+	if err != nil {
+		t.Fatalf("ResolveRepoArgs() error: %v", err)
+	}
+	if want := []string{"ws/repo", "7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveRepoArgs() = %v, want %v", got, want)
+	}
+
+	cmd = newRepoCmd(1)
+	_ = cmd.Flag("repo").Value.Set("other/slug")
+	got, err = ResolveRepoArgs(cmd, []string{"7"})
+	if err != nil {
+		t.Fatalf("ResolveRepoArgs() error: %v", err)
+	}
+	if want := []string{"other/slug", "7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveRepoArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveRepoArgs_InvalidFlag(t *testing.T) {
+	cmd := newRepoCmd(0)
+	_ = cmd.Flag("repo").Value.Set("no-slash")
+	if _, err := ResolveRepoArgs(cmd, nil); err == nil {
+		t.Error("expected an error for an invalid --repo value")
+	}
+}
+
+func TestResolveRepoArgs_FromGitRemote(t *testing.T) {
+	inTempGitRepo(t, "git@bitbucket.org:myteam/my-repo.git")
+
+	got, err := ResolveRepoArgs(newRepoCmd(1), []string{"42"})
+	if err != nil {
+		t.Fatalf("ResolveRepoArgs() error: %v", err)
+	}
+	if want := []string{"myteam/my-repo", "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveRepoArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectRepo_ConfiguredRemote(t *testing.T) {
+	inTempGitRepo(t, "")
+	if err := exec.Command("git", "remote", "add", "upstream", "https://bitbucket.org/team/upstream.git").Run(); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	if _, err := DetectRepo(); err == nil {
+		t.Error("expected an error without an origin remote")
+	}
+
+	t.Setenv(config.EnvGitRemote, "upstream")
+	got, err := DetectRepo()
+	if err != nil {
+		t.Fatalf("DetectRepo() error: %v", err)
+	}
+	if got != "team/upstream" {
+		t.Errorf("DetectRepo() = %q, want %q", got, "team/upstream")
+	}
+}
+
+func TestValidateRepo(t *testing.T) {
+	for _, repo := range []string{"ws/repo", "my-team/my.repo"} {
+		if err := ValidateRepo(repo); err != nil {
+			t.Errorf("ValidateRepo(%q) error: %v", repo, err)
+		}
+	}
+	for _, repo := range []string{"", "repo", "/repo", "ws/", "a/b/c"} {
+		if err := ValidateRepo(repo); err == nil {
+			t.Errorf("ValidateRepo(%q) expected an error", repo)
+		}
+	}
+}
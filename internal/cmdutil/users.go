@@ -0,0 +1,193 @@
+package cmdutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+)
+
+// User is a Bitbucket user that a reference given on the command line
+// resolved to.
+type User struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	AccountID   string `json:"account_id"`
+}
+
+// Name returns the display name of u, falling back to its nickname and UUID.
+func (u User) Name() string {
+	switch {
+	case u.DisplayName != "":
+		return u.DisplayName
+	case u.Nickname != "":
+		return u.Nickname
+	}
+	return u.UUID
+}
+
+var uuidPattern = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+
+// UserResolver resolves user references to members of a workspace. The
+// member list is fetched on first use and reused for later lookups.
+type UserResolver struct {
+	client    *api.Client
+	workspace string
+	members   []User
+	loaded    bool
+}
+
+// NewUserResolver returns a UserResolver for the members of workspace.
+func NewUserResolver(client *api.Client, workspace string) *UserResolver {
+	return &UserResolver{client: client, workspace: workspace}
+}
+
+// Resolve returns the user that ref refers to. ref may be "me", a UUID (with
+// or without braces), an email address, or the nickname, display name or
+// account ID of a workspace member, matched case-insensitively.
+func (r *UserResolver) Resolve(ctx context.Context, ref string) (User, error) {
+	ref = strings.TrimSpace(ref)
+	// UUIDs copied from API URLs have their braces encoded as %7B and %7D.
+	// Nothing else is unescaped: names may contain a literal %.
+	if decoded, err := url.PathUnescape(ref); err == nil && decoded != ref && uuidPattern.MatchString(decoded) {
+		ref = decoded
+	}
+	switch {
+	case ref == "":
+		return User{}, errors.InvalidInput("user", "cannot be empty")
+	case ref == "me":
+		return r.currentUser(ctx)
+	case uuidPattern.MatchString(ref):
+		return User{UUID: "{" + strings.Trim(ref, "{}") + "}"}, nil
+	case strings.Contains(ref, "@"):
+		return r.byEmail(ctx, ref)
+	}
+
+	if err := r.load(ctx); err != nil {
+		return User{}, err
+	}
+	var matches []User
+	for _, match := range []func(User) bool{
+		func(u User) bool { return strings.EqualFold(u.Nickname, ref) },
+		func(u User) bool { return strings.EqualFold(u.DisplayName, ref) },
+		func(u User) bool { return u.AccountID == ref },
+	} {
+		for _, u := range r.members {
+			if match(u) {
+				matches = append(matches, u)
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return User{}, &errors.BBError{
+			Message:    fmt.Sprintf("No member of workspace %s matches %q", r.workspace, ref),
+			Suggestion: fmt.Sprintf("Use a nickname, display name, email or UUID; run 'bb workspace members %s' to list members.", r.workspace),
+		}
+	default:
+		names := make([]string, len(matches))
+		for i, u := range matches {
+			names[i] = fmt.Sprintf("%s (%s)", u.Name(), u.UUID)
+		}
+		return User{}, &errors.BBError{
+			Message:    fmt.Sprintf("%q matches several members of workspace %s: %s", ref, r.workspace, strings.Join(names, ", ")),
+			Suggestion: "Use the member's nickname or UUID instead.",
+		}
+	}
+}
+
+// ResolveAll resolves every reference in refs, dropping duplicate users.
+func (r *UserResolver) ResolveAll(ctx context.Context, refs []string) ([]User, error) {
+	var users []User
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		u, err := r.Resolve(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[u.UUID] {
+			seen[u.UUID] = true
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// Search returns the workspace members whose nickname or display name
+// contains query, case-insensitively.
+func (r *UserResolver) Search(ctx context.Context, query string) ([]User, error) {
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var found []User
+	for _, u := range r.members {
+		if strings.Contains(strings.ToLower(u.Nickname), query) || strings.Contains(strings.ToLower(u.DisplayName), query) {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+func (r *UserResolver) load(ctx context.Context) error {
+	if r.loaded {
+		return nil
+	}
+	path := fmt.Sprintf("/workspaces/%s/members?pagelen=100", url.PathEscape(r.workspace))
+	members, err := api.GetAll[struct {
+		User User `json:"user"`
+	}](ctx, r.client, path, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list members of workspace %s: %w", r.workspace, err)
+	}
+	r.members = make([]User, len(members))
+	for i, m := range members {
+		r.members[i] = m.User
+	}
+	r.loaded = true
+	return nil
+}
+
+func (r *UserResolver) currentUser(ctx context.Context) (User, error) {
+	data, err := r.client.GetContext(ctx, "/user")
+	if err != nil {
+		return User{}, fmt.Errorf("failed to fetch current user: %w", err)
+	}
+	var u User
+	if err := json.Unmarshal(data, &u); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+// byEmail looks up a workspace member by email address. Bitbucket only
+// allows this for workspace administrators.
+func (r *UserResolver) byEmail(ctx context.Context, email string) (User, error) {
+	q := url.QueryEscape(fmt.Sprintf(`user.email IN ("%s")`, strings.ReplaceAll(email, `"`, "")))
+	path := fmt.Sprintf("/workspaces/%s/members?q=%s", url.PathEscape(r.workspace), q)
+	members, err := api.GetAll[struct {
+		User User `json:"user"`
+	}](ctx, r.client, path, 1)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to look up %s: %w", email, err)
+	}
+	if len(members) == 0 {
+		return User{}, &errors.BBError{
+			Message:    fmt.Sprintf("No member of workspace %s has the email address %s", r.workspace, email),
+			Suggestion: "Looking up members by email requires workspace admin access; use a nickname or UUID instead.",
+		}
+	}
+	return members[0].User, nil
+}
@@ -0,0 +1,88 @@
+package cmdutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
+)
+
+func TestUserResolver_Resolve(t *testing.T) {
+	t.Setenv("BB_API_URL", "")
+	var memberRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user":
+			w.Write([]byte(`{"uuid":"{me}","display_name":"Me Myself","nickname":"me"}`))
+		case r.URL.Path == "/workspaces/ws/members" && strings.Contains(r.URL.Query().Get("q"), "ann@example.com"):
+			w.Write([]byte(`{"values":[{"user":{"uuid":"{ann}","display_name":"Ann Lee","nickname":"ann"}}]}`))
+		case r.URL.Path == "/workspaces/ws/members" && r.URL.Query().Get("q") != "":
+			w.Write([]byte(`{"values":[]}`))
+		case r.URL.Path == "/workspaces/ws/members":
+			memberRequests++
+			w.Write([]byte(`{"values":[
+				{"user":{"uuid":"{ann}","display_name":"Ann Lee","nickname":"ann","account_id":"557058:1"}},
+				{"user":{"uuid":"{bob}","display_name":"Bob Smith","nickname":"bob"}},
+				{"user":{"uuid":"{bob2}","display_name":"Bob Smith","nickname":"bsmith"}},
+				{"user":{"uuid":"{pct}","display_name":"100%20Dev","nickname":"pct"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.Client(), &config.Config{APIURL: server.URL}, &config.TokenData{AccessToken: "token"})
+	r := NewUserResolver(client, "ws")
+	ctx := context.Background()
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{"ANN", "{ann}", ""},
+		{"Ann Lee", "{ann}", ""},
+		{"557058:1", "{ann}", ""},
+		{"bsmith", "{bob2}", ""},
+		{"me", "{me}", ""},
+		{"ann@example.com", "{ann}", ""},
+		{"12345678-1234-1234-1234-123456789abc", "{12345678-1234-1234-1234-123456789abc}", ""},
+		{"%7B12345678-1234-1234-1234-123456789abc%7D", "{12345678-1234-1234-1234-123456789abc}", ""},
+		{"100%20Dev", "{pct}", ""},
+		{"100 Dev", "", "No member"},
+		{"Bob Smith", "", "several members"},
+		{"carol", "", "No member"},
+		{"carol@example.com", "", "email address"},
+	}
+	for _, tt := range tests {
+		u, err := r.Resolve(ctx, tt.ref)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want one containing %q", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q) error: %v", tt.ref, err)
+		} else if u.UUID != tt.want {
+			t.Errorf("Resolve(%q) = %s, want %s", tt.ref, u.UUID, tt.want)
+		}
+	}
+	if memberRequests != 1 {
+		t.Errorf("expected the member list to be fetched once, got %d requests", memberRequests)
+	}
+
+	users, err := r.ResolveAll(ctx, []string{"ann", "Ann Lee", "bob"})
+	if err != nil || len(users) != 2 {
+		t.Errorf("ResolveAll() = %v, %v, want ann and bob", users, err)
+	}
+	found, err := r.Search(ctx, "smi")
+	if err != nil || len(found) != 2 {
+		t.Errorf("Search(smi) = %v, %v, want both Bob Smiths", found, err)
+	}
+}
@@ -0,0 +1,56 @@
+package cmdutil
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/errors"
+)
+
+// ErrWatchInterrupted is returned by Watch when polling stops because the
+// context was cancelled or the process received an interrupt.
+var ErrWatchInterrupted = stderrors.New("watch interrupted")
+
+// ValidateInterval checks a polling interval given in seconds with --interval.
+func ValidateInterval(seconds int) error {
+	if seconds < 1 {
+		return errors.InvalidInput("interval", fmt.Sprintf("%d: must be at least 1 second", seconds))
+	}
+	return nil
+}
+
+// Watch calls poll immediately and then every interval until it reports done
+// or fails. Polling stops, and any in-flight request is aborted, when ctx is
+// cancelled or the process receives an interrupt.
+func Watch(ctx context.Context, interval time.Duration, poll func(ctx context.Context) (done bool, err error)) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// time.NewTicker panics on a non-positive interval.
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := poll(ctx)
+		if ctx.Err() != nil {
+			return ErrWatchInterrupted
+		}
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ErrWatchInterrupted
+		}
+	}
+}
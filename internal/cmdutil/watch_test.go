@@ -0,0 +1,70 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatch_PollsUntilDone(t *testing.T) {
+	calls := 0
+	err := Watch(context.Background(), time.Millisecond, func(context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("Watch() error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("poll called %d times, want 3", calls)
+	}
+}
+
+func TestWatch_Error(t *testing.T) {
+	want := errors.New("boom")
+	err := Watch(context.Background(), time.Millisecond, func(context.Context) (bool, error) {
+		return false, want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("Watch() error = %v, want %v", err, want)
+	}
+}
+
+func TestWatch_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Watch(ctx, time.Hour, func(context.Context) (bool, error) {
+		calls++
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, ErrWatchInterrupted) {
+		t.Errorf("Watch() error = %v, want ErrWatchInterrupted", err)
+	}
+	if calls != 1 {
+		t.Errorf("poll called %d times, want 1", calls)
+	}
+}
+
+func TestWatch_NonPositiveInterval(t *testing.T) {
+	calls := 0
+	err := Watch(context.Background(), 0, func(context.Context) (bool, error) {
+		calls++
+		return true, nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("Watch() = %v after %d polls, want nil after 1", err, calls)
+	}
+}
+
+func TestValidateInterval(t *testing.T) {
+	for _, tt := range []struct {
+		seconds int
+		ok      bool
+	}{{1, true}, {30, true}, {0, false}, {-5, false}} {
+		if err := ValidateInterval(tt.seconds); (err == nil) != tt.ok {
+			t.Errorf("ValidateInterval(%d) = %v, want ok=%v", tt.seconds, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package comments arranges pull request comments into threads and finds the
+// lines of the diff that inline comments are on.
+package comments
+
+import (
+	"sort"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+// Comment is a pull request comment as returned by the Bitbucket API.
+// Replies have a parent; inline comments point at a line of the diff.
+type Comment struct {
+	ID      int `json:"id"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User struct {
+		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname"`
+	} `json:"user"`
+	CreatedOn string `json:"created_on"`
+	UpdatedOn string `json:"updated_on"`
+	Deleted   bool   `json:"deleted"`
+	Parent    *struct {
+		ID int `json:"id"`
+	} `json:"parent"`
+	Inline     *Inline `json:"inline"`
+	Resolution *struct {
+		User struct {
+			DisplayName string `json:"display_name"`
+		} `json:"user"`
+		CreatedOn string `json:"created_on"`
+	} `json:"resolution"`
+}
+
+// Inline is the position of an inline comment: a line given by its number
+// in the new file (To) or, for removed lines, in the old file (From).
+type Inline struct {
+	Path     string `json:"path"`
+	From     *int   `json:"from"`
+	To       *int   `json:"to"`
+	Outdated bool   `json:"outdated"`
+}
+
+// Author returns the display name of the comment's author, or the nickname
+// when there is none.
+func (c *Comment) Author() string {
+	if c.User.DisplayName != "" {
+		return c.User.DisplayName
+	}
+	return c.User.Nickname
+}
+
+// DiffContext returns up to context lines of files either side of the line
+// an inline comment is on, with the index of that line in the result. ok is
+// false for comments that are not inline or are outdated, and when the diff
+// does not show the line.
+func (c *Comment) DiffContext(files []diff.File, context int) (lines []diff.Line, index int, ok bool) {
+	if c.Inline == nil || c.Inline.Outdated {
+		return nil, 0, false
+	}
+	f := diff.FindFile(files, c.Inline.Path)
+	if f == nil {
+		return nil, 0, false
+	}
+	var from, to int
+	if c.Inline.From != nil {
+		from = *c.Inline.From
+	}
+	if c.Inline.To != nil {
+		to = *c.Inline.To
+	}
+	return f.Around(from, to, context)
+}
+
+// Thread is a comment with its replies.
+type Thread struct {
+	Comment *Comment
+	Replies []*Thread
+}
+
+// Threads arranges comments into threads ordered by ID. Replies whose parent
+// is missing start a thread of their own, and deleted comments nobody
+// replied to are dropped.
+func Threads(comments []Comment) []*Thread {
+	sorted := make([]*Comment, len(comments))
+	for i := range comments {
+		sorted[i] = &comments[i]
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	byID := make(map[int]*Thread, len(sorted))
+	for _, c := range sorted {
+		byID[c.ID] = &Thread{Comment: c}
+	}
+	var roots []*Thread
+	for _, c := range sorted {
+		t := byID[c.ID]
+		if c.Parent != nil {
+			if parent, ok := byID[c.Parent.ID]; ok {
+				parent.Replies = append(parent.Replies, t)
+				continue
+			}
+		}
+		roots = append(roots, t)
+	}
+
+	var prune func([]*Thread) []*Thread
+	prune = func(threads []*Thread) []*Thread {
+		kept := threads[:0]
+		for _, t := range threads {
+			t.Replies = prune(t.Replies)
+			if !t.Comment.Deleted || len(t.Replies) > 0 {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	}
+	return prune(roots)
+}
+
+// Unresolved returns the threads that have not been resolved.
+func Unresolved(threads []*Thread) []*Thread {
+	var open []*Thread
+	for _, t := range threads {
+		if t.Comment.Resolution == nil {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// HasInline reports whether any thread starts with an inline comment that is
+// not outdated, that is whether the diff is needed to show their context.
+func HasInline(threads []*Thread) bool {
+	for _, t := range threads {
+		if t.Comment.Inline != nil && !t.Comment.Inline.Outdated {
+			return true
+		}
+	}
+	return false
+}
+
+// Walk calls fn for every comment of the thread with its reply depth, the
+// thread's own comment first.
+func (t *Thread) Walk(fn func(c *Comment, depth int)) {
+	var visit func(*Thread, int)
+	visit = func(t *Thread, depth int) {
+		fn(t.Comment, depth)
+		for _, r := range t.Replies {
+			visit(r, depth+1)
+		}
+	}
+	visit(t, 0)
+}
@@ -0,0 +1,93 @@
+package comments
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+func TestThreads(t *testing.T) {
+	var comments []Comment
+	if err := json.Unmarshal([]byte(`[
+		{"id": 3, "parent": {"id": 1}, "content": {"raw": "reply"}},
+		{"id": 1, "content": {"raw": "root"}, "resolution": {"user": {"display_name": "Ann"}}},
+		{"id": 2, "deleted": true},
+		{"id": 4, "deleted": true},
+		{"id": 5, "parent": {"id": 4}, "content": {"raw": "reply to deleted"}},
+		{"id": 6, "parent": {"id": 99}, "content": {"raw": "orphan"}},
+		{"id": 7, "parent": {"id": 6}, "deleted": true}
+	]`), &comments); err != nil {
+		t.Fatal(err)
+	}
+
+	threads := Threads(comments)
+	var ids []int
+	for _, th := range threads {
+		ids = append(ids, th.Comment.ID)
+	}
+	if fmt.Sprint(ids) != "[1 4 6]" {
+		t.Fatalf("thread roots = %v, want [1 4 6]", ids)
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].Comment.ID != 3 {
+		t.Errorf("replies of #1 = %+v", threads[0].Replies)
+	}
+	if len(threads[2].Replies) != 0 {
+		t.Errorf("deleted reply #7 was kept: %+v", threads[2].Replies)
+	}
+
+	var depths []int
+	threads[1].Walk(func(c *Comment, depth int) { depths = append(depths, depth) })
+	if fmt.Sprint(depths) != "[0 1]" {
+		t.Errorf("Walk() depths = %v, want [0 1]", depths)
+	}
+
+	if open := Unresolved(threads); len(open) != 2 || open[0].Comment.ID != 4 {
+		t.Errorf("Unresolved() = %+v", open)
+	}
+}
+
+func TestComment_DiffContext(t *testing.T) {
+	files := diff.Parse(`diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+ func main() {}
+`)
+	to, from := 2, 2
+	tests := []struct {
+		inline    *Inline
+		wantLines int
+		wantIndex int
+		wantOK    bool
+	}{
+		{&Inline{Path: "main.go", To: &to}, 3, 1, true},
+		{&Inline{Path: "main.go", From: &from}, 3, 1, true},
+		{&Inline{Path: "main.go", To: &to, Outdated: true}, 0, 0, false},
+		{&Inline{Path: "other.go", To: &to}, 0, 0, false},
+		{nil, 0, 0, false},
+	}
+	for i, tt := range tests {
+		c := Comment{Inline: tt.inline}
+		lines, index, ok := c.DiffContext(files, 1)
+		if len(lines) != tt.wantLines || index != tt.wantIndex || ok != tt.wantOK {
+			t.Errorf("case %d: DiffContext() = %d lines, index %d, %v; want %d, %d, %v", i, len(lines), index, ok, tt.wantLines, tt.wantIndex, tt.wantOK)
+		}
+	}
+}
+
+func TestComment_Author(t *testing.T) {
+	var c Comment
+	c.User.Nickname = "ann"
+	if got := c.Author(); got != "ann" {
+		t.Errorf("Author() = %q, want ann", got)
+	}
+	c.User.DisplayName = "Ann Lee"
+	if got := c.Author(); got != "Ann Lee" {
+		t.Errorf("Author() = %q, want Ann Lee", got)
+	}
+}
@@ -1,6 +1,7 @@
 package completion
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -42,7 +43,7 @@ func WorkspaceNames(cmd *cobra.Command, args []string, toComplete string) ([]str
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	workspaces, err := api.GetPaginated[Workspace](client, "/workspaces?pagelen=50")
+	workspaces, err := api.GetPaginatedContext[Workspace](cmd.Context(), client, "/workspaces?pagelen=50")
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -62,7 +63,7 @@ func WorkspaceNamesWithDescriptions(cmd *cobra.Command, args []string, toComplet
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	workspaces, err := api.GetPaginated[Workspace](client, "/workspaces?pagelen=50")
+	workspaces, err := api.GetPaginatedContext[Workspace](cmd.Context(), client, "/workspaces?pagelen=50")
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -77,13 +78,13 @@ func WorkspaceNamesWithDescriptions(cmd *cobra.Command, args []string, toComplet
 }
 
 // GetWorkspaceSlugs retrieves all workspace slugs for use in other completion functions
-func GetWorkspaceSlugs() ([]string, error) {
+func GetWorkspaceSlugs(ctx context.Context) ([]string, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
 	}
 
-	workspaces, err := api.GetPaginated[Workspace](client, "/workspaces?pagelen=50")
+	workspaces, err := api.GetPaginatedContext[Workspace](ctx, client, "/workspaces?pagelen=50")
 	if err != nil {
 		return nil, err
 	}
@@ -97,13 +98,13 @@ func GetWorkspaceSlugs() ([]string, error) {
 }
 
 // GetWorkspaceBySlug retrieves a single workspace by its slug
-func GetWorkspaceBySlug(slug string) (*Workspace, error) {
+func GetWorkspaceBySlug(ctx context.Context, slug string) (*Workspace, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := client.Get("/workspaces/" + slug)
+	data, err := client.GetContext(ctx, "/workspaces/"+slug)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +136,7 @@ func RepositoryNames(cmd *cobra.Command, args []string, toComplete string) ([]st
 	}
 
 	path := fmt.Sprintf("/repositories/%s?pagelen=50", url.PathEscape(workspace))
-	repos, err := api.GetPaginated[Repository](client, path)
+	repos, err := api.GetPaginatedContext[Repository](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -167,7 +168,7 @@ func RepositoryNamesWithDescriptions(cmd *cobra.Command, args []string, toComple
 	}
 
 	path := fmt.Sprintf("/repositories/%s?pagelen=50", url.PathEscape(workspace))
-	repos, err := api.GetPaginated[Repository](client, path)
+	repos, err := api.GetPaginatedContext[Repository](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -182,7 +183,7 @@ func RepositoryNamesWithDescriptions(cmd *cobra.Command, args []string, toComple
 }
 
 // GetRepositorySlugs retrieves all repository full names for a specific workspace
-func GetRepositorySlugs(workspace string) ([]string, error) {
+func GetRepositorySlugs(ctx context.Context, workspace string) ([]string, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
@@ -197,7 +198,7 @@ func GetRepositorySlugs(workspace string) ([]string, error) {
 	}
 
 	path := fmt.Sprintf("/repositories/%s?pagelen=50", url.PathEscape(workspace))
-	repos, err := api.GetPaginated[Repository](client, path)
+	repos, err := api.GetPaginatedContext[Repository](ctx, client, path)
 	if err != nil {
 		return nil, err
 	}
@@ -211,13 +212,13 @@ func GetRepositorySlugs(workspace string) ([]string, error) {
 }
 
 // GetRepositoryByFullName retrieves a single repository by its full name (workspace/repo-slug)
-func GetRepositoryByFullName(fullName string) (*Repository, error) {
+func GetRepositoryByFullName(ctx context.Context, fullName string) (*Repository, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := client.Get("/repositories/" + fullName)
+	data, err := client.GetContext(ctx, "/repositories/"+fullName)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +269,7 @@ func BranchNames(cmd *cobra.Command, args []string, toComplete string) ([]string
 	}
 
 	path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=50", escapeRepoPath(args[0]))
-	branches, err := api.GetPaginated[Branch](client, path)
+	branches, err := api.GetPaginatedContext[Branch](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -294,7 +295,7 @@ func BranchNamesWithDescriptions(cmd *cobra.Command, args []string, toComplete s
 	}
 
 	path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=50", escapeRepoPath(args[0]))
-	branches, err := api.GetPaginated[Branch](client, path)
+	branches, err := api.GetPaginatedContext[Branch](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -309,7 +310,7 @@ func BranchNamesWithDescriptions(cmd *cobra.Command, args []string, toComplete s
 }
 
 // GetBranchNames retrieves all branch names for a specific repository
-func GetBranchNames(repoFullName string) ([]string, error) {
+func GetBranchNames(ctx context.Context, repoFullName string) ([]string, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
@@ -320,7 +321,7 @@ func GetBranchNames(repoFullName string) ([]string, error) {
 	}
 
 	path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=50", escapeRepoPath(repoFullName))
-	branches, err := api.GetPaginated[Branch](client, path)
+	branches, err := api.GetPaginatedContext[Branch](ctx, client, path)
 	if err != nil {
 		return nil, err
 	}
@@ -346,7 +347,7 @@ func PRNumbers(cmd *cobra.Command, args []string, toComplete string) ([]string,
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=50&state=OPEN", escapeRepoPath(args[0]))
-	prs, err := api.GetPaginated[PullRequest](client, path)
+	prs, err := api.GetPaginatedContext[PullRequest](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -372,7 +373,7 @@ func PRNumbersWithDescriptions(cmd *cobra.Command, args []string, toComplete str
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=50&state=OPEN", escapeRepoPath(args[0]))
-	prs, err := api.GetPaginated[PullRequest](client, path)
+	prs, err := api.GetPaginatedContext[PullRequest](cmd.Context(), client, path)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -388,7 +389,7 @@ func PRNumbersWithDescriptions(cmd *cobra.Command, args []string, toComplete str
 }
 
 // GetPRNumbers retrieves all PR numbers for a specific repository
-func GetPRNumbers(repoFullName string) ([]string, error) {
+func GetPRNumbers(ctx context.Context, repoFullName string) ([]string, error) {
 	client, err := api.NewClient()
 	if err != nil {
 		return nil, err
@@ -399,7 +400,7 @@ func GetPRNumbers(repoFullName string) ([]string, error) {
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=50&state=OPEN", escapeRepoPath(repoFullName))
-	prs, err := api.GetPaginated[PullRequest](client, path)
+	prs, err := api.GetPaginatedContext[PullRequest](ctx, client, path)
 	if err != nil {
 		return nil, err
 	}
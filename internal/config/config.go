@@ -2,6 +2,8 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -37,6 +39,17 @@ type Config struct {
 	// OAuth credentials configured by the user
 	OAuthKey    string `json:"oauth_key"`
 	OAuthSecret string `json:"oauth_secret"`
+	// Endpoint overrides for Bitbucket-compatible servers, gateways and mocks.
+	// Empty values fall back to the Bitbucket Cloud defaults.
+	APIURL   string   `json:"api_url,omitempty"`
+	AuthURL  string   `json:"auth_url,omitempty"`
+	TokenURL string   `json:"token_url,omitempty"`
+	GitHosts []string `json:"git_hosts,omitempty"`
+	// GitRemote names the remote used to detect the current repository.
+	GitRemote string `json:"git_remote,omitempty"`
+	// CredentialStore selects where the token and OAuth secret are kept:
+	// plaintext (default), keyring or encrypted.
+	CredentialStore string `json:"credential_store,omitempty"`
 }
 
 type TokenData struct {
@@ -86,60 +99,139 @@ func ConfigDir() (string, error) {
 	return cachedDir, nil
 }
 
+// LoadConfig loads the configuration of the active profile.
 func LoadConfig() (*Config, error) {
-	dir, err := ConfigDir()
+	return LoadProfileConfig(ActiveProfile())
+}
+
+// ConfiguredFormat returns the active profile's default output format, as set
+// with 'bb config set-format'. Unlike LoadConfig it never reads the credential
+// store, so it cannot prompt for a passphrase.
+func ConfiguredFormat() string {
+	cfg, err := readProfileConfig(ActiveProfile())
+	if err != nil || cfg.DefaultFormat == "" {
+		return DefaultFormat
+	}
+	return cfg.DefaultFormat
+}
+
+// LoadProfileConfig loads the configuration of the named profile, returning
+// defaults if the profile has no saved configuration. The OAuth secret is
+// resolved from the profile's credential store.
+func LoadProfileConfig(profile string) (*Config, error) {
+	cfg, err := readProfileConfig(profile)
 	if err != nil {
 		return nil, err
 	}
-	path := filepath.Join(dir, "config.json")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Config{DefaultFormat: DefaultFormat}, nil
+	if cfg.DefaultFormat == "" {
+		cfg.DefaultFormat = DefaultFormat
+	}
+	if cfg.CredentialStore != "" && cfg.CredentialStore != StorePlaintext {
+		store, err := OpenCredentialStore(cfg.CredentialStore)
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
+		secret, err := store.Get(profile, credentialOAuthSecret)
+		if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+			return nil, fmt.Errorf("failed to read OAuth secret from %s store: %w", store.Name(), err)
+		}
+		cfg.OAuthSecret = secret
 	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	return cfg, nil
+}
+
+// LoadProfileMetadata loads the configuration of the named profile without
+// its OAuth secret. Unlike LoadProfileConfig it never reads the credential
+// store, so it cannot prompt for a passphrase.
+func LoadProfileMetadata(profile string) (*Config, error) {
+	cfg, err := readProfileConfig(profile)
+	if err != nil {
 		return nil, err
 	}
 	if cfg.DefaultFormat == "" {
 		cfg.DefaultFormat = DefaultFormat
 	}
-	return &cfg, nil
+	cfg.OAuthSecret = ""
+	return cfg, nil
 }
 
+// SaveConfig saves the configuration of the active profile. The OAuth secret
+// is written to the profile's credential store rather than config.json unless
+// the plaintext store is in use.
 func SaveConfig(cfg *Config) error {
-	dir, err := ConfigDir()
+	profile := ActiveProfile()
+	if cfg.CredentialStore == "" || cfg.CredentialStore == StorePlaintext {
+		return writeProfileConfig(profile, cfg)
+	}
+
+	store, err := OpenCredentialStore(cfg.CredentialStore)
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	if cfg.OAuthSecret != "" {
+		err = store.Set(profile, credentialOAuthSecret, cfg.OAuthSecret)
+	} else {
+		err = store.Delete(profile, credentialOAuthSecret)
+	}
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to save OAuth secret to %s store: %w", store.Name(), err)
 	}
-	return os.WriteFile(filepath.Join(dir, "config.json"), data, 0600)
+	stored := *cfg
+	stored.OAuthSecret = ""
+	return writeProfileConfig(profile, &stored)
 }
 
+// LoadToken loads the token of the active profile.
 func LoadToken() (*TokenData, error) {
-	dir, err := ConfigDir()
+	return LoadProfileToken(ActiveProfile())
+}
+
+// LoadProfileToken loads the token of the named profile from its credential
+// store. A missing token yields an error satisfying errors.Is(err, fs.ErrNotExist).
+func LoadProfileToken(profile string) (*TokenData, error) {
+	store, err := ProfileCredentialStore(profile)
 	if err != nil {
 		return nil, err
 	}
-	path := filepath.Join(dir, "token.json")
-	data, err := os.ReadFile(path)
+	data, err := store.Get(profile, credentialToken)
 	if err != nil {
+		if errors.Is(err, ErrCredentialNotFound) {
+			return nil, fmt.Errorf("no token for profile %q: %w", profile, os.ErrNotExist)
+		}
 		return nil, err
 	}
 	var token TokenData
-	if err := json.Unmarshal(data, &token); err != nil {
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
 		return nil, err
 	}
 	return &token, nil
 }
 
+// HasProfileToken reports whether the named profile has a saved token. It
+// does not decrypt the encrypted store, so it cannot prompt for a passphrase.
+func HasProfileToken(profile string) (bool, error) {
+	store, err := ProfileCredentialStore(profile)
+	if err != nil {
+		return false, err
+	}
+	if s, ok := store.(interface {
+		Has(profile, key string) (bool, error)
+	}); ok {
+		return s.Has(profile, credentialToken)
+	}
+	if _, err := store.Get(profile, credentialToken); err != nil {
+		if errors.Is(err, ErrCredentialNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SaveToken saves the token of the active profile to its credential store.
 func SaveToken(token *TokenData) error {
-	dir, err := ConfigDir()
+	profile := ActiveProfile()
+	store, err := ProfileCredentialStore(profile)
 	if err != nil {
 		return err
 	}
@@ -147,17 +239,15 @@ func SaveToken(token *TokenData) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(dir, "token.json"), data, 0600)
+	return store.Set(profile, credentialToken, string(data))
 }
 
+// ClearToken removes the token of the active profile.
 func ClearToken() error {
-	dir, err := ConfigDir()
+	profile := ActiveProfile()
+	store, err := ProfileCredentialStore(profile)
 	if err != nil {
 		return err
 	}
-	path := filepath.Join(dir, "token.json")
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		return err
-	}
-	return nil
+	return store.Delete(profile, credentialToken)
 }
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Credential store kinds, selectable per profile with 'bb auth login --store'.
+const (
+	StorePlaintext = "plaintext"
+	StoreKeyring   = "keyring"
+	StoreEncrypted = "encrypted"
+)
+
+// Keys under which secrets are kept in a CredentialStore.
+const (
+	credentialToken       = "token"
+	credentialOAuthSecret = "oauth_secret"
+)
+
+// ErrCredentialNotFound is returned by CredentialStore.Get when no secret is
+// stored under the requested key.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore persists secrets (OAuth tokens and consumer secrets) for a
+// profile outside of the plaintext config files.
+type CredentialStore interface {
+	// Name returns the store kind, e.g. StoreKeyring.
+	Name() string
+	// Get returns the secret stored under key, or ErrCredentialNotFound.
+	Get(profile, key string) (string, error)
+	// Set stores value under key, replacing any previous value.
+	Set(profile, key, value string) error
+	// Delete removes the secret stored under key. Missing keys are not an error.
+	Delete(profile, key string) error
+}
+
+// StoreKinds lists the supported credential store kinds.
+func StoreKinds() []string {
+	return []string{StorePlaintext, StoreKeyring, StoreEncrypted}
+}
+
+// OpenCredentialStore returns the credential store of the given kind. An
+// empty kind selects the plaintext store.
+func OpenCredentialStore(kind string) (CredentialStore, error) {
+	switch kind {
+	case "", StorePlaintext:
+		return plaintextStore{}, nil
+	case StoreKeyring:
+		return newKeyringStore()
+	case StoreEncrypted:
+		return encryptedStore{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q: must be one of plaintext, keyring, encrypted", kind)
+	}
+}
+
+// ProfileCredentialStore returns the credential store used by the named profile.
+func ProfileCredentialStore(profile string) (CredentialStore, error) {
+	cfg, err := readProfileConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	return OpenCredentialStore(cfg.CredentialStore)
+}
+
+// MigrateCredentials moves the named profile's token and OAuth consumer
+// secret into the credential store of the given kind and records the choice
+// in the profile's config. Secrets are removed from the previous store only
+// after they have been written to the new one.
+func MigrateCredentials(profile, kind string) error {
+	cfg, err := readProfileConfig(profile)
+	if err != nil {
+		return err
+	}
+	if kind == StorePlaintext {
+		kind = ""
+	}
+	if cfg.CredentialStore == kind {
+		return nil
+	}
+
+	from, err := OpenCredentialStore(cfg.CredentialStore)
+	if err != nil {
+		return err
+	}
+	to, err := OpenCredentialStore(kind)
+	if err != nil {
+		return err
+	}
+
+	secrets := map[string]string{}
+	for _, key := range []string{credentialToken, credentialOAuthSecret} {
+		value, err := from.Get(profile, key)
+		if errors.Is(err, ErrCredentialNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s store: %w", key, from.Name(), err)
+		}
+		secrets[key] = value
+	}
+
+	for key, value := range secrets {
+		if err := to.Set(profile, key, value); err != nil {
+			return fmt.Errorf("failed to write %s to %s store: %w", key, to.Name(), err)
+		}
+	}
+
+	// Record the new store before clearing the old one, so a failure in
+	// between never leaves the profile pointing at an empty store.
+	cfg, err = readProfileConfig(profile)
+	if err != nil {
+		return err
+	}
+	cfg.CredentialStore = kind
+	if kind != "" {
+		cfg.OAuthSecret = ""
+	}
+	if err := writeProfileConfig(profile, cfg); err != nil {
+		return err
+	}
+
+	for key := range secrets {
+		if err := from.Delete(profile, key); err != nil {
+			return fmt.Errorf("failed to remove %s from %s store: %w", key, from.Name(), err)
+		}
+	}
+	return nil
+}
+
+// readProfileConfig reads the profile's config.json as stored on disk,
+// without resolving secrets from its credential store.
+func readProfileConfig(profile string) (*Config, error) {
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// writeProfileConfig writes cfg to the profile's config.json as is.
+func writeProfileConfig(profile string, cfg *Config) error {
+	dir, err := ensureProfileDir(profile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "config.json"), data, 0600)
+}
+
+// plaintextStore keeps secrets in the profile's token.json and config.json,
+// as the CLI always has. It is the default store.
+type plaintextStore struct{}
+
+func (plaintextStore) Name() string { return StorePlaintext }
+
+func (plaintextStore) Get(profile, key string) (string, error) {
+	switch key {
+	case credentialToken:
+		dir, err := ProfileDir(profile)
+		if err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "token.json"))
+		if os.IsNotExist(err) {
+			return "", ErrCredentialNotFound
+		}
+		return string(data), err
+	case credentialOAuthSecret:
+		cfg, err := readProfileConfig(profile)
+		if err != nil {
+			return "", err
+		}
+		if cfg.OAuthSecret == "" {
+			return "", ErrCredentialNotFound
+		}
+		return cfg.OAuthSecret, nil
+	}
+	return "", ErrCredentialNotFound
+}
+
+func (plaintextStore) Set(profile, key, value string) error {
+	switch key {
+	case credentialToken:
+		dir, err := ensureProfileDir(profile)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, "token.json"), []byte(value), 0600)
+	case credentialOAuthSecret:
+		cfg, err := readProfileConfig(profile)
+		if err != nil {
+			return err
+		}
+		cfg.OAuthSecret = value
+		return writeProfileConfig(profile, cfg)
+	}
+	return fmt.Errorf("unsupported credential key %q", key)
+}
+
+func (plaintextStore) Delete(profile, key string) error {
+	switch key {
+	case credentialToken:
+		dir, err := ProfileDir(profile)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(dir, "token.json")); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case credentialOAuthSecret:
+		cfg, err := readProfileConfig(profile)
+		if err != nil || cfg.OAuthSecret == "" {
+			return err
+		}
+		cfg.OAuthSecret = ""
+		return writeProfileConfig(profile, cfg)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeSecretTool replaces secret-tool with an in-memory keyring for a test.
+func fakeSecretTool(t *testing.T) map[string]string {
+	t.Helper()
+	secrets := map[string]string{}
+	orig := runSecretTool
+	runSecretTool = func(stdin string, args ...string) (string, error) {
+		var attrs []string
+		for _, a := range args[1:] {
+			if !strings.HasPrefix(a, "--") {
+				attrs = append(attrs, a)
+			}
+		}
+		id := strings.Join(attrs, "/")
+		switch args[0] {
+		case "store":
+			secrets[id] = stdin
+		case "lookup":
+			return secrets[id], nil
+		case "clear":
+			delete(secrets, id)
+		}
+		return "", nil
+	}
+	t.Cleanup(func() { runSecretTool = orig })
+	return secrets
+}
+
+func TestPlaintextStore_FilesUnchanged(t *testing.T) {
+	dir := setupProfiles(t)
+
+	if err := SaveConfig(&Config{OAuthKey: "key", OAuthSecret: "secret"}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	if err := SaveToken(&TokenData{AccessToken: "access"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("reading config.json: %v", err)
+	}
+	if !strings.Contains(string(data), `"oauth_secret": "secret"`) {
+		t.Errorf("config.json should keep the secret in plaintext, got %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "token.json")); err != nil {
+		t.Errorf("token.json should exist: %v", err)
+	}
+}
+
+func TestEncryptedStore_RoundTrip(t *testing.T) {
+	dir := setupProfiles(t)
+	t.Setenv(EnvPassphrase, "correct horse")
+
+	if err := SaveConfig(&Config{OAuthKey: "key", OAuthSecret: "secret", CredentialStore: StoreEncrypted}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	if err := SaveToken(&TokenData{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "token.json")); !os.IsNotExist(err) {
+		t.Errorf("token.json should not be written, stat err = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "config.json"))
+	if strings.Contains(string(data), `"oauth_secret": "secret"`) {
+		t.Errorf("config.json should not contain the consumer secret, got %s", data)
+	}
+	enc, _ := os.ReadFile(filepath.Join(dir, encryptedFile))
+	if strings.Contains(string(enc), "access") {
+		t.Error("credentials.enc should not contain the token in plaintext")
+	}
+
+	token, err := LoadToken()
+	if err != nil {
+		t.Fatalf("LoadToken() error: %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+		t.Errorf("LoadToken() = %+v", token)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.OAuthSecret != "secret" {
+		t.Errorf("OAuthSecret = %q, want %q", cfg.OAuthSecret, "secret")
+	}
+
+	t.Setenv(EnvPassphrase, "wrong")
+	if _, err := LoadToken(); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("LoadToken() with wrong passphrase error = %v", err)
+	}
+}
+
+func TestEncryptedStore_NoPassphrase(t *testing.T) {
+	setupProfiles(t)
+	t.Setenv(EnvPassphrase, "")
+	SetPassphrasePrompt(nil)
+
+	if err := SaveConfig(&Config{CredentialStore: StoreEncrypted}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	err := SaveToken(&TokenData{AccessToken: "access"})
+	if err == nil || !strings.Contains(err.Error(), EnvPassphrase) {
+		t.Errorf("SaveToken() error = %v, want mention of %s", err, EnvPassphrase)
+	}
+}
+
+func TestEncryptedStore_PassphrasePerProfile(t *testing.T) {
+	setupProfiles(t)
+	t.Setenv(EnvPassphrase, "")
+	passphrases := map[string]string{DefaultProfile: "first", "work": "second"}
+	var asked []string
+	SetPassphrasePrompt(func(profile string) (string, error) {
+		asked = append(asked, profile)
+		return passphrases[profile], nil
+	})
+	t.Cleanup(func() { SetPassphrasePrompt(nil) })
+
+	for _, profile := range []string{DefaultProfile, "work"} {
+		SetProfile(profile)
+		if err := SaveConfig(&Config{CredentialStore: StoreEncrypted}); err != nil {
+			t.Fatalf("SaveConfig(%s) error: %v", profile, err)
+		}
+		if err := SaveToken(&TokenData{AccessToken: "access-" + profile}); err != nil {
+			t.Fatalf("SaveToken(%s) error: %v", profile, err)
+		}
+	}
+	for _, profile := range []string{DefaultProfile, "work"} {
+		token, err := LoadProfileToken(profile)
+		if err != nil {
+			t.Fatalf("LoadProfileToken(%s) error: %v", profile, err)
+		}
+		if token.AccessToken != "access-"+profile {
+			t.Errorf("LoadProfileToken(%s) = %q", profile, token.AccessToken)
+		}
+	}
+	if want := []string{DefaultProfile, "work"}; !slices.Equal(asked, want) {
+		t.Errorf("asked for the passphrases of %v, want %v", asked, want)
+	}
+}
+
+func TestHasProfileToken_DoesNotUnlock(t *testing.T) {
+	setupProfiles(t)
+	t.Setenv(EnvPassphrase, "correct horse")
+	if err := SaveConfig(&Config{OAuthSecret: "secret", CredentialStore: StoreEncrypted}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+
+	t.Setenv(EnvPassphrase, "")
+	SetPassphrasePrompt(func(string) (string, error) {
+		t.Error("asked for a passphrase")
+		return "", errors.New("no passphrase")
+	})
+	t.Cleanup(func() { SetPassphrasePrompt(nil) })
+
+	if ok, err := HasProfileToken(DefaultProfile); err != nil || ok {
+		t.Errorf("HasProfileToken() without a token = %v, %v", ok, err)
+	}
+	t.Setenv(EnvPassphrase, "correct horse")
+	if err := SaveToken(&TokenData{AccessToken: "access"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+	t.Setenv(EnvPassphrase, "")
+	if ok, err := HasProfileToken(DefaultProfile); err != nil || !ok {
+		t.Errorf("HasProfileToken() with a token = %v, %v", ok, err)
+	}
+	if cfg, err := LoadProfileMetadata(DefaultProfile); err != nil || cfg.CredentialStore != StoreEncrypted {
+		t.Errorf("LoadProfileMetadata() = %+v, %v", cfg, err)
+	}
+}
+
+func TestKeyringStore_RoundTrip(t *testing.T) {
+	if _, err := newKeyringStore(); err != nil {
+		t.Skip(err)
+	}
+	dir := setupProfiles(t)
+	secrets := fakeSecretTool(t)
+
+	if err := SaveConfig(&Config{OAuthSecret: "secret", CredentialStore: StoreKeyring}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	if err := SaveToken(&TokenData{AccessToken: "access"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+	if len(secrets) != 2 {
+		t.Errorf("keyring holds %d secrets, want 2", len(secrets))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "token.json")); !os.IsNotExist(err) {
+		t.Errorf("token.json should not be written, stat err = %v", err)
+	}
+
+	token, err := LoadToken()
+	if err != nil || token.AccessToken != "access" {
+		t.Errorf("LoadToken() = %+v, %v", token, err)
+	}
+
+	if err := ClearToken(); err != nil {
+		t.Fatalf("ClearToken() error: %v", err)
+	}
+	if _, err := LoadToken(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadToken() after ClearToken error = %v, want not exist", err)
+	}
+}
+
+func TestMigrateCredentials(t *testing.T) {
+	dir := setupProfiles(t)
+	t.Setenv(EnvPassphrase, "correct horse")
+
+	if err := SaveConfig(&Config{OAuthKey: "key", OAuthSecret: "secret"}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	if err := SaveToken(&TokenData{AccessToken: "access"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+
+	if err := MigrateCredentials(DefaultProfile, StoreEncrypted); err != nil {
+		t.Fatalf("MigrateCredentials(encrypted) error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "token.json")); !os.IsNotExist(err) {
+		t.Errorf("token.json should be removed after migration, stat err = %v", err)
+	}
+	store, err := ProfileCredentialStore(DefaultProfile)
+	if err != nil || store.Name() != StoreEncrypted {
+		t.Fatalf("ProfileCredentialStore() = %v, %v", store, err)
+	}
+	if token, err := LoadToken(); err != nil || token.AccessToken != "access" {
+		t.Errorf("LoadToken() after migration = %+v, %v", token, err)
+	}
+
+	if err := MigrateCredentials(DefaultProfile, StorePlaintext); err != nil {
+		t.Fatalf("MigrateCredentials(plaintext) error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, encryptedFile)); !os.IsNotExist(err) {
+		t.Errorf("credentials.enc should be removed after migration, stat err = %v", err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.OAuthSecret != "secret" || cfg.CredentialStore != "" {
+		t.Errorf("LoadConfig() = %+v, want plaintext secret restored", cfg)
+	}
+	if token, err := LoadToken(); err != nil || token.AccessToken != "access" {
+		t.Errorf("LoadToken() after migrating back = %+v, %v", token, err)
+	}
+}
+
+func TestOpenCredentialStore_Unknown(t *testing.T) {
+	if _, err := OpenCredentialStore("vault"); err == nil {
+		t.Error("OpenCredentialStore(\"vault\") should fail")
+	}
+}
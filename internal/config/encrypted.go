@@ -0,0 +1,286 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// EnvPassphrase supplies the passphrase of the encrypted credential store
+// non-interactively.
+const EnvPassphrase = "BB_CREDENTIAL_PASSPHRASE"
+
+const (
+	encryptedFile       = "credentials.enc"
+	encryptedVersion    = 1
+	pbkdf2Iterations    = 600000
+	encryptionKeyLength = 32
+)
+
+var (
+	passphraseMu     sync.Mutex
+	passphrasePrompt func(profile string) (string, error)
+	// passphraseCache maps the path of each credential file to the
+	// passphrase that was entered for it.
+	passphraseCache = map[string]string{}
+)
+
+// SetPassphrasePrompt installs the function used to ask for the passphrase
+// of a profile's encrypted store when BB_CREDENTIAL_PASSPHRASE is not set.
+// Each answer is remembered for the rest of the process, for that profile's
+// store only.
+func SetPassphrasePrompt(prompt func(profile string) (string, error)) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	passphrasePrompt = prompt
+	passphraseCache = map[string]string{}
+}
+
+// passphrase returns the passphrase of the profile's credential file at path.
+func passphrase(profile, path string) (string, error) {
+	if p := os.Getenv(EnvPassphrase); p != "" {
+		return p, nil
+	}
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	if p, ok := passphraseCache[path]; ok {
+		return p, nil
+	}
+	if passphrasePrompt == nil {
+		return "", fmt.Errorf("the encrypted credential store needs a passphrase: set %s", EnvPassphrase)
+	}
+	p, err := passphrasePrompt(profile)
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	passphraseCache[path] = p
+	return p, nil
+}
+
+// encryptedEnvelope is the on-disk format of credentials.enc. The plaintext
+// is a JSON object mapping credential keys to secrets, sealed with
+// AES-256-GCM under a key derived from the passphrase with PBKDF2-SHA256.
+// The keys are also listed in the clear, so that the secrets a profile has
+// can be told without the passphrase.
+type encryptedEnvelope struct {
+	Version    int      `json:"version"`
+	Keys       []string `json:"keys,omitempty"`
+	Salt       []byte   `json:"salt"`
+	Nonce      []byte   `json:"nonce"`
+	Ciphertext []byte   `json:"ciphertext"`
+}
+
+// encryptedStore keeps a profile's secrets in a passphrase-encrypted file.
+// It works on every platform and is the fallback when no keyring is available.
+type encryptedStore struct{}
+
+func (encryptedStore) Name() string { return StoreEncrypted }
+
+func (s encryptedStore) Get(profile, key string) (string, error) {
+	secrets, err := s.load(profile)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+func (s encryptedStore) Set(profile, key, value string) error {
+	secrets, err := s.load(profile)
+	if err != nil {
+		return err
+	}
+	secrets[key] = value
+	return s.save(profile, secrets)
+}
+
+func (s encryptedStore) Delete(profile, key string) error {
+	secrets, err := s.load(profile)
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	return s.save(profile, secrets)
+}
+
+// Has reports whether a secret is stored under key without decrypting the
+// file. Files written before the keys were listed are assumed to hold it.
+func (encryptedStore) Has(profile, key string) (bool, error) {
+	env, _, err := readEnvelope(profile)
+	if err != nil || env == nil {
+		return false, err
+	}
+	return env.Keys == nil || slices.Contains(env.Keys, key), nil
+}
+
+func encryptedPath(profile string) (string, error) {
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, encryptedFile), nil
+}
+
+// readEnvelope reads the profile's credential file. It returns a nil
+// envelope if there is no file.
+func readEnvelope(profile string) (*encryptedEnvelope, string, error) {
+	path, err := encryptedPath(profile)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, path, nil
+		}
+		return nil, "", err
+	}
+
+	var env encryptedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, "", fmt.Errorf("corrupt credential file %s: %w", path, err)
+	}
+	if env.Version != encryptedVersion {
+		return nil, "", fmt.Errorf("unsupported credential file version %d", env.Version)
+	}
+	return &env, path, nil
+}
+
+func (encryptedStore) load(profile string) (map[string]string, error) {
+	env, path, err := readEnvelope(profile)
+	if err != nil {
+		return nil, err
+	}
+	if env == nil {
+		return map[string]string{}, nil
+	}
+
+	pass, err := passphrase(profile, path)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(pass, env.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt credentials: wrong passphrase or corrupt file")
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("corrupt credential file %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+func (encryptedStore) save(profile string, secrets map[string]string) error {
+	if _, err := ensureProfileDir(profile); err != nil {
+		return err
+	}
+	path, err := encryptedPath(profile)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	pass, err := passphrase(profile, path)
+	if err != nil {
+		return err
+	}
+	salt, err := existingSalt(path)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(pass, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	keys := slices.Sorted(maps.Keys(secrets))
+	data, err := json.MarshalIndent(encryptedEnvelope{
+		Version:    encryptedVersion,
+		Keys:       keys,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// existingSalt returns the salt of the credential file at path, so rewrites
+// can reuse its derived key, or a fresh random salt if there is no file.
+func existingSalt(path string) ([]byte, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		var env encryptedEnvelope
+		if json.Unmarshal(data, &env) == nil && len(env.Salt) > 0 {
+			return env.Salt, nil
+		}
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// derivedKeys caches PBKDF2 output so a command that reads several secrets
+// pays the key derivation cost once.
+var derivedKeys sync.Map
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	cacheKey := passphrase + "\x00" + string(salt)
+	var key []byte
+	if k, ok := derivedKeys.Load(cacheKey); ok {
+		key = k.([]byte)
+	} else {
+		var err error
+		key, err = pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, encryptionKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		derivedKeys.Store(cacheKey, key)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
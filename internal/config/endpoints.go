@@ -0,0 +1,110 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultGitHost is the hostname of Bitbucket Cloud, used by its website and
+// git remotes.
+const DefaultGitHost = "bitbucket.org"
+
+// DefaultGitRemote is the remote used to detect the current repository.
+const DefaultGitRemote = "origin"
+
+// Environment variables that override the configured endpoints.
+const (
+	EnvAPIURL   = "BB_API_URL"
+	EnvAuthURL  = "BB_AUTH_URL"
+	EnvTokenURL = "BB_TOKEN_URL"
+	EnvGitHosts = "BB_GIT_HOSTS"
+	// EnvGitRemote overrides the git_remote config setting.
+	EnvGitRemote = "BB_GIT_REMOTE"
+)
+
+// APIBaseURL returns the Bitbucket API base URL without a trailing slash.
+// BB_API_URL takes precedence over the api_url config setting.
+// It is safe to call on a nil Config.
+func (c *Config) APIBaseURL() string {
+	var configured string
+	if c != nil {
+		configured = c.APIURL
+	}
+	return resolveURL(EnvAPIURL, configured, BitbucketAPI)
+}
+
+// OAuthAuthorizeURL returns the OAuth 2.0 authorization endpoint.
+// BB_AUTH_URL takes precedence over the auth_url config setting.
+func (c *Config) OAuthAuthorizeURL() string {
+	var configured string
+	if c != nil {
+		configured = c.AuthURL
+	}
+	return resolveURL(EnvAuthURL, configured, AuthURL)
+}
+
+// OAuthTokenURL returns the OAuth 2.0 token endpoint.
+// BB_TOKEN_URL takes precedence over the token_url config setting.
+func (c *Config) OAuthTokenURL() string {
+	var configured string
+	if c != nil {
+		configured = c.TokenURL
+	}
+	return resolveURL(EnvTokenURL, configured, TokenURL)
+}
+
+// WebURL returns the base URL of the Bitbucket website without a trailing
+// slash: the scheme and host of the OAuth authorization endpoint, which is
+// served by the website. It is safe to call on a nil Config.
+func (c *Config) WebURL() string {
+	u, err := url.Parse(c.OAuthAuthorizeURL())
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "https://" + DefaultGitHost
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// GitHostnames returns the hostnames whose git remotes are treated as
+// Bitbucket repositories: bitbucket.org plus any hosts listed in
+// BB_GIT_HOSTS (comma-separated) or, if unset, the git_hosts config setting.
+func (c *Config) GitHostnames() []string {
+	hosts := []string{DefaultGitHost}
+	var extra []string
+	if env := os.Getenv(EnvGitHosts); env != "" {
+		extra = strings.Split(env, ",")
+	} else if c != nil {
+		extra = c.GitHosts
+	}
+	for _, h := range extra {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" && h != DefaultGitHost {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// GitRemoteName returns the git remote used to detect the current
+// repository: BB_GIT_REMOTE, then the git_remote config setting, then
+// "origin". It is safe to call on a nil Config.
+func (c *Config) GitRemoteName() string {
+	if env := strings.TrimSpace(os.Getenv(EnvGitRemote)); env != "" {
+		return env
+	}
+	if c != nil && c.GitRemote != "" {
+		return c.GitRemote
+	}
+	return DefaultGitRemote
+}
+
+func resolveURL(envVar, configured, fallback string) string {
+	u := os.Getenv(envVar)
+	if u == "" {
+		u = configured
+	}
+	if u == "" {
+		u = fallback
+	}
+	return strings.TrimRight(u, "/")
+}
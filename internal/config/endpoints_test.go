@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAPIBaseURL(t *testing.T) {
+	t.Setenv(EnvAPIURL, "")
+
+	var nilCfg *Config
+	if got := nilCfg.APIBaseURL(); got != BitbucketAPI {
+		t.Errorf("nil config APIBaseURL() = %q, want %q", got, BitbucketAPI)
+	}
+
+	cfg := &Config{APIURL: "http://localhost:8080/2.0/"}
+	if got := cfg.APIBaseURL(); got != "http://localhost:8080/2.0" {
+		t.Errorf("APIBaseURL() = %q, want configured URL without trailing slash", got)
+	}
+
+	t.Setenv(EnvAPIURL, "https://gateway.example.com/bitbucket/2.0")
+	if got := cfg.APIBaseURL(); got != "https://gateway.example.com/bitbucket/2.0" {
+		t.Errorf("APIBaseURL() = %q, want env override", got)
+	}
+}
+
+func TestOAuthURLs(t *testing.T) {
+	t.Setenv(EnvAuthURL, "")
+	t.Setenv(EnvTokenURL, "")
+
+	cfg := &Config{}
+	if got := cfg.OAuthAuthorizeURL(); got != AuthURL {
+		t.Errorf("OAuthAuthorizeURL() = %q, want %q", got, AuthURL)
+	}
+	if got := cfg.OAuthTokenURL(); got != TokenURL {
+		t.Errorf("OAuthTokenURL() = %q, want %q", got, TokenURL)
+	}
+
+	cfg.TokenURL = "http://localhost:8080/token"
+	if got := cfg.OAuthTokenURL(); got != cfg.TokenURL {
+		t.Errorf("OAuthTokenURL() = %q, want %q", got, cfg.TokenURL)
+	}
+
+	t.Setenv(EnvAuthURL, "http://localhost:9090/authorize")
+	if got := cfg.OAuthAuthorizeURL(); got != "http://localhost:9090/authorize" {
+		t.Errorf("OAuthAuthorizeURL() = %q, want env override", got)
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	t.Setenv(EnvAuthURL, "")
+
+	var nilCfg *Config
+	if got := nilCfg.WebURL(); got != "https://bitbucket.org" {
+		t.Errorf("WebURL() = %q, want https://bitbucket.org", got)
+	}
+
+	cfg := &Config{AuthURL: "https://bitbucket.example.com/site/oauth2/authorize"}
+	if got := cfg.WebURL(); got != "https://bitbucket.example.com" {
+		t.Errorf("WebURL() = %q, want the authorize URL's host", got)
+	}
+
+	t.Setenv(EnvAuthURL, "http://localhost:9090/authorize")
+	if got := cfg.WebURL(); got != "http://localhost:9090" {
+		t.Errorf("WebURL() = %q, want env override", got)
+	}
+}
+
+func TestGitHostnames(t *testing.T) {
+	t.Setenv(EnvGitHosts, "")
+
+	if got := (*Config)(nil).GitHostnames(); !reflect.DeepEqual(got, []string{DefaultGitHost}) {
+		t.Errorf("nil config GitHostnames() = %v", got)
+	}
+
+	cfg := &Config{GitHosts: []string{"Git.Example.com", "bitbucket.org", " "}}
+	want := []string{DefaultGitHost, "git.example.com"}
+	if got := cfg.GitHostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitHostnames() = %v, want %v", got, want)
+	}
+
+	t.Setenv(EnvGitHosts, "localhost:8080, mirror.example.com")
+	want = []string{DefaultGitHost, "localhost:8080", "mirror.example.com"}
+	if got := cfg.GitHostnames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitHostnames() with env = %v, want %v", got, want)
+	}
+}
+
+func TestGitRemoteName(t *testing.T) {
+	t.Setenv(EnvGitRemote, "")
+
+	if got := (*Config)(nil).GitRemoteName(); got != DefaultGitRemote {
+		t.Errorf("nil config GitRemoteName() = %q, want %q", got, DefaultGitRemote)
+	}
+
+	cfg := &Config{GitRemote: "upstream"}
+	if got := cfg.GitRemoteName(); got != "upstream" {
+		t.Errorf("GitRemoteName() = %q, want upstream", got)
+	}
+
+	t.Setenv(EnvGitRemote, "bitbucket")
+	if got := cfg.GitRemoteName(); got != "bitbucket" {
+		t.Errorf("GitRemoteName() with env = %q, want bitbucket", got)
+	}
+}
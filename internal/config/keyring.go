@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringService is the Secret Service "service" attribute used for all
+// secrets written by the CLI.
+const keyringService = AppName
+
+// runSecretTool runs the secret-tool binary with the given arguments and
+// stdin. It is a variable so tests can substitute a fake.
+var runSecretTool = func(stdin string, args ...string) (string, error) {
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%w: %s", err, msg)
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
+
+// keyringStore keeps secrets in the desktop keyring through the freedesktop
+// Secret Service D-Bus API (GNOME Keyring, KWallet), using libsecret's
+// secret-tool. Only Linux is supported.
+type keyringStore struct{}
+
+func newKeyringStore() (CredentialStore, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("the keyring credential store is not supported on %s; use --store=encrypted instead", runtime.GOOS)
+	}
+	return keyringStore{}, nil
+}
+
+func (keyringStore) Name() string { return StoreKeyring }
+
+func keyringAttributes(profile, key string) []string {
+	return []string{"service", keyringService, "profile", profile, "key", key}
+}
+
+func (keyringStore) Get(profile, key string) (string, error) {
+	out, err := runSecretTool("", append([]string{"lookup"}, keyringAttributes(profile, key)...)...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		// secret-tool exits with status 1 and no output when nothing matches.
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && out == "" {
+			return "", ErrCredentialNotFound
+		}
+		return "", keyringError(err)
+	}
+	if out == "" {
+		return "", ErrCredentialNotFound
+	}
+	return out, nil
+}
+
+func (keyringStore) Set(profile, key, value string) error {
+	label := fmt.Sprintf("%s %s (%s)", AppName, key, profile)
+	args := append([]string{"store", "--label=" + label}, keyringAttributes(profile, key)...)
+	if _, err := runSecretTool(value, args...); err != nil {
+		return keyringError(err)
+	}
+	return nil
+}
+
+func (keyringStore) Delete(profile, key string) error {
+	if _, err := runSecretTool("", append([]string{"clear"}, keyringAttributes(profile, key)...)...); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil
+		}
+		return keyringError(err)
+	}
+	return nil
+}
+
+func keyringError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("secret-tool not found; install libsecret-tools (Debian/Ubuntu) or libsecret (Fedora/Arch) to use the keyring store: %w", err)
+	}
+	return fmt.Errorf("secret service error: %w", err)
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DefaultProfile is the profile whose files live directly in ConfigDir, so
+// configurations written before profiles existed keep working unchanged.
+const DefaultProfile = "default"
+
+// EnvProfile selects the active profile, overriding the persisted choice.
+const EnvProfile = "BB_PROFILE"
+
+var (
+	profileMu       sync.RWMutex
+	profileOverride string
+)
+
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateProfileName reports whether name can be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// SetProfile selects the profile used by this process, taking precedence
+// over BB_PROFILE and the persisted active profile. An empty name clears the
+// override. It backs the global --profile flag.
+func SetProfile(name string) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profileOverride = name
+}
+
+// ActiveProfile returns the profile in use, resolved from SetProfile, then
+// BB_PROFILE, then the profile persisted by SwitchProfile, then DefaultProfile.
+func ActiveProfile() string {
+	profileMu.RLock()
+	override := profileOverride
+	profileMu.RUnlock()
+	if override != "" {
+		return override
+	}
+	if env := os.Getenv(EnvProfile); env != "" {
+		return env
+	}
+	if st, err := loadState(); err == nil && st.ActiveProfile != "" {
+		return st.ActiveProfile
+	}
+	return DefaultProfile
+}
+
+// SwitchProfile persists name as the active profile for future invocations.
+// The profile must already exist.
+func SwitchProfile(name string) error {
+	exists, err := ProfileExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	st, err := loadState()
+	if err != nil {
+		return err
+	}
+	st.ActiveProfile = name
+	if name == DefaultProfile {
+		st.ActiveProfile = ""
+	}
+	return saveState(st)
+}
+
+// ProfileDir returns the directory holding the named profile's files.
+func ProfileDir(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if name == DefaultProfile {
+		return dir, nil
+	}
+	return filepath.Join(dir, "profiles", name), nil
+}
+
+// ProfileExists reports whether the named profile has a saved config or token.
+func ProfileExists(name string) (bool, error) {
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range []string{"config.json", "token.json"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListProfiles returns the names of all saved profiles, sorted, with
+// DefaultProfile first. The default profile is always included.
+func ListProfiles() ([]string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == DefaultProfile || ValidateProfileName(e.Name()) != nil {
+			continue
+		}
+		if ok, _ := ProfileExists(e.Name()); ok {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// DeleteProfile removes a non-default profile and its files. If it was the
+// persisted active profile, the default profile becomes active.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the %s profile cannot be deleted", DefaultProfile)
+	}
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return err
+	}
+	// Files are removed with the directory; keyring entries live elsewhere.
+	if store, err := ProfileCredentialStore(name); err == nil && store.Name() == StoreKeyring {
+		for _, key := range []string{credentialToken, credentialOAuthSecret} {
+			if err := store.Delete(name, key); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	st, err := loadState()
+	if err != nil {
+		return err
+	}
+	if st.ActiveProfile == name {
+		st.ActiveProfile = ""
+		return saveState(st)
+	}
+	return nil
+}
+
+func ensureProfileDir(name string) (string, error) {
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// state holds settings that apply across profiles.
+type state struct {
+	ActiveProfile string `json:"active_profile,omitempty"`
+}
+
+func loadState() (*state, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "state.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &state{}, nil
+		}
+		return nil, err
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func saveState(st *state) error {
+	dir, err := ConfigDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "state.json"), data, 0600)
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setupProfiles isolates the config directory and profile selection for a test.
+func setupProfiles(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv(EnvProfile, "")
+	ResetConfigDirCache()
+	SetProfile("")
+	t.Cleanup(func() {
+		SetProfile("")
+		ResetConfigDirCache()
+	})
+	return filepath.Join(tmpDir, AppName)
+}
+
+func TestProfiles_IsolatedTokens(t *testing.T) {
+	dir := setupProfiles(t)
+
+	if err := SaveToken(&TokenData{AccessToken: "personal"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+
+	SetProfile("bot")
+	if err := SaveToken(&TokenData{AccessToken: "bot-token"}); err != nil {
+		t.Fatalf("SaveToken() error: %v", err)
+	}
+	if err := SaveConfig(&Config{DefaultWorkspace: "bots"}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "profiles", "bot", "token.json")); err != nil {
+		t.Errorf("expected bot token in profiles/bot: %v", err)
+	}
+
+	SetProfile("")
+	token, err := LoadToken()
+	if err != nil || token.AccessToken != "personal" {
+		t.Errorf("default profile token = %v, %v; want personal", token, err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil || cfg.DefaultWorkspace != "" {
+		t.Errorf("default profile config leaked from bot profile: %+v, %v", cfg, err)
+	}
+
+	botToken, err := LoadProfileToken("bot")
+	if err != nil || botToken.AccessToken != "bot-token" {
+		t.Errorf("LoadProfileToken(bot) = %v, %v", botToken, err)
+	}
+}
+
+func TestActiveProfile_Precedence(t *testing.T) {
+	setupProfiles(t)
+
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Errorf("ActiveProfile() = %q, want %q", got, DefaultProfile)
+	}
+
+	SetProfile("work")
+	if err := SaveConfig(&Config{}); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+	SetProfile("")
+
+	if err := SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile() error: %v", err)
+	}
+	if got := ActiveProfile(); got != "work" {
+		t.Errorf("ActiveProfile() after switch = %q, want work", got)
+	}
+
+	t.Setenv(EnvProfile, "env")
+	if got := ActiveProfile(); got != "env" {
+		t.Errorf("ActiveProfile() with BB_PROFILE = %q, want env", got)
+	}
+
+	SetProfile("flag")
+	if got := ActiveProfile(); got != "flag" {
+		t.Errorf("ActiveProfile() with override = %q, want flag", got)
+	}
+}
+
+func TestSwitchProfile_Missing(t *testing.T) {
+	setupProfiles(t)
+
+	if err := SwitchProfile("nope"); err == nil {
+		t.Error("expected error switching to a missing profile")
+	}
+}
+
+func TestListAndDeleteProfiles(t *testing.T) {
+	setupProfiles(t)
+
+	for _, name := range []string{"zeta", "alpha"} {
+		SetProfile(name)
+		if err := SaveToken(&TokenData{AccessToken: name}); err != nil {
+			t.Fatalf("SaveToken() error: %v", err)
+		}
+	}
+	SetProfile("")
+
+	names, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() error: %v", err)
+	}
+	if want := []string{DefaultProfile, "alpha", "zeta"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListProfiles() = %v, want %v", names, want)
+	}
+
+	if err := SwitchProfile("alpha"); err != nil {
+		t.Fatalf("SwitchProfile() error: %v", err)
+	}
+	if err := DeleteProfile("alpha"); err != nil {
+		t.Fatalf("DeleteProfile() error: %v", err)
+	}
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Errorf("ActiveProfile() after deleting active profile = %q", got)
+	}
+	if err := DeleteProfile(DefaultProfile); err == nil {
+		t.Error("expected error deleting the default profile")
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"default", "bot", "work.ci", "team_1-a"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../escape", "a/b", ".hidden", "sp ace"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) expected error", name)
+		}
+	}
+}
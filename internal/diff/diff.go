@@ -0,0 +1,209 @@
+// Package diff parses unified diffs such as those returned by the Bitbucket
+// diff endpoints and filters them by path.
+package diff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineKind says whether a diff line was added, removed or left unchanged.
+type LineKind byte
+
+const (
+	Context LineKind = ' '
+	Added   LineKind = '+'
+	Removed LineKind = '-'
+)
+
+// Line is a line of a hunk. OldLine and NewLine are its line numbers in the
+// old and new file; OldLine is 0 for added lines and NewLine for removed ones.
+// Index is the position of the line in the parsed text, counting from 0.
+type Line struct {
+	Kind    LineKind
+	Text    string
+	OldLine int
+	NewLine int
+	Index   int
+}
+
+// Hunk is a block of changes in a file.
+type Hunk struct {
+	Header   string
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// File is the diff of a single file. OldPath is empty for added files and
+// NewPath for deleted ones.
+type File struct {
+	OldPath string
+	NewPath string
+	Header  []string
+	Binary  bool
+	Hunks   []Hunk
+}
+
+// Path returns the path of the file, preferring the new one.
+func (f *File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Stats returns the number of lines added and removed in the file.
+func (f *File) Stats() (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case Added:
+				added++
+			case Removed:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse splits a unified diff into files and hunks. Text it does not
+// recognise is kept in the file's header lines.
+func Parse(text string) []File {
+	var files []File
+	var file *File
+	var hunk *Hunk
+	var oldLine, newLine int
+
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, File{})
+			file = &files[len(files)-1]
+			hunk = nil
+			file.Header = append(file.Header, line)
+			file.OldPath, file.NewPath = gitPaths(strings.TrimPrefix(line, "diff --git "))
+			continue
+		}
+		if file == nil {
+			continue
+		}
+
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			file.Hunks = append(file.Hunks, Hunk{
+				Header:   line,
+				OldStart: atoi(m[1], 0),
+				OldLines: atoi(m[2], 1),
+				NewStart: atoi(m[3], 0),
+				NewLines: atoi(m[4], 1),
+			})
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+			continue
+		}
+
+		if hunk == nil {
+			file.Header = append(file.Header, line)
+			switch {
+			case strings.HasPrefix(line, "--- "):
+				file.OldPath = headerPath(strings.TrimPrefix(line, "--- "), "a/")
+			case strings.HasPrefix(line, "+++ "):
+				file.NewPath = headerPath(strings.TrimPrefix(line, "+++ "), "b/")
+			case strings.HasPrefix(line, "new file mode"):
+				file.OldPath = ""
+			case strings.HasPrefix(line, "deleted file mode"):
+				file.NewPath = ""
+			case strings.HasPrefix(line, "Binary files"):
+				file.Binary = true
+			}
+			continue
+		}
+
+		if line == "" {
+			// Some producers drop the leading space of empty context lines.
+			line = " "
+		}
+		l := Line{Kind: LineKind(line[0]), Text: line[1:], Index: i}
+		switch l.Kind {
+		case Added:
+			l.NewLine = newLine
+			newLine++
+		case Removed:
+			l.OldLine = oldLine
+			oldLine++
+		case Context:
+			l.OldLine, l.NewLine = oldLine, newLine
+			oldLine++
+			newLine++
+		default:
+			// "\ No newline at end of file" and the like.
+			continue
+		}
+		hunk.Lines = append(hunk.Lines, l)
+	}
+	return files
+}
+
+// FindFile returns the file whose new or old path is path, or nil.
+func FindFile(files []File, path string) *File {
+	for i := range files {
+		if files[i].NewPath == path || files[i].OldPath == path {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+// Around returns up to context lines either side of a line of f, together
+// with the index of that line in the result. The line is given by its number
+// in the new file, or in the old file when newLine is 0. ok is false when
+// the diff does not show the line.
+func (f *File) Around(oldLine, newLine, context int) (lines []Line, index int, ok bool) {
+	for _, h := range f.Hunks {
+		for i, l := range h.Lines {
+			if (newLine > 0 && l.NewLine == newLine) || (newLine == 0 && oldLine > 0 && l.OldLine == oldLine) {
+				start := max(0, i-context)
+				end := min(len(h.Lines), i+context+1)
+				return h.Lines[start:end], i - start, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// gitPaths splits the "a/old b/new" part of a "diff --git" line.
+func gitPaths(s string) (oldPath, newPath string) {
+	if strings.HasPrefix(s, "a/") {
+		if i := strings.Index(s, " b/"); i >= 0 {
+			return s[2:i], s[i+3:]
+		}
+	}
+	return s, s
+}
+
+func headerPath(s, prefix string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+func atoi(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
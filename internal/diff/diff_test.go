@@ -0,0 +1,89 @@
+package diff
+
+import "testing"
+
+const sample = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,5 +1,7 @@ package main
+ package main
+
+-import "fmt"
++import (
++	"fmt"
++)
+
+ func main() {}
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1 @@
++hello
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParse(t *testing.T) {
+	files := Parse(sample)
+	if len(files) != 4 {
+		t.Fatalf("Parse() returned %d files, want 4", len(files))
+	}
+
+	main := files[0]
+	if main.OldPath != "main.go" || main.NewPath != "main.go" {
+		t.Errorf("paths = %q, %q", main.OldPath, main.NewPath)
+	}
+	if len(main.Hunks) != 1 || len(main.Hunks[0].Lines) != 8 {
+		t.Fatalf("unexpected hunks: %+v", main.Hunks)
+	}
+	if added, removed := main.Stats(); added != 3 || removed != 1 {
+		t.Errorf("Stats() = %d, %d; want 3, 1", added, removed)
+	}
+	last := main.Hunks[0].Lines[7]
+	if last.Kind != Context || last.OldLine != 5 || last.NewLine != 7 || last.Index != 12 {
+		t.Errorf("last line = %+v", last)
+	}
+
+	if files[1].OldPath != "" || files[1].NewPath != "docs/new.md" || len(files[1].Hunks[0].Lines) != 1 {
+		t.Errorf("added file = %+v", files[1])
+	}
+	if files[2].Path() != "old.txt" || files[2].NewPath != "" {
+		t.Errorf("deleted file = %+v", files[2])
+	}
+	if !files[3].Binary || len(files[3].Hunks) != 0 {
+		t.Errorf("binary file = %+v", files[3])
+	}
+}
+
+func TestAround(t *testing.T) {
+	files := Parse(sample)
+	f := FindFile(files, "main.go")
+	if f == nil {
+		t.Fatal("FindFile() = nil")
+	}
+
+	lines, index, ok := f.Around(0, 4, 1)
+	if !ok || len(lines) != 3 || lines[index].Text != "\t\"fmt\"" {
+		t.Errorf("Around(new 4) = %+v, %d, %v", lines, index, ok)
+	}
+	lines, index, ok = f.Around(3, 0, 0)
+	if !ok || len(lines) != 1 || lines[index].Kind != Removed {
+		t.Errorf("Around(old 3) = %+v, %d, %v", lines, index, ok)
+	}
+	if _, _, ok := f.Around(0, 40, 2); ok {
+		t.Error("Around() found a line outside the diff")
+	}
+	if FindFile(files, "missing.go") != nil {
+		t.Error("FindFile() found a missing file")
+	}
+}
@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Stat is the summary of the changes to a file, as listed by the Bitbucket
+// diffstat endpoints.
+type Stat struct {
+	Path    string
+	OldPath string
+	Status  string
+	Added   int
+	Removed int
+}
+
+// MatchPath reports whether path matches the glob pattern, in which *
+// matches within a path segment and ** across segments. A pattern without a
+// slash matches the file name in any directory, and a pattern naming a
+// directory matches everything below it.
+func MatchPath(pattern, path string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if strings.HasPrefix(path, strings.TrimSuffix(pattern, "/")+"/") {
+		return true
+	}
+	target := path
+	if !strings.Contains(pattern, "/") {
+		target = path[strings.LastIndex(path, "/")+1:]
+	}
+
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" also matches no directory at all.
+					i++
+					re.WriteString("(.*/)?")
+				} else {
+					re.WriteString(".*")
+				}
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	ok, err := regexp.MatchString(re.String(), target)
+	return err == nil && ok
+}
+
+// matchAny reports whether either path matches one of the patterns. No
+// patterns match everything.
+func matchAny(patterns []string, paths ...string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		for _, p := range paths {
+			if p != "" && MatchPath(pattern, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterText returns the parts of a unified diff for files matching one of
+// the patterns, keeping the text of those files as it is.
+func FilterText(text string, patterns []string) string {
+	if len(patterns) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	for _, section := range splitFiles(text) {
+		files := Parse(section)
+		if len(files) == 1 && matchAny(patterns, files[0].OldPath, files[0].NewPath) {
+			sb.WriteString(section)
+		}
+	}
+	return sb.String()
+}
+
+// FilterStats returns the stats of files matching one of the patterns.
+func FilterStats(stats []Stat, patterns []string) []Stat {
+	var kept []Stat
+	for _, s := range stats {
+		if matchAny(patterns, s.OldPath, s.Path) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// splitFiles splits a unified diff into the text of each file.
+func splitFiles(text string) []string {
+	var sections []string
+	start := -1
+	for i := 0; i < len(text); {
+		end := strings.IndexByte(text[i:], '\n')
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += i + 1
+		}
+		if strings.HasPrefix(text[i:], "diff --git ") {
+			if start >= 0 {
+				sections = append(sections, text[start:i])
+			}
+			start = i
+		}
+		i = end
+	}
+	if start >= 0 {
+		sections = append(sections, text[start:])
+	}
+	return sections
+}
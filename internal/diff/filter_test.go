@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/diff/diff.go", true},
+		{"*.go", "README.md", false},
+		{"internal/*.go", "internal/diff/diff.go", false},
+		{"internal/**/*.go", "internal/diff/diff.go", true},
+		{"internal/**/*.go", "internal/main.go", true},
+		{"internal", "internal/diff/diff.go", true},
+		{"internal/", "internal/diff/diff.go", true},
+		{"./docs/*.md", "docs/new.md", true},
+		{"docs/?ew.md", "docs/new.md", true},
+		{"docs", "docsite/index.md", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilterText(t *testing.T) {
+	if got := FilterText(sample, nil); got != sample {
+		t.Error("FilterText without patterns should return the diff unchanged")
+	}
+
+	got := FilterText(sample, []string{"*.md", "old.txt"})
+	files := Parse(got)
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	if files[0].Path() != "docs/new.md" || files[1].Path() != "old.txt" {
+		t.Errorf("got files %q and %q", files[0].Path(), files[1].Path())
+	}
+	if !strings.Contains(got, "\\ No newline at end of file\n") {
+		t.Error("FilterText should keep the text of matching files as it is")
+	}
+
+	if got := FilterText(sample, []string{"nothing"}); got != "" {
+		t.Errorf("FilterText with no matches = %q, want empty", got)
+	}
+}
+
+func TestFilterStats(t *testing.T) {
+	stats := []Stat{
+		{Path: "main.go"},
+		{Path: "docs/new.md"},
+		{OldPath: "old.txt"},
+	}
+	if got := FilterStats(stats, nil); len(got) != 3 {
+		t.Errorf("FilterStats without patterns kept %d stats, want 3", len(got))
+	}
+	got := FilterStats(stats, []string{"old.txt"})
+	if len(got) != 1 || got[0].OldPath != "old.txt" {
+		t.Errorf("FilterStats = %+v, want old.txt only", got)
+	}
+}
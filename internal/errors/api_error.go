@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -135,27 +136,29 @@ func ParseAPIError(resp *http.Response, body []byte) *BBError {
 	}
 }
 
+// RateLimitReset returns the time at which the rate limit resets, from an
+// X-RateLimit-Reset header holding Unix epoch seconds or an RFC 3339 time.
+func RateLimitReset(h http.Header) (time.Time, bool) {
+	v := h.Get("X-RateLimit-Reset")
+	if v == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(epoch, 0), true
+	}
+	return time.Time{}, false
+}
+
 // extractRateLimitReset extracts the rate limit reset time from response headers.
 // It looks for X-RateLimit-Reset header and formats it as a human-readable time.
 func extractRateLimitReset(resp *http.Response) string {
-	resetHeader := resp.Header.Get("X-RateLimit-Reset")
-	if resetHeader == "" {
-		return ""
-	}
-
-	// Try to parse as Unix timestamp
-	var resetTime time.Time
-	if timestamp, err := time.Parse(time.RFC3339, resetHeader); err == nil {
-		resetTime = timestamp
-	} else {
-		// Some APIs use Unix epoch seconds
-		var epochSecs int64
-		if _, err := fmt.Sscanf(resetHeader, "%d", &epochSecs); err == nil {
-			resetTime = time.Unix(epochSecs, 0)
-		} else {
-			// Couldn't parse, return raw value
-			return resetHeader
-		}
+	resetTime, ok := RateLimitReset(resp.Header)
+	if !ok {
+		// Couldn't parse, return raw value
+		return resp.Header.Get("X-RateLimit-Reset")
 	}
 
 	// Format as human-readable time
@@ -166,8 +169,6 @@ func extractRateLimitReset(resp *http.Response) string {
 			return fmt.Sprintf("%d seconds", int(duration.Seconds()))
 		} else if duration < time.Hour {
 			return fmt.Sprintf("%d minutes", int(duration.Minutes()))
-		} else {
-			return resetTime.Format("3:04 PM MST")
 		}
 	}
 
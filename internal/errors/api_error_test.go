@@ -420,3 +420,30 @@ func TestParseAPIError_Integration(t *testing.T) {
 		t.Error("IsRateLimit() should return true for rate limit error")
 	}
 }
+
+func TestRateLimitReset(t *testing.T) {
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Time
+		ok     bool
+	}{
+		{"epoch seconds", fmt.Sprintf("%d", at.Unix()), at, true},
+		{"RFC 3339", at.Format(time.RFC3339), at, true},
+		{"missing", "", time.Time{}, false},
+		{"invalid", "soon", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.header != "" {
+				h.Set("X-RateLimit-Reset", tt.header)
+			}
+			got, ok := RateLimitReset(h)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("RateLimitReset() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/config"
 )
 
 // RemoteInfo holds the workspace and repository extracted from a Bitbucket remote URL.
@@ -57,31 +59,44 @@ func GetRemoteURL(remoteName string) (string, error) {
 // ParseBitbucketRemote extracts workspace and repository name from a Bitbucket remote URL.
 // Supports both SSH and HTTPS formats:
 //   - SSH: git@bitbucket.org:workspace/repo.git
+//   - SSH: ssh://git@bitbucket.org/workspace/repo.git
 //   - HTTPS: https://bitbucket.org/workspace/repo.git
+//   - HTTPS: https://user@bitbucket.org/workspace/repo.git
 //
-// Returns an error if the URL is not a valid Bitbucket remote URL.
-func ParseBitbucketRemote(url string) (*RemoteInfo, error) {
+// Remotes on bitbucket.org are always recognized; additional hosts (for example
+// a corporate gateway or a local mock) may be passed in hosts, optionally with
+// a port. Returns an error if the URL is not a valid Bitbucket remote URL.
+func ParseBitbucketRemote(url string, hosts ...string) (*RemoteInfo, error) {
 	if url == "" {
 		return nil, fmt.Errorf("remote URL is empty")
 	}
 
-	// SSH format: git@bitbucket.org:workspace/repo.git
-	sshPattern := regexp.MustCompile(`^git@bitbucket\.org:([^/]+)/(.+?)(?:\.git)?$`)
-	if matches := sshPattern.FindStringSubmatch(url); matches != nil {
-		return &RemoteInfo{
-			Workspace: matches[1],
-			Repo:      matches[2],
-		}, nil
-	}
-
-	// HTTPS format: https://bitbucket.org/workspace/repo.git
-	// Also handle http:// for edge cases
-	httpsPattern := regexp.MustCompile(`^https?://bitbucket\.org/([^/]+)/(.+?)(?:\.git)?$`)
-	if matches := httpsPattern.FindStringSubmatch(url); matches != nil {
-		return &RemoteInfo{
-			Workspace: matches[1],
-			Repo:      matches[2],
-		}, nil
+	for _, host := range append([]string{config.DefaultGitHost}, hosts...) {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		h := regexp.QuoteMeta(host)
+
+		// SSH format: git@bitbucket.org:workspace/repo.git
+		sshPattern := regexp.MustCompile(`(?i)^git@` + h + `:([^/]+)/(.+?)(?:\.git)?/?$`)
+		if matches := sshPattern.FindStringSubmatch(url); matches != nil {
+			return &RemoteInfo{
+				Workspace: matches[1],
+				Repo:      matches[2],
+			}, nil
+		}
+
+		// URL formats: https://[user@]bitbucket.org/workspace/repo.git and
+		// ssh://git@bitbucket.org[:port]/workspace/repo.git.
+		// Also handle http:// for edge cases
+		urlPattern := regexp.MustCompile(`(?i)^(?:https?|ssh)://(?:[^@/]+@)?` + h + `(?::\d+)?/([^/]+)/(.+?)(?:\.git)?/?$`)
+		if matches := urlPattern.FindStringSubmatch(url); matches != nil {
+			return &RemoteInfo{
+				Workspace: matches[1],
+				Repo:      matches[2],
+			}, nil
+		}
 	}
 
 	return nil, fmt.Errorf("not a valid Bitbucket remote URL: %s", url)
@@ -89,8 +104,9 @@ func ParseBitbucketRemote(url string) (*RemoteInfo, error) {
 
 // GetBitbucketContext attempts to auto-detect the Bitbucket workspace, repository,
 // and current branch from the git repository in the current directory.
+// Additional recognized git hostnames may be passed in hosts.
 // Returns an error if not in a git repository or if the remote is not a Bitbucket URL.
-func GetBitbucketContext(remoteName string) (workspace, repo, branch string, err error) {
+func GetBitbucketContext(remoteName string, hosts ...string) (workspace, repo, branch string, err error) {
 	branch, err = GetCurrentBranch()
 	if err != nil {
 		return "", "", "", err
@@ -101,7 +117,7 @@ func GetBitbucketContext(remoteName string) (workspace, repo, branch string, err
 		return "", "", "", err
 	}
 
-	info, err := ParseBitbucketRemote(url)
+	info, err := ParseBitbucketRemote(url, hosts...)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -138,6 +138,83 @@ func TestParseBitbucketRemote_InvalidURLs(t *testing.T) {
 	}
 }
 
+func TestParseBitbucketRemote_CustomHosts(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		hosts     []string
+		wantWS    string
+		wantRepo  string
+		wantError bool
+	}{
+		{
+			name:     "HTTPS with username",
+			url:      "https://jdoe@bitbucket.org/myworkspace/myrepo.git",
+			wantWS:   "myworkspace",
+			wantRepo: "myrepo",
+		},
+		{
+			name:     "ssh:// scheme",
+			url:      "ssh://git@bitbucket.org/myworkspace/myrepo.git",
+			wantWS:   "myworkspace",
+			wantRepo: "myrepo",
+		},
+		{
+			name:     "custom SSH host",
+			url:      "git@git.example.com:myworkspace/myrepo.git",
+			hosts:    []string{"git.example.com"},
+			wantWS:   "myworkspace",
+			wantRepo: "myrepo",
+		},
+		{
+			name:     "custom HTTP host with port",
+			url:      "http://localhost:8080/myworkspace/myrepo",
+			hosts:    []string{"localhost:8080"},
+			wantWS:   "myworkspace",
+			wantRepo: "myrepo",
+		},
+		{
+			name:     "custom host without port matches port in URL",
+			url:      "ssh://git@git.example.com:7999/myworkspace/myrepo.git",
+			hosts:    []string{"git.example.com"},
+			wantWS:   "myworkspace",
+			wantRepo: "myrepo",
+		},
+		{
+			name:      "unrecognized host",
+			url:       "git@git.example.com:myworkspace/myrepo.git",
+			wantError: true,
+		},
+		{
+			name:      "host is not treated as a pattern",
+			url:       "git@gitXexample.com:myworkspace/myrepo.git",
+			hosts:     []string{"git.example.com"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseBitbucketRemote(tt.url, tt.hosts...)
+			if tt.wantError {
+				if err == nil {
+					t.Error("ParseBitbucketRemote() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBitbucketRemote() error: %v", err)
+			}
+			if info.Workspace != tt.wantWS {
+				t.Errorf("Workspace = %q, want %q", info.Workspace, tt.wantWS)
+			}
+			if info.Repo != tt.wantRepo {
+				t.Errorf("Repo = %q, want %q", info.Repo, tt.wantRepo)
+			}
+		})
+	}
+}
+
 func TestGetCurrentBranch_InGitRepo(t *testing.T) {
 	// Create a temporary git repository
 	tmpDir := t.TempDir()
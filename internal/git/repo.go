@@ -0,0 +1,346 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Remote is a configured git remote.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// run executes git with args and returns its trimmed standard output. Errors
+// include git's standard error output.
+func run(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Remotes returns the remotes of the repository in the current directory,
+// sorted by name.
+func Remotes() ([]Remote, error) {
+	out, err := run("config", "--get-regexp", `^remote\..*\.url$`)
+	if err != nil {
+		// git config exits non-zero when nothing matches.
+		if _, revErr := run("rev-parse", "--git-dir"); revErr != nil {
+			return nil, fmt.Errorf("not in a git repository: %w", revErr)
+		}
+		return nil, nil
+	}
+
+	var remotes []Remote
+	for _, line := range strings.Split(out, "\n") {
+		key, url, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")
+		remotes = append(remotes, Remote{Name: name, URL: url})
+	}
+	sort.Slice(remotes, func(i, j int) bool { return remotes[i].Name < remotes[j].Name })
+	return remotes, nil
+}
+
+// FindRemote returns the remote whose URL points at the Bitbucket repository
+// fullName (workspace/repo-slug). Additional recognized git hostnames may be
+// passed in hosts. It returns nil when no remote matches.
+func FindRemote(remotes []Remote, fullName string, hosts ...string) *Remote {
+	for i, r := range remotes {
+		info, err := ParseBitbucketRemote(r.URL, hosts...)
+		if err == nil && strings.EqualFold(info.Workspace+"/"+info.Repo, fullName) {
+			return &remotes[i]
+		}
+	}
+	return nil
+}
+
+// ReplaceRepoInURL returns url, a Bitbucket remote URL, pointing at the
+// repository fullName instead, keeping its host, user and protocol.
+func ReplaceRepoInURL(url, fullName string, hosts ...string) (string, error) {
+	info, err := ParseBitbucketRemote(url, hosts...)
+	if err != nil {
+		return "", err
+	}
+	current := info.Workspace + "/" + info.Repo
+	i := strings.LastIndex(url, current)
+	if i < 0 {
+		return "", fmt.Errorf("cannot locate %s in remote URL %s", current, url)
+	}
+	return url[:i] + fullName + url[i+len(current):], nil
+}
+
+// AddRemote adds a remote called name with the given URL.
+func AddRemote(name, url string) error {
+	_, err := run("remote", "add", name, url)
+	return err
+}
+
+// FetchBranch fetches branch from remote into its remote-tracking ref,
+// refs/remotes/<remote>/<branch>.
+func FetchBranch(remote, branch string) error {
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch)
+	_, err := run("fetch", remote, refspec)
+	return err
+}
+
+// BranchExists reports whether the local branch name exists.
+func BranchExists(name string) bool {
+	_, err := run("show-ref", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// RemoteBranchExists reports whether branch currently exists on remote. It
+// asks the remote rather than trusting the remote-tracking ref, which may be
+// stale.
+func RemoteBranchExists(remote, branch string) (bool, error) {
+	out, err := run("ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// CommitsAhead returns the number of commits reachable from head but not
+// from base.
+func CommitsAhead(base, head string) (int, error) {
+	out, err := run("rev-list", "--count", base+".."+head, "--")
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	if _, err := fmt.Sscanf(out, "%d", &n); err != nil {
+		return 0, fmt.Errorf("unexpected rev-list output %q", out)
+	}
+	return n, nil
+}
+
+// Push pushes the local branch to the branch of the same name on remote,
+// setting it as the upstream when setUpstream is true.
+func Push(remote, branch string, setUpstream bool) error {
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	_, err := run(append(args, remote, "refs/heads/"+branch+":refs/heads/"+branch)...)
+	return err
+}
+
+// BranchUpstream returns the remote and remote branch that the local branch
+// name tracks. Both are empty when it has no upstream.
+func BranchUpstream(name string) (remote, branch string) {
+	remote, _ = run("config", "--get", "branch."+name+".remote")
+	merge, _ := run("config", "--get", "branch."+name+".merge")
+	return remote, strings.TrimPrefix(merge, "refs/heads/")
+}
+
+// CheckoutBranch switches to the existing local branch name.
+func CheckoutBranch(name string) error {
+	_, err := run("checkout", name)
+	return err
+}
+
+// CheckoutTrackingBranch creates (or, with force, resets) the local branch
+// name at remote/branch, sets it to track that branch and switches to it.
+func CheckoutTrackingBranch(name, remote, branch string, force bool) error {
+	flag := "-b"
+	if force {
+		flag = "-B"
+	}
+	_, err := run("checkout", flag, name, "--track", remote+"/"+branch)
+	return err
+}
+
+// MergeFastForward fast-forwards the current branch to ref, failing if the
+// branches have diverged.
+func MergeFastForward(ref string) error {
+	_, err := run("merge", "--ff-only", ref)
+	return err
+}
+
+// ResolveCommit returns the full hash of the commit that ref (a branch, tag,
+// HEAD or abbreviated hash) names in the current repository.
+func ResolveCommit(ref string) (string, error) {
+	return run("rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+}
+
+// Commit is a commit hash with its message split into subject and body.
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// Commits returns the commits reachable from head but not from base, oldest
+// first.
+func Commits(base, head string) ([]Commit, error) {
+	out, err := run("log", "--reverse", "--format=%H%x00%s%x00%b%x1e", base+".."+head, "--")
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Subject: fields[1],
+			Body:    strings.TrimSpace(fields[2]),
+		})
+	}
+	return commits, nil
+}
+
+// TopLevel returns the root directory of the current working tree.
+func TopLevel() (string, error) {
+	return run("rev-parse", "--show-toplevel")
+}
+
+// HasUncommittedChanges reports whether tracked files in the working tree or
+// the index differ from HEAD.
+func HasUncommittedChanges() (bool, error) {
+	out, err := run("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// ApplyError is returned by ApplyMailbox when a patch does not apply. The
+// git am session is left in progress so that the conflicts can be resolved
+// and the rest of the series applied with "git am --continue".
+type ApplyError struct {
+	// Patch is the number and subject of the failing patch, such as
+	// "0002 Fix the parser".
+	Patch string
+	// Conflicts lists the files with conflicts or rejected hunks.
+	Conflicts []string
+	// Output is what git am printed.
+	Output string
+}
+
+func (e *ApplyError) Error() string {
+	msg := "patch does not apply"
+	if e.Patch != "" {
+		msg = "patch " + e.Patch + " does not apply"
+	}
+	if len(e.Conflicts) > 0 {
+		msg += ": conflicts in " + strings.Join(e.Conflicts, ", ")
+	}
+	return msg
+}
+
+var (
+	patchFailedAt = regexp.MustCompile(`(?m)^Patch failed at (.+)$`)
+	rejectedFile  = regexp.MustCompile(`(?m)^error: patch failed: (.+):\d+$`)
+	missingFile   = regexp.MustCompile(`(?m)^error: (.+): does not exist in index$`)
+)
+
+// ApplyMailbox applies the patches of mbox, a mailbox such as "git
+// format-patch --stdout" writes, onto the current branch with git am,
+// committing each one. Patches that do not apply cleanly fall back to a
+// three-way merge. It returns the subjects of the commits git am made, read
+// from ORIG_HEAD..HEAD; when a patch fails, the error is an *ApplyError.
+// git runs in the C locale so that its messages can be parsed.
+func ApplyMailbox(mbox string, signoff bool) ([]string, error) {
+	if !strings.HasPrefix(mbox, "From ") {
+		return nil, fmt.Errorf("not a mailbox of patches")
+	}
+	args := []string{"am", "--3way"}
+	if signoff {
+		args = append(args, "--signoff")
+	}
+	var out bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	cmd.Stdin = strings.NewReader(mbox)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if err == nil {
+		return appliedSubjects()
+	}
+
+	if !amInProgress() {
+		// git am refused to start, for example because of local changes.
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return nil, fmt.Errorf("git am: %s", msg)
+		}
+		return nil, fmt.Errorf("git am: %w", err)
+	}
+	applyErr := &ApplyError{
+		Conflicts: conflictedFiles(out.String()),
+		Output:    strings.TrimSpace(out.String()),
+	}
+	if m := patchFailedAt.FindStringSubmatch(out.String()); m != nil {
+		applyErr.Patch = m[1]
+	}
+	// The failed patch is not committed, so only the ones before it count.
+	applied, _ := appliedSubjects()
+	return applied, applyErr
+}
+
+// appliedSubjects returns the subjects of the commits made by git am, which
+// sets ORIG_HEAD to where the branch was when it started, oldest first.
+func appliedSubjects() ([]string, error) {
+	commits, err := Commits("ORIG_HEAD", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	return subjects, nil
+}
+
+// amInProgress reports whether a git am session is in progress.
+func amInProgress() bool {
+	dir, err := run("rev-parse", "--git-path", "rebase-apply")
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(dir)
+	return err == nil
+}
+
+// conflictedFiles returns the unmerged files of the index together with the
+// files git am reported as not applying, without duplicates.
+func conflictedFiles(amOutput string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(f string) {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	if out, err := run("diff", "--name-only", "--diff-filter=U"); err == nil && out != "" {
+		for _, f := range strings.Split(out, "\n") {
+			add(f)
+		}
+	}
+	for _, re := range []*regexp.Regexp{rejectedFile, missingFile} {
+		for _, m := range re.FindAllStringSubmatch(amOutput, -1) {
+			add(m[1])
+		}
+	}
+	return files
+}
@@ -0,0 +1,322 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// gitRun runs git in dir and fails the test on error.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// commitFile writes a file in dir and commits it.
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "commit", "-m", "update "+name)
+}
+
+func TestRemotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	gitRun(t, tmpDir, "init")
+
+	remotes, err := Remotes()
+	if err != nil {
+		t.Fatalf("Remotes() error: %v", err)
+	}
+	if len(remotes) != 0 {
+		t.Errorf("Remotes() = %v, want none", remotes)
+	}
+
+	gitRun(t, tmpDir, "remote", "add", "origin", "git@bitbucket.org:team/app.git")
+	if err := AddRemote("fork", "https://bitbucket.org/alice/app.git"); err != nil {
+		t.Fatalf("AddRemote() error: %v", err)
+	}
+	remotes, err = Remotes()
+	if err != nil {
+		t.Fatalf("Remotes() error: %v", err)
+	}
+	if len(remotes) != 2 || remotes[0].Name != "fork" || remotes[1].Name != "origin" {
+		t.Fatalf("Remotes() = %v, want fork and origin", remotes)
+	}
+
+	if r := FindRemote(remotes, "Team/App"); r == nil || r.Name != "origin" {
+		t.Errorf("FindRemote(team/app) = %v, want origin", r)
+	}
+	if r := FindRemote(remotes, "bob/app"); r != nil {
+		t.Errorf("FindRemote(bob/app) = %v, want nil", r)
+	}
+}
+
+func TestRemotes_NotInGitRepo(t *testing.T) {
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	if _, err := Remotes(); err == nil {
+		t.Error("Remotes() expected error when not in git repo, got nil")
+	}
+}
+
+func TestReplaceRepoInURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@bitbucket.org:team/app.git", "git@bitbucket.org:alice/app-fork.git"},
+		{"https://user@bitbucket.org/team/app.git", "https://user@bitbucket.org/alice/app-fork.git"},
+		{"ssh://git@bitbucket.org:22/team/app", "ssh://git@bitbucket.org:22/alice/app-fork"},
+	}
+	for _, tt := range tests {
+		got, err := ReplaceRepoInURL(tt.url, "alice/app-fork")
+		if err != nil {
+			t.Errorf("ReplaceRepoInURL(%q) error: %v", tt.url, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ReplaceRepoInURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+
+	if _, err := ReplaceRepoInURL("https://github.com/team/app.git", "alice/app"); err == nil {
+		t.Error("ReplaceRepoInURL() expected error for a non-Bitbucket URL")
+	}
+}
+
+func TestCheckoutTrackingBranch(t *testing.T) {
+	upstream := t.TempDir()
+	gitRun(t, upstream, "init")
+	commitFile(t, upstream, "a.txt", "one")
+	gitRun(t, upstream, "checkout", "-b", "feature")
+	commitFile(t, upstream, "b.txt", "two")
+
+	clone := t.TempDir()
+	gitRun(t, clone, "init")
+	commitFile(t, clone, "local.txt", "local")
+	gitRun(t, clone, "remote", "add", "up", upstream)
+	if err := os.Chdir(clone); err != nil {
+		t.Fatalf("failed to change to clone: %v", err)
+	}
+
+	if err := FetchBranch("up", "feature"); err != nil {
+		t.Fatalf("FetchBranch() error: %v", err)
+	}
+	if BranchExists("feature") {
+		t.Fatal("BranchExists(feature) = true before checkout")
+	}
+	if err := CheckoutTrackingBranch("feature", "up", "feature", false); err != nil {
+		t.Fatalf("CheckoutTrackingBranch() error: %v", err)
+	}
+	if branch, _ := GetCurrentBranch(); branch != "feature" {
+		t.Errorf("current branch = %q, want feature", branch)
+	}
+	if r, b := BranchUpstream("feature"); r != "up" || b != "feature" {
+		t.Errorf("BranchUpstream() = %q, %q, want up, feature", r, b)
+	}
+
+	// New upstream commits fast-forward the existing branch.
+	commitFile(t, upstream, "c.txt", "three")
+	if err := FetchBranch("up", "feature"); err != nil {
+		t.Fatalf("FetchBranch() error: %v", err)
+	}
+	if err := MergeFastForward("up/feature"); err != nil {
+		t.Fatalf("MergeFastForward() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "c.txt")); err != nil {
+		t.Errorf("c.txt missing after fast-forward: %v", err)
+	}
+
+	// A diverged branch refuses to fast-forward.
+	commitFile(t, clone, "d.txt", "local change")
+	commitFile(t, upstream, "e.txt", "upstream change")
+	if err := FetchBranch("up", "feature"); err != nil {
+		t.Fatalf("FetchBranch() error: %v", err)
+	}
+	if err := MergeFastForward("up/feature"); err == nil {
+		t.Error("MergeFastForward() expected error for diverged branches")
+	}
+
+	if err := FetchBranch("up", "missing"); err == nil {
+		t.Error("FetchBranch() expected error for a missing branch")
+	}
+}
+
+func TestCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRun(t, tmpDir, "init")
+	commitFile(t, tmpDir, "a.txt", "one")
+	gitRun(t, tmpDir, "branch", "base")
+	commitFile(t, tmpDir, "b.txt", "two")
+	if err := os.WriteFile(filepath.Join(tmpDir, "c.txt"), []byte("three"), 0644); err != nil {
+		t.Fatalf("failed to write c.txt: %v", err)
+	}
+	gitRun(t, tmpDir, "add", "c.txt")
+	gitRun(t, tmpDir, "commit", "-m", "Add c", "-m", "Explains why.")
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+
+	commits, err := Commits("base", "HEAD")
+	if err != nil {
+		t.Fatalf("Commits() error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Commits() returned %d commits, want 2", len(commits))
+	}
+	if commits[0].Subject != "update b.txt" || commits[0].Body != "" {
+		t.Errorf("commits[0] = %+v, want subject %q and no body", commits[0], "update b.txt")
+	}
+	if commits[1].Subject != "Add c" || commits[1].Body != "Explains why." {
+		t.Errorf("commits[1] = %+v, want subject %q and body %q", commits[1], "Add c", "Explains why.")
+	}
+	if len(commits[1].Hash) != 40 {
+		t.Errorf("commits[1].Hash = %q, want a full hash", commits[1].Hash)
+	}
+
+	if commits, err := Commits("HEAD", "HEAD"); err != nil || len(commits) != 0 {
+		t.Errorf("Commits(HEAD, HEAD) = %v, %v, want none", commits, err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	upstream := t.TempDir()
+	gitRun(t, upstream, "init", "--bare")
+
+	clone := t.TempDir()
+	gitRun(t, clone, "init")
+	gitRun(t, clone, "checkout", "-b", "feature")
+	commitFile(t, clone, "a.txt", "one")
+	gitRun(t, clone, "remote", "add", "up", upstream)
+	if err := os.Chdir(clone); err != nil {
+		t.Fatalf("failed to change to clone: %v", err)
+	}
+
+	if ok, err := RemoteBranchExists("up", "feature"); ok || err != nil {
+		t.Fatalf("RemoteBranchExists() before pushing = %v, %v, want false", ok, err)
+	}
+	if err := Push("up", "feature", true); err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+	if ok, err := RemoteBranchExists("up", "feature"); !ok || err != nil {
+		t.Errorf("RemoteBranchExists() after pushing = %v, %v, want true", ok, err)
+	}
+	if remote, branch := BranchUpstream("feature"); remote != "up" || branch != "feature" {
+		t.Errorf("BranchUpstream() = %q, %q, want up, feature", remote, branch)
+	}
+
+	commitFile(t, clone, "b.txt", "two")
+	if n, err := CommitsAhead("up/feature", "feature"); err != nil || n != 1 {
+		t.Errorf("CommitsAhead() = %d, %v, want 1", n, err)
+	}
+	if err := Push("up", "feature", false); err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+	if n, err := CommitsAhead("up/feature", "feature"); err != nil || n != 0 {
+		t.Errorf("CommitsAhead() after push = %d, %v, want 0", n, err)
+	}
+
+	// Deleted on the remote: the stale remote-tracking ref must not count.
+	gitRun(t, upstream, "branch", "-D", "feature")
+	if ok, err := RemoteBranchExists("up", "feature"); ok || err != nil {
+		t.Errorf("RemoteBranchExists() after remote deletion = %v, %v, want false", ok, err)
+	}
+	if _, err := RemoteBranchExists("missing", "feature"); err == nil {
+		t.Error("RemoteBranchExists() on an unknown remote should fail")
+	}
+}
+
+func TestApplyMailbox(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRun(t, tmpDir, "init")
+	// git am commits as the configured user.
+	gitRun(t, tmpDir, "config", "user.name", "Test User")
+	gitRun(t, tmpDir, "config", "user.email", "test@example.com")
+	commitFile(t, tmpDir, "a.txt", "one\ntwo\nthree\n")
+	gitRun(t, tmpDir, "branch", "base")
+	gitRun(t, tmpDir, "checkout", "-b", "feature")
+	commitFile(t, tmpDir, "a.txt", "one\nTWO\nthree\n")
+	commitFile(t, tmpDir, "b.txt", "new")
+
+	cmd := exec.Command("git", "format-patch", "--stdout", "base..feature")
+	cmd.Dir = tmpDir
+	mbox, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git format-patch: %v", err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+
+	gitRun(t, tmpDir, "checkout", "-b", "clean", "base")
+	applied, err := ApplyMailbox(string(mbox), false)
+	if err != nil {
+		t.Fatalf("ApplyMailbox() error: %v", err)
+	}
+	if len(applied) != 2 || applied[0] != "update a.txt" || applied[1] != "update b.txt" {
+		t.Errorf("ApplyMailbox() applied %q, want both patches", applied)
+	}
+	if n, err := CommitsAhead("base", "HEAD"); err != nil || n != 2 {
+		t.Errorf("CommitsAhead() = %d, %v, want 2", n, err)
+	}
+	if dirty, err := HasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("HasUncommittedChanges() = %v, %v, want false", dirty, err)
+	}
+
+	// The first patch conflicts with a change on this branch.
+	gitRun(t, tmpDir, "checkout", "-b", "conflict", "base")
+	commitFile(t, tmpDir, "a.txt", "one\n2\nthree\n")
+	applied, err = ApplyMailbox(string(mbox), false)
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("ApplyMailbox() error = %v, want *ApplyError", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("ApplyMailbox() applied %q, want none", applied)
+	}
+	if applyErr.Patch != "0001 update a.txt" {
+		t.Errorf("ApplyError.Patch = %q, want %q", applyErr.Patch, "0001 update a.txt")
+	}
+	if len(applyErr.Conflicts) != 1 || applyErr.Conflicts[0] != "a.txt" {
+		t.Errorf("ApplyError.Conflicts = %q, want a.txt", applyErr.Conflicts)
+	}
+	if !amInProgress() {
+		t.Error("the git am session should be left in progress")
+	}
+	gitRun(t, tmpDir, "am", "--abort")
+
+	// The second patch conflicts, after the first was committed.
+	gitRun(t, tmpDir, "checkout", "-b", "partial", "base")
+	commitFile(t, tmpDir, "b.txt", "other")
+	applied, err = ApplyMailbox(string(mbox), false)
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("ApplyMailbox() error = %v, want *ApplyError", err)
+	}
+	if len(applied) != 1 || applied[0] != "update a.txt" {
+		t.Errorf("ApplyMailbox() applied %q, want the first patch", applied)
+	}
+	if applyErr.Patch != "0002 update b.txt" {
+		t.Errorf("ApplyError.Patch = %q, want %q", applyErr.Patch, "0002 update b.txt")
+	}
+	gitRun(t, tmpDir, "am", "--abort")
+
+	if _, err := ApplyMailbox("not a patch", false); err == nil || errors.As(err, &applyErr) {
+		t.Errorf("ApplyMailbox() error = %v, want a plain error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/comments"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+// defaultCommentContext is the number of diff lines shown either side of an
+// inline comment.
+const defaultCommentContext = 3
+
+// PRCommentsListHandler handles the pr_comments tool invocation.
+func PRCommentsListHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, _, err := prItemArgs(args, "")
+	if err != nil {
+		return nil, err
+	}
+	unresolved, _ := args["unresolved"].(bool)
+	contextLines := defaultCommentContext
+	if n, ok := args["context_lines"].(float64); ok && n >= 0 {
+		contextLines = int(n)
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments?pagelen=100", repository, prID)
+	all, err := api.GetAll[comments.Comment](ctx, client, path, maxToolItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request comments: %w", err)
+	}
+
+	threads := comments.Threads(all)
+	if unresolved {
+		threads = comments.Unresolved(threads)
+	}
+	if len(threads) == 0 {
+		if unresolved {
+			return []Content{NewTextContent("No unresolved comments on this pull request.")}, nil
+		}
+		return []Content{NewTextContent("No comments on this pull request.")}, nil
+	}
+
+	var files []diff.File
+	if contextLines > 0 && comments.HasInline(threads) {
+		diffPath := fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", repository, prID)
+		if data, err := client.GetContext(ctx, diffPath); err == nil {
+			files = diff.Parse(string(data))
+		}
+	}
+
+	// Format threads for readability
+	var sb strings.Builder
+	for _, t := range threads {
+		writeCommentThread(&sb, t, files, contextLines)
+		sb.WriteString("---\n\n")
+	}
+	return []Content{NewTextContent(sb.String())}, nil
+}
+
+// writeCommentThread writes a thread as markdown, with the diff around the
+// line an inline thread is on.
+func writeCommentThread(sb *strings.Builder, t *comments.Thread, files []diff.File, contextLines int) {
+	t.Walk(func(c *comments.Comment, depth int) {
+		if depth == 0 {
+			fmt.Fprintf(sb, "### Comment #%d by %s", c.ID, c.Author())
+			if c.Inline != nil {
+				fmt.Fprintf(sb, " on `%s`", c.Inline.Path)
+				switch {
+				case c.Inline.To != nil:
+					fmt.Fprintf(sb, " (line %d)", *c.Inline.To)
+				case c.Inline.From != nil:
+					fmt.Fprintf(sb, " (old line %d)", *c.Inline.From)
+				}
+				if c.Inline.Outdated {
+					sb.WriteString(" [outdated]")
+				}
+			}
+		} else {
+			fmt.Fprintf(sb, "#### Reply #%d by %s to #%d", c.ID, c.Author(), c.Parent.ID)
+		}
+		if c.Resolution != nil {
+			fmt.Fprintf(sb, " [resolved by %s]", c.Resolution.User.DisplayName)
+		}
+		sb.WriteString("\n")
+		fmt.Fprintf(sb, "*%s*\n\n", c.CreatedOn)
+
+		if depth == 0 && contextLines > 0 {
+			if lines, _, ok := c.DiffContext(files, contextLines); ok {
+				sb.WriteString("```diff\n")
+				for _, l := range lines {
+					fmt.Fprintf(sb, "%c%s\n", l.Kind, l.Text)
+				}
+				sb.WriteString("```\n\n")
+			}
+		}
+
+		if c.Deleted {
+			sb.WriteString("(deleted)")
+		} else {
+			sb.WriteString(c.Content.Raw)
+		}
+		sb.WriteString("\n\n")
+	})
+}
+
+// PRCommentEditHandler handles the pr_comment_edit tool invocation.
+func PRCommentEditHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, commentID, err := prItemArgs(args, "comment_id")
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := args["content"].(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content parameter is required")
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"content": map[string]string{"raw": content},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s", repository, prID, commentID)
+	data, err := client.PutContext(ctx, path, string(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit comment: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
+// PRCommentDeleteHandler handles the pr_comment_delete tool invocation.
+func PRCommentDeleteHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, commentID, err := prItemArgs(args, "comment_id")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s", repository, prID, commentID)
+	if _, err := client.DeleteContext(ctx, path); err != nil {
+		return nil, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return []Content{NewTextContent(fmt.Sprintf("Comment #%s deleted from PR #%s", commentID, prID))}, nil
+}
+
+// PRCommentResolveHandler handles the pr_comment_resolve tool invocation.
+func PRCommentResolveHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, commentID, err := prItemArgs(args, "comment_id")
+	if err != nil {
+		return nil, err
+	}
+	unresolve, _ := args["unresolve"].(bool)
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%s/resolve", repository, prID, commentID)
+	if unresolve {
+		if _, err := client.DeleteContext(ctx, path); err != nil {
+			return nil, fmt.Errorf("failed to reopen comment thread: %w", err)
+		}
+		return []Content{NewTextContent(fmt.Sprintf("Comment thread #%s on PR #%s reopened", commentID, prID))}, nil
+	}
+
+	data, err := client.PostContext(ctx, path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve comment thread: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
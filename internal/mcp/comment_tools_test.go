@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/comments"
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+func TestWriteCommentThread(t *testing.T) {
+	var all []comments.Comment
+	if err := json.Unmarshal([]byte(`[
+		{"id": 3, "parent": {"id": 1}, "content": {"raw": "thanks"}, "user": {"nickname": "ann"}},
+		{"id": 1, "content": {"raw": "rename this"}, "user": {"display_name": "Bob"},
+		 "inline": {"path": "main.go", "to": 2}, "resolution": {"user": {"display_name": "Ann"}}}
+	]`), &all); err != nil {
+		t.Fatal(err)
+	}
+	files := diff.Parse(`diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+ package main
+-var x = 1
++var x = 2
+`)
+
+	var sb strings.Builder
+	writeCommentThread(&sb, comments.Threads(all)[0], files, 3)
+	got := sb.String()
+	for _, want := range []string{
+		"### Comment #1 by Bob on `main.go` (line 2) [resolved by Ann]",
+		"```diff\n package main\n-var x = 1\n+var x = 2\n```",
+		"#### Reply #3 by ann to #1",
+		"thanks",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("thread output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // Download represents a Bitbucket repository download.
@@ -53,7 +51,7 @@ func DownloadListHandler(ctx context.Context, args map[string]interface{}) ([]Co
 
 	path := fmt.Sprintf("/repositories/%s/%s/downloads?pagelen=25",
 		url.PathEscape(ws), url.PathEscape(repo))
-	downloads, err := api.GetPaginated[Download](client, path)
+	downloads, err := fetchPaginated[Download](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list downloads: %w", err)
 	}
@@ -93,7 +91,7 @@ func DownloadDeleteHandler(ctx context.Context, args map[string]interface{}) ([]
 
 	path := fmt.Sprintf("/repositories/%s/%s/downloads/%s",
 		url.PathEscape(ws), url.PathEscape(repo), url.PathEscape(filename))
-	_, err = client.Delete(path)
+	_, err = client.DeleteContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete download: %w", err)
 	}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // Environment represents a Bitbucket deployment environment.
@@ -44,7 +42,7 @@ func EnvironmentListHandler(ctx context.Context, args map[string]interface{}) ([
 	}
 
 	path := fmt.Sprintf("/repositories/%s/environments?pagelen=25", repository)
-	envs, err := api.GetPaginated[Environment](client, path)
+	envs, err := fetchPaginated[Environment](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list environments: %w", err)
 	}
@@ -78,7 +76,7 @@ func EnvironmentViewHandler(ctx context.Context, args map[string]interface{}) ([
 	}
 
 	path := fmt.Sprintf("/repositories/%s/environments/%s", repository, envUUID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch environment: %w", err)
 	}
@@ -134,7 +132,7 @@ func EnvironmentCreateHandler(ctx context.Context, args map[string]interface{})
 	}
 
 	path := fmt.Sprintf("/repositories/%s/environments", repository)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create environment: %w", err)
 	}
@@ -173,7 +171,7 @@ func EnvironmentDeleteHandler(ctx context.Context, args map[string]interface{})
 	}
 
 	path := fmt.Sprintf("/repositories/%s/environments/%s", repository, envUUID)
-	_, err = client.Delete(path)
+	_, err = client.DeleteContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete environment: %w", err)
 	}
@@ -0,0 +1,366 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// withArgs returns a copy of base with the given key/value pairs applied; a
+// nil value removes the key.
+func withArgs(base map[string]interface{}, kv ...interface{}) map[string]interface{} {
+	args := make(map[string]interface{}, len(base)+len(kv)/2)
+	for k, v := range base {
+		args[k] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		key := kv[i].(string)
+		if kv[i+1] == nil {
+			delete(args, key)
+		} else {
+			args[key] = kv[i+1]
+		}
+	}
+	return args
+}
+
+func TestTools_Definitions(t *testing.T) {
+	registry := NewToolRegistry()
+	if err := RegisterDefaultTools(registry); err != nil {
+		t.Fatalf("RegisterDefaultTools failed: %v", err)
+	}
+	for _, tool := range registry.List() {
+		t.Run(tool.Name, func(t *testing.T) {
+			if tool.Description == "" {
+				t.Error("expected non-empty description")
+			}
+			if tool.InputSchema["type"] != "object" {
+				t.Fatalf("input schema type = %v, want object", tool.InputSchema["type"])
+			}
+			props, _ := tool.InputSchema["properties"].(map[string]interface{})
+			required, _ := tool.InputSchema["required"].([]string)
+			for _, name := range required {
+				if _, ok := props[name]; !ok {
+					t.Errorf("required parameter %q is not a property", name)
+				}
+			}
+		})
+	}
+}
+
+func TestTools_DraftOnlyOnEdit(t *testing.T) {
+	registry := NewToolRegistry()
+	if err := RegisterDefaultTools(registry); err != nil {
+		t.Fatalf("RegisterDefaultTools failed: %v", err)
+	}
+	for name, want := range map[string]bool{"pr_edit": true, "pr_merge": false} {
+		props, _ := registry.Get(name).Tool.InputSchema["properties"].(map[string]interface{})
+		if _, ok := props["draft"]; ok != want {
+			t.Errorf("%s declares draft = %v, want %v", name, ok, want)
+		}
+	}
+}
+
+func TestTools_HandlerValidation(t *testing.T) {
+	pr := map[string]interface{}{"repository": "ws/repo", "pr_id": "42"}
+	status := map[string]interface{}{
+		"repository": "ws/repo",
+		"commit":     "1a2b3c4d5e6f",
+		"state":      "SUCCESSFUL",
+		"key":        "ci",
+		"url":        "https://ci.example.com/1",
+	}
+
+	tests := []struct {
+		name    string
+		handler ToolHandler
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"status_list missing repository", StatusListHandler, map[string]interface{}{}, "repository"},
+		{"status_list missing commit", StatusListHandler, map[string]interface{}{"repository": "ws/repo"}, "commit"},
+		{"status_list invalid commit", StatusListHandler, map[string]interface{}{"repository": "ws/repo", "commit": "../x"}, "invalid commit"},
+		{"status_set missing state", StatusSetHandler, withArgs(status, "state", nil), "state"},
+		{"status_set invalid state", StatusSetHandler, withArgs(status, "state", "PASSED"), "invalid state"},
+		{"status_set missing key", StatusSetHandler, withArgs(status, "key", nil), "key"},
+		{"status_set missing url", StatusSetHandler, withArgs(status, "url", nil), "url"},
+		{"status_set invalid repository", StatusSetHandler, withArgs(status, "repository", "nope"), "invalid repository"},
+
+		{"pr_tasks missing repository", PRTasksHandler, map[string]interface{}{}, "repository"},
+		{"pr_tasks missing pr_id", PRTasksHandler, map[string]interface{}{"repository": "ws/repo"}, "pr_id"},
+		{"pr_tasks invalid repository", PRTasksHandler, withArgs(pr, "repository", "nope"), "invalid repository"},
+		{"pr_tasks invalid state", PRTasksHandler, withArgs(pr, "state", "done"), "invalid state"},
+		{"pr_task_create missing content", PRTaskCreateHandler, pr, "content"},
+		{"pr_task_create invalid comment_id", PRTaskCreateHandler, withArgs(pr, "content", "Fix it", "comment_id", "abc"), "invalid comment_id"},
+		{"pr_task_resolve missing task_id", PRTaskResolveHandler, pr, "task_id"},
+		{"pr_task_resolve invalid task_id", PRTaskResolveHandler, withArgs(pr, "task_id", "../1"), "invalid task_id"},
+		{"pr_task_delete missing task_id", PRTaskDeleteHandler, pr, "task_id"},
+
+		{"pr_comments missing pr_id", PRCommentsListHandler, map[string]interface{}{"repository": "ws/repo"}, "pr_id"},
+		{"pr_comment invalid parent_id", PRCommentHandler, withArgs(pr, "content", "Done", "parent_id", "x"), "invalid parent_id"},
+		{"pr_comment_edit missing comment_id", PRCommentEditHandler, withArgs(pr, "content", "New"), "comment_id"},
+		{"pr_comment_edit missing content", PRCommentEditHandler, withArgs(pr, "comment_id", "7"), "content"},
+		{"pr_comment_delete invalid comment_id", PRCommentDeleteHandler, withArgs(pr, "comment_id", "7/../8"), "invalid comment_id"},
+		{"pr_comment_resolve invalid repository", PRCommentResolveHandler, withArgs(pr, "repository", "nope", "comment_id", "7"), "invalid repository"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.handler(context.Background(), tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// apiRequest is a request received by the test API server.
+type apiRequest struct {
+	Method string
+	Path   string
+	Filter string // the BBQL q parameter
+	Body   string
+}
+
+// newTestAPI starts an API server that records every request and answers
+// with an empty object, or an empty page for GET requests, and returns a
+// context whose client talks to it.
+func newTestAPI(t *testing.T) (context.Context, *[]apiRequest) {
+	t.Helper()
+	var requests []apiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, apiRequest{r.Method, r.URL.Path, r.URL.Query().Get("q"), string(body)})
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"values":[]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("BB_API_URL", server.URL)
+	return ContextWithToken(context.Background(), "token"), &requests
+}
+
+func TestTools_HandlerRequests(t *testing.T) {
+	pr := map[string]interface{}{"repository": "ws/repo", "pr_id": "42"}
+
+	tests := []struct {
+		name     string
+		handler  ToolHandler
+		args     map[string]interface{}
+		method   string
+		path     string
+		filter   string
+		wantBody string
+	}{
+		{
+			name:    "status_list",
+			handler: StatusListHandler,
+			args:    map[string]interface{}{"repository": "ws/repo", "commit": "1a2b3c4d"},
+			method:  "GET",
+			path:    "/repositories/ws/repo/commit/1a2b3c4d/statuses",
+		},
+		{
+			name:    "status_set",
+			handler: StatusSetHandler,
+			args: map[string]interface{}{
+				"repository": "ws/repo", "commit": "1a2b3c4d", "state": "failed",
+				"key": "ci", "url": "https://ci.example.com/1", "name": "Unit tests",
+			},
+			method:   "POST",
+			path:     "/repositories/ws/repo/commit/1a2b3c4d/statuses/build",
+			wantBody: `{"key":"ci","name":"Unit tests","state":"FAILED","url":"https://ci.example.com/1"}`,
+		},
+		{
+			name:    "pr_tasks",
+			handler: PRTasksHandler,
+			args:    withArgs(pr, "state", "open"),
+			method:  "GET",
+			path:    "/repositories/ws/repo/pullrequests/42/tasks",
+			filter:  `state="UNRESOLVED"`,
+		},
+		{
+			name:     "pr_task_create",
+			handler:  PRTaskCreateHandler,
+			args:     withArgs(pr, "content", "Add a test", "comment_id", "7"),
+			method:   "POST",
+			path:     "/repositories/ws/repo/pullrequests/42/tasks",
+			wantBody: `{"comment":{"id":7},"content":{"raw":"Add a test"}}`,
+		},
+		{
+			name:     "pr_task_resolve",
+			handler:  PRTaskResolveHandler,
+			args:     withArgs(pr, "task_id", "3"),
+			method:   "PUT",
+			path:     "/repositories/ws/repo/pullrequests/42/tasks/3",
+			wantBody: `{"state":"RESOLVED"}`,
+		},
+		{
+			name:     "pr_task_resolve reopen",
+			handler:  PRTaskResolveHandler,
+			args:     withArgs(pr, "task_id", "3", "reopen", true),
+			method:   "PUT",
+			path:     "/repositories/ws/repo/pullrequests/42/tasks/3",
+			wantBody: `{"state":"UNRESOLVED"}`,
+		},
+		{
+			name:    "pr_task_delete",
+			handler: PRTaskDeleteHandler,
+			args:    withArgs(pr, "task_id", "3"),
+			method:  "DELETE",
+			path:    "/repositories/ws/repo/pullrequests/42/tasks/3",
+		},
+		{
+			name:     "pr_comment reply",
+			handler:  PRCommentHandler,
+			args:     withArgs(pr, "content", "Done", "parent_id", "7"),
+			method:   "POST",
+			path:     "/repositories/ws/repo/pullrequests/42/comments",
+			wantBody: `{"content":{"raw":"Done"},"parent":{"id":7}}`,
+		},
+		{
+			name:     "pr_comment_edit",
+			handler:  PRCommentEditHandler,
+			args:     withArgs(pr, "comment_id", "7", "content", "Updated"),
+			method:   "PUT",
+			path:     "/repositories/ws/repo/pullrequests/42/comments/7",
+			wantBody: `{"content":{"raw":"Updated"}}`,
+		},
+		{
+			name:    "pr_comment_delete",
+			handler: PRCommentDeleteHandler,
+			args:    withArgs(pr, "comment_id", "7"),
+			method:  "DELETE",
+			path:    "/repositories/ws/repo/pullrequests/42/comments/7",
+		},
+		{
+			name:    "pr_comment_resolve",
+			handler: PRCommentResolveHandler,
+			args:    withArgs(pr, "comment_id", "7"),
+			method:  "POST",
+			path:    "/repositories/ws/repo/pullrequests/42/comments/7/resolve",
+		},
+		{
+			name:    "pr_comment_resolve unresolve",
+			handler: PRCommentResolveHandler,
+			args:    withArgs(pr, "comment_id", "7", "unresolve", true),
+			method:  "DELETE",
+			path:    "/repositories/ws/repo/pullrequests/42/comments/7/resolve",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, requests := newTestAPI(t)
+			if _, err := tt.handler(ctx, tt.args); err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("got %d requests, want 1: %+v", len(*requests), *requests)
+			}
+			req := (*requests)[0]
+			if req.Method != tt.method || req.Path != tt.path {
+				t.Errorf("request = %s %s, want %s %s", req.Method, req.Path, tt.method, tt.path)
+			}
+			if req.Filter != tt.filter {
+				t.Errorf("q = %q, want %q", req.Filter, tt.filter)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			var got, want interface{}
+			if err := json.Unmarshal([]byte(req.Body), &got); err != nil {
+				t.Fatalf("request body %q is not JSON: %v", req.Body, err)
+			}
+			if err := json.Unmarshal([]byte(tt.wantBody), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("request body = %s, want %s", req.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// listTools are list tools with the arguments they need.
+var listTools = []struct {
+	name    string
+	handler ToolHandler
+	args    map[string]interface{}
+}{
+	{"download_list", DownloadListHandler, map[string]interface{}{"repository": "ws/repo"}},
+	{"environment_list", EnvironmentListHandler, map[string]interface{}{"repository": "ws/repo"}},
+	{"variable_list", VariableListHandler, map[string]interface{}{"repository": "ws/repo"}},
+	{"snippet_list", SnippetListHandler, map[string]interface{}{"workspace": "ws"}},
+	{"workspace_list", WorkspaceListHandler, nil},
+	{"workspace_members", WorkspaceMembersHandler, map[string]interface{}{"workspace": "ws"}},
+	{"workspace_projects", WorkspaceProjectsHandler, map[string]interface{}{"workspace": "ws"}},
+	{"user_emails", UserEmailsHandler, nil},
+	{"user_ssh_keys", UserSSHKeysHandler, nil},
+}
+
+func TestTools_ListHandlersStopWhenCancelled(t *testing.T) {
+	for _, tt := range listTools {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, requests := newTestAPI(t)
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err := tt.handler(ctx, tt.args); err == nil {
+				t.Error("expected an error for a cancelled request")
+			}
+			if len(*requests) != 0 {
+				t.Errorf("made %d API requests after the request was cancelled", len(*requests))
+			}
+		})
+	}
+}
+
+func TestTools_ListHandlersFollowPages(t *testing.T) {
+	registry := NewToolRegistry()
+	if err := RegisterDefaultTools(registry); err != nil {
+		t.Fatalf("RegisterDefaultTools failed: %v", err)
+	}
+	for _, tt := range listTools {
+		t.Run(tt.name, func(t *testing.T) {
+			props, _ := registry.Get(tt.name).Tool.InputSchema["properties"].(map[string]interface{})
+			for _, key := range []string{"all", "limit"} {
+				if _, ok := props[key]; !ok {
+					t.Errorf("input schema does not declare %q", key)
+				}
+			}
+
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pages++
+				if r.URL.Query().Get("page") == "next" {
+					w.Write([]byte(`{"values":[{}]}`))
+					return
+				}
+				fmt.Fprintf(w, `{"values":[{}],"next":"http://%s%s?page=next"}`, r.Host, r.URL.Path)
+			}))
+			defer server.Close()
+			t.Setenv("BB_API_URL", server.URL)
+			ctx := ContextWithToken(context.Background(), "token")
+
+			content, err := tt.handler(ctx, withArgs(tt.args, "all", true))
+			if err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal([]byte(content[0].Text), &items); err != nil {
+				t.Fatalf("result is not a JSON array: %v", err)
+			}
+			if pages != 2 || len(items) != 2 {
+				t.Errorf("fetched %d pages and returned %d items, want 2 and 2", pages, len(items))
+			}
+		})
+	}
+}
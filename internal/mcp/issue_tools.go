@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // IssueListHandler handles the issue_list tool invocation.
@@ -40,7 +38,7 @@ func IssueListHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 	}
 
 	// Fetch issues
-	issues, err := api.GetPaginated[Issue](client, path)
+	issues, err := fetchPaginated[Issue](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch issues: %w", err)
 	}
@@ -106,7 +104,7 @@ func IssueCreateHandler(ctx context.Context, args map[string]interface{}) ([]Con
 
 	// Create issue
 	path := fmt.Sprintf("/repositories/%s/issues", repository)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create issue: %w", err)
 	}
@@ -149,7 +147,7 @@ func IssueViewHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 
 	// Fetch issue
 	path := fmt.Sprintf("/repositories/%s/issues/%s", repository, issueID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch issue: %w", err)
 	}
@@ -221,7 +219,7 @@ func IssueEditHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 
 	// Update issue
 	path := fmt.Sprintf("/repositories/%s/issues/%s", repository, issueID)
-	data, err := client.Put(path, string(jsonBody))
+	data, err := client.PutContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update issue: %w", err)
 	}
@@ -264,7 +262,7 @@ func IssueDeleteHandler(ctx context.Context, args map[string]interface{}) ([]Con
 
 	// Delete issue
 	path := fmt.Sprintf("/repositories/%s/issues/%s", repository, issueID)
-	_, err = client.Delete(path)
+	_, err = client.DeleteContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete issue: %w", err)
 	}
@@ -322,7 +320,7 @@ func IssueCommentHandler(ctx context.Context, args map[string]interface{}) ([]Co
 
 	// Post comment
 	path := fmt.Sprintf("/repositories/%s/issues/%s/comments", repository, issueID)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add comment: %w", err)
 	}
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/api"
+)
+
+// maxToolItems caps the number of items a list tool returns when "all" is
+// requested without an explicit limit, keeping tool results within a size
+// an MCP client can reasonably consume.
+const maxToolItems = 1000
+
+// fetchPaginated fetches a paginated collection honouring the optional "all"
+// and "limit" tool arguments. Without either, only the page at path is fetched.
+func fetchPaginated[T any](ctx context.Context, client *api.Client, path string, args map[string]interface{}) ([]T, error) {
+	all, _ := args["all"].(bool)
+	limit := 0
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	if !all && limit == 0 {
+		return api.GetPaginatedContext[T](ctx, client, path)
+	}
+	if limit == 0 || limit > maxToolItems {
+		limit = maxToolItems
+	}
+	return api.GetAll[T](ctx, client, path, limit)
+}
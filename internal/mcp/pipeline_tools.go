@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // PipelineListHandler handles the pipeline_list tool invocation.
@@ -35,7 +33,7 @@ func PipelineListHandler(ctx context.Context, args map[string]interface{}) ([]Co
 	path := fmt.Sprintf("/repositories/%s/pipelines/?pagelen=20&page=%d&sort=-created_on", repository, page)
 
 	// Fetch pipelines
-	pipelines, err := api.GetPaginated[Pipeline](client, path)
+	pipelines, err := fetchPaginated[Pipeline](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pipelines: %w", err)
 	}
@@ -101,7 +99,7 @@ func PipelineTriggerHandler(ctx context.Context, args map[string]interface{}) ([
 
 	// Trigger pipeline
 	path := fmt.Sprintf("/repositories/%s/pipelines/", repository)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to trigger pipeline: %w", err)
 	}
@@ -144,7 +142,7 @@ func PipelineViewHandler(ctx context.Context, args map[string]interface{}) ([]Co
 
 	// Fetch pipeline
 	path := fmt.Sprintf("/repositories/%s/pipelines/%s", repository, pipelineUUID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pipeline: %w", err)
 	}
@@ -187,7 +185,7 @@ func PipelineStopHandler(ctx context.Context, args map[string]interface{}) ([]Co
 
 	// Stop pipeline
 	path := fmt.Sprintf("/repositories/%s/pipelines/%s/stopPipeline", repository, pipelineUUID)
-	_, err = client.Post(path, "")
+	_, err = client.PostContext(ctx, path, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to stop pipeline: %w", err)
 	}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/PhilipKram/bitbucket-cli/internal/api"
+	"github.com/PhilipKram/bitbucket-cli/internal/cmdutil"
 )
 
 // PRListHandler handles the pr_list tool invocation.
@@ -41,7 +43,7 @@ func PRListHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 	}
 
 	// Fetch pull requests
-	prs, err := api.GetPaginated[PullRequest](client, path)
+	prs, err := fetchPaginated[PullRequest](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
 	}
@@ -79,7 +81,7 @@ func PRViewHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 
 	// Fetch pull request
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s", repository, prID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pull request: %w", err)
 	}
@@ -147,6 +149,16 @@ func PRCreateHandler(ctx context.Context, args map[string]interface{}) ([]Conten
 			"branch": map[string]string{"name": destination},
 		}
 	}
+	if draft, ok := args["draft"].(bool); ok && draft {
+		body["draft"] = true
+	}
+	if refs, ok := args["reviewers"].(string); ok && refs != "" {
+		reviewers, err := resolveReviewers(ctx, client, repository, refs)
+		if err != nil {
+			return nil, err
+		}
+		body["reviewers"] = reviewers
+	}
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -155,7 +167,7 @@ func PRCreateHandler(ctx context.Context, args map[string]interface{}) ([]Conten
 
 	// Create pull request
 	path := fmt.Sprintf("/repositories/%s/pullrequests", repository)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pull request: %w", err)
 	}
@@ -198,7 +210,7 @@ func PRApproveHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 
 	// Approve pull request
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/approve", repository, prID)
-	data, err := client.Post(path, "")
+	data, err := client.PostContext(ctx, path, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to approve pull request: %w", err)
 	}
@@ -255,7 +267,7 @@ func PRMergeHandler(ctx context.Context, args map[string]interface{}) ([]Content
 
 	// Merge pull request
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/merge", repository, prID)
-	data, err := client.Post(path, jsonBody)
+	data, err := client.PostContext(ctx, path, jsonBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge pull request: %w", err)
 	}
@@ -297,7 +309,7 @@ func PRDeclineHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 
 	// Decline pull request
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/decline", repository, prID)
-	data, err := client.Post(path, "")
+	data, err := client.PostContext(ctx, path, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to decline pull request: %w", err)
 	}
@@ -339,7 +351,7 @@ func PRDiffHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 
 	// Fetch pull request diff (returns plain text)
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", repository, prID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pull request diff: %w", err)
 	}
@@ -368,18 +380,25 @@ func PRCommentHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 		return nil, fmt.Errorf("content parameter is required")
 	}
 
-	// Create API client
-	client, err := GetClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create API client: %w", err)
-	}
-
 	// Build request body
 	body := map[string]interface{}{
 		"content": map[string]string{
 			"raw": content,
 		},
 	}
+	if parentID, ok := args["parent_id"].(string); ok && parentID != "" {
+		id, err := strconv.Atoi(parentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent_id %q: expected a number", parentID)
+		}
+		body["parent"] = map[string]int{"id": id}
+	}
+
+	// Create API client
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -388,7 +407,7 @@ func PRCommentHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 
 	// Create comment
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments", repository, prID)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pull request comment: %w", err)
 	}
@@ -396,96 +415,6 @@ func PRCommentHandler(ctx context.Context, args map[string]interface{}) ([]Conte
 	return []Content{NewTextContent(string(data))}, nil
 }
 
-// PRCommentsListHandler handles the pr_comments tool invocation.
-func PRCommentsListHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
-	repository, ok := args["repository"].(string)
-	if !ok || repository == "" {
-		return nil, fmt.Errorf("repository parameter is required")
-	}
-	if err := validateRepoArg(repository); err != nil {
-		return nil, err
-	}
-
-	prID, ok := args["pr_id"].(string)
-	if !ok || prID == "" {
-		return nil, fmt.Errorf("pr_id parameter is required")
-	}
-
-	client, err := GetClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create API client: %w", err)
-	}
-
-	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments?pagelen=100", repository, prID)
-	data, err := client.Get(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pull request comments: %w", err)
-	}
-
-	// Parse the paginated response to extract comments
-	var response struct {
-		Values []struct {
-			ID      int `json:"id"`
-			Content struct {
-				Raw string `json:"raw"`
-			} `json:"content"`
-			User struct {
-				DisplayName string `json:"display_name"`
-				Nickname    string `json:"nickname"`
-			} `json:"user"`
-			CreatedOn string `json:"created_on"`
-			UpdatedOn string `json:"updated_on"`
-			Inline    *struct {
-				Path string `json:"path"`
-				From *int   `json:"from"`
-				To   *int   `json:"to"`
-			} `json:"inline"`
-			Parent *struct {
-				ID int `json:"id"`
-			} `json:"parent"`
-			Deleted bool `json:"deleted"`
-		} `json:"values"`
-	}
-	if err := json.Unmarshal(data, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse comments: %w", err)
-	}
-
-	// Format comments for readability
-	var sb strings.Builder
-	for _, c := range response.Values {
-		if c.Deleted {
-			continue
-		}
-		author := c.User.DisplayName
-		if author == "" {
-			author = c.User.Nickname
-		}
-
-		if c.Inline != nil {
-			sb.WriteString(fmt.Sprintf("### Inline comment by %s on `%s`", author, c.Inline.Path))
-			if c.Inline.To != nil {
-				sb.WriteString(fmt.Sprintf(" (line %d)", *c.Inline.To))
-			}
-			sb.WriteString("\n")
-		} else {
-			sb.WriteString(fmt.Sprintf("### Comment by %s", author))
-			if c.Parent != nil {
-				sb.WriteString(fmt.Sprintf(" (reply to #%d)", c.Parent.ID))
-			}
-			sb.WriteString("\n")
-		}
-		sb.WriteString(fmt.Sprintf("*%s*\n\n", c.CreatedOn))
-		sb.WriteString(c.Content.Raw)
-		sb.WriteString("\n\n---\n\n")
-	}
-
-	if sb.Len() == 0 {
-		return []Content{NewTextContent("No comments on this pull request.")}, nil
-	}
-
-	return []Content{NewTextContent(sb.String())}, nil
-}
-
 // PREditHandler handles the pr_edit tool invocation.
 func PREditHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
 	repository, ok := args["repository"].(string)
@@ -516,15 +445,26 @@ func PREditHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 	if closeBranch, ok := args["close_source_branch"].(bool); ok {
 		body["close_source_branch"] = closeBranch
 	}
+	if draft, ok := args["draft"].(bool); ok {
+		body["draft"] = draft
+	}
+	refs, hasReviewers := args["reviewers"].(string)
 
-	if len(body) == 0 {
-		return nil, fmt.Errorf("no changes specified; use title, description, destination, or close_source_branch")
+	if len(body) == 0 && !hasReviewers {
+		return nil, fmt.Errorf("no changes specified; use title, description, destination, close_source_branch, draft, or reviewers")
 	}
 
 	client, err := GetClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
+	if hasReviewers {
+		reviewers, err := resolveReviewers(ctx, client, repository, refs)
+		if err != nil {
+			return nil, err
+		}
+		body["reviewers"] = reviewers
+	}
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -532,7 +472,7 @@ func PREditHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s", repository, prID)
-	data, err := client.Put(path, string(jsonBody))
+	data, err := client.PutContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update pull request: %w", err)
 	}
@@ -550,6 +490,29 @@ func PREditHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 	return []Content{NewTextContent(string(result))}, nil
 }
 
+// resolveReviewers resolves a comma-separated list of users (nicknames,
+// display names, email addresses or UUIDs) to the reviewer objects of a pull
+// request body, looking them up among the members of the repository's
+// workspace.
+func resolveReviewers(ctx context.Context, client *api.Client, repository, refs string) ([]map[string]string, error) {
+	var list []string
+	for _, ref := range strings.Split(refs, ",") {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			list = append(list, ref)
+		}
+	}
+	workspace := strings.SplitN(repository, "/", 2)[0]
+	users, err := cmdutil.NewUserResolver(client, workspace).ResolveAll(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reviewers: %w", err)
+	}
+	reviewers := make([]map[string]string, len(users))
+	for i, u := range users {
+		reviewers[i] = map[string]string{"uuid": u.UUID}
+	}
+	return reviewers, nil
+}
+
 // PRUnapproveHandler handles the pr_unapprove tool invocation.
 func PRUnapproveHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
 	repository, ok := args["repository"].(string)
@@ -571,7 +534,7 @@ func PRUnapproveHandler(ctx context.Context, args map[string]interface{}) ([]Con
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/approve", repository, prID)
-	_, err = client.Delete(path)
+	_, err = client.DeleteContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unapprove pull request: %w", err)
 	}
@@ -579,6 +542,43 @@ func PRUnapproveHandler(ctx context.Context, args map[string]interface{}) ([]Con
 	return []Content{NewTextContent(fmt.Sprintf("Approval removed from PR #%s", prID))}, nil
 }
 
+// PRRequestChangesHandler handles the pr_request_changes tool invocation.
+func PRRequestChangesHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return nil, fmt.Errorf("repository parameter is required")
+	}
+	if err := validateRepoArg(repository); err != nil {
+		return nil, err
+	}
+
+	prID, ok := args["pr_id"].(string)
+	if !ok || prID == "" {
+		return nil, fmt.Errorf("pr_id parameter is required")
+	}
+	undo, _ := args["undo"].(bool)
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/request-changes", repository, prID)
+	if undo {
+		if _, err := client.DeleteContext(ctx, path); err != nil {
+			return nil, fmt.Errorf("failed to remove change request: %w", err)
+		}
+		return []Content{NewTextContent(fmt.Sprintf("Change request removed from PR #%s", prID))}, nil
+	}
+
+	data, err := client.PostContext(ctx, path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to request changes: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
 // PRActivityHandler handles the pr_activity tool invocation.
 func PRActivityHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
 	repository, ok := args["repository"].(string)
@@ -600,7 +600,7 @@ func PRActivityHandler(ctx context.Context, args map[string]interface{}) ([]Cont
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/activity?pagelen=50", repository, prID)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pull request activity: %w", err)
 	}
@@ -682,6 +682,7 @@ type PullRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
 	CreatedOn   string `json:"created_on"`
 	UpdatedOn   string `json:"updated_on"`
 	Author      struct {
@@ -721,5 +722,6 @@ type PullRequest struct {
 		} `json:"user"`
 		Role     string `json:"role"`
 		Approved bool   `json:"approved"`
+		State    string `json:"state"`
 	} `json:"participants"`
 }
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // Repository represents a Bitbucket repository.
@@ -78,7 +76,7 @@ func RepoListHandler(ctx context.Context, args map[string]interface{}) ([]Conten
 	path := fmt.Sprintf("/repositories/%s?pagelen=25&page=%d", url.PathEscape(workspace), page)
 
 	// Fetch repositories
-	repos, err := api.GetPaginated[Repository](client, path)
+	repos, err := fetchPaginated[Repository](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
 	}
@@ -111,7 +109,7 @@ func RepoViewHandler(ctx context.Context, args map[string]interface{}) ([]Conten
 
 	// Fetch repository
 	path := fmt.Sprintf("/repositories/%s", repository)
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repository: %w", err)
 	}
@@ -148,7 +146,7 @@ func SnippetListHandler(ctx context.Context, args map[string]interface{}) ([]Con
 	path := fmt.Sprintf("/snippets/%s?pagelen=25", url.PathEscape(workspace))
 
 	// Fetch snippets
-	snippets, err := api.GetPaginated[Snippet](client, path)
+	snippets, err := fetchPaginated[Snippet](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch snippets: %w", err)
 	}
@@ -183,7 +181,7 @@ func SnippetViewHandler(ctx context.Context, args map[string]interface{}) ([]Con
 
 	// Fetch snippet
 	path := fmt.Sprintf("/snippets/%s/%s", url.PathEscape(workspace), url.PathEscape(snippetID))
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch snippet: %w", err)
 	}
@@ -229,7 +227,7 @@ func BranchListHandler(ctx context.Context, args map[string]interface{}) ([]Cont
 	path := fmt.Sprintf("/repositories/%s/refs/branches?pagelen=25&page=%d", repository, page)
 
 	// Fetch branches
-	branches, err := api.GetPaginated[Branch](client, path)
+	branches, err := fetchPaginated[Branch](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch branches: %w", err)
 	}
@@ -46,7 +46,7 @@ func handleReadmeResource(ctx context.Context, uri string) (*ResourceReadResult,
 		return nil, err
 	}
 
-	data, err := client.Get(fmt.Sprintf("/repositories/%s/src/HEAD/README.md", repo))
+	data, err := client.GetContext(ctx, fmt.Sprintf("/repositories/%s/src/HEAD/README.md", repo))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read README.md: %w", err)
 	}
@@ -90,7 +90,7 @@ func handlePipelineStepLogResource(ctx context.Context, uri string) (*ResourceRe
 		return nil, err
 	}
 
-	data, err := client.Get(fmt.Sprintf("/repositories/%s/pipelines/%s/steps/%s/log", repo, pipelineUUID, stepUUID))
+	data, err := client.GetContext(ctx, fmt.Sprintf("/repositories/%s/pipelines/%s/steps/%s/log", repo, pipelineUUID, stepUUID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read pipeline step log: %w", err)
 	}
@@ -140,7 +140,7 @@ func handlePRDiffResource(ctx context.Context, uri string) (*ResourceReadResult,
 		return nil, err
 	}
 
-	data, err := client.Get(fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", repo, prID))
+	data, err := client.GetContext(ctx, fmt.Sprintf("/repositories/%s/pullrequests/%s/diff", repo, prID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read PR diff: %w", err)
 	}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommitStatus represents a build status reported against a commit.
+type CommitStatus struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	RefName     string `json:"refname"`
+	CreatedOn   string `json:"created_on"`
+	UpdatedOn   string `json:"updated_on"`
+}
+
+var commitArgPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+
+// validateCommitArg checks that a commit argument is a hex commit hash, so it
+// can be used safely in an API path.
+func validateCommitArg(commit string) error {
+	if !commitArgPattern.MatchString(commit) {
+		return fmt.Errorf("invalid commit %q: expected a commit hash", commit)
+	}
+	return nil
+}
+
+// StatusListHandler handles the status_list tool invocation.
+func StatusListHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return nil, fmt.Errorf("repository parameter is required")
+	}
+	if err := validateRepoArg(repository); err != nil {
+		return nil, err
+	}
+
+	commit, ok := args["commit"].(string)
+	if !ok || commit == "" {
+		return nil, fmt.Errorf("commit parameter is required")
+	}
+	if err := validateCommitArg(commit); err != nil {
+		return nil, err
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/commit/%s/statuses?pagelen=25", repository, commit)
+	statuses, err := fetchPaginated[CommitStatus](ctx, client, path, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit statuses: %w", err)
+	}
+
+	data, err := json.Marshal(statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal commit statuses: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
+// StatusSetHandler handles the status_set tool invocation.
+func StatusSetHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return nil, fmt.Errorf("repository parameter is required")
+	}
+	if err := validateRepoArg(repository); err != nil {
+		return nil, err
+	}
+
+	commit, ok := args["commit"].(string)
+	if !ok || commit == "" {
+		return nil, fmt.Errorf("commit parameter is required")
+	}
+	if err := validateCommitArg(commit); err != nil {
+		return nil, err
+	}
+
+	state, ok := args["state"].(string)
+	if !ok || state == "" {
+		return nil, fmt.Errorf("state parameter is required")
+	}
+	state = strings.ToUpper(state)
+	switch state {
+	case "INPROGRESS", "SUCCESSFUL", "FAILED", "STOPPED":
+	default:
+		return nil, fmt.Errorf("invalid state %q: expected INPROGRESS, SUCCESSFUL, FAILED or STOPPED", state)
+	}
+
+	key, ok := args["key"].(string)
+	if !ok || key == "" {
+		return nil, fmt.Errorf("key parameter is required")
+	}
+
+	statusURL, ok := args["url"].(string)
+	if !ok || statusURL == "" {
+		return nil, fmt.Errorf("url parameter is required")
+	}
+
+	body := map[string]string{
+		"key":   key,
+		"state": state,
+		"url":   statusURL,
+	}
+	for _, field := range []string{"name", "description", "refname"} {
+		if v, ok := args[field].(string); ok && v != "" {
+			body[field] = v
+		}
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/commit/%s/statuses/build", repository, commit)
+	data, err := client.PostContext(ctx, path, string(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set commit status: %w", err)
+	}
+
+	var status CommitStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal commit status: %w", err)
+	}
+
+	result, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal commit status: %w", err)
+	}
+
+	return []Content{NewTextContent(string(result))}, nil
+}
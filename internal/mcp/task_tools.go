@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PRTask represents a task on a pull request.
+type PRTask struct {
+	ID      int    `json:"id"`
+	State   string `json:"state"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	Creator struct {
+		DisplayName string `json:"display_name"`
+	} `json:"creator"`
+	Comment *struct {
+		ID int `json:"id"`
+	} `json:"comment,omitempty"`
+	CreatedOn  string `json:"created_on"`
+	ResolvedOn string `json:"resolved_on,omitempty"`
+}
+
+// prItemArgs returns the repository and pr_id arguments and, when idParam is
+// set, the numeric ID of an item of the pull request given in that argument.
+func prItemArgs(args map[string]interface{}, idParam string) (repository, prID, itemID string, err error) {
+	repository, ok := args["repository"].(string)
+	if !ok || repository == "" {
+		return "", "", "", fmt.Errorf("repository parameter is required")
+	}
+	if err := validateRepoArg(repository); err != nil {
+		return "", "", "", err
+	}
+
+	prID, ok = args["pr_id"].(string)
+	if !ok || prID == "" {
+		return "", "", "", fmt.Errorf("pr_id parameter is required")
+	}
+
+	if idParam != "" {
+		itemID, ok = args[idParam].(string)
+		if !ok || itemID == "" {
+			return "", "", "", fmt.Errorf("%s parameter is required", idParam)
+		}
+		if _, err := strconv.Atoi(itemID); err != nil {
+			return "", "", "", fmt.Errorf("invalid %s %q: expected a number", idParam, itemID)
+		}
+	}
+	return repository, prID, itemID, nil
+}
+
+// PRTasksHandler handles the pr_tasks tool invocation.
+func PRTasksHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, _, err := prItemArgs(args, "")
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks?pagelen=50", repository, prID)
+	if state, ok := args["state"].(string); ok && state != "" {
+		switch strings.ToLower(state) {
+		case "open", "unresolved":
+			state = "UNRESOLVED"
+		case "resolved":
+			state = "RESOLVED"
+		default:
+			return nil, fmt.Errorf("invalid state %q: expected open or resolved", state)
+		}
+		path += "&q=" + url.QueryEscape(fmt.Sprintf(`state="%s"`, state))
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	tasks, err := fetchPaginated[PRTask](ctx, client, path, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
+// PRTaskCreateHandler handles the pr_task_create tool invocation.
+func PRTaskCreateHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, _, err := prItemArgs(args, "")
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := args["content"].(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content parameter is required")
+	}
+
+	body := map[string]interface{}{
+		"content": map[string]string{"raw": content},
+	}
+	if commentID, ok := args["comment_id"].(string); ok && commentID != "" {
+		id, err := strconv.Atoi(commentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment_id %q: expected a number", commentID)
+		}
+		body["comment"] = map[string]int{"id": id}
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks", repository, prID)
+	data, err := client.PostContext(ctx, path, string(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
+// PRTaskResolveHandler handles the pr_task_resolve tool invocation.
+func PRTaskResolveHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, taskID, err := prItemArgs(args, "task_id")
+	if err != nil {
+		return nil, err
+	}
+	reopen, _ := args["reopen"].(bool)
+
+	state := "RESOLVED"
+	if reopen {
+		state = "UNRESOLVED"
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(map[string]string{"state": state})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks/%s", repository, prID, taskID)
+	data, err := client.PutContext(ctx, path, string(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return []Content{NewTextContent(string(data))}, nil
+}
+
+// PRTaskDeleteHandler handles the pr_task_delete tool invocation.
+func PRTaskDeleteHandler(ctx context.Context, args map[string]interface{}) ([]Content, error) {
+	repository, prID, taskID, err := prItemArgs(args, "task_id")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/tasks/%s", repository, prID, taskID)
+	if _, err := client.DeleteContext(ctx, path); err != nil {
+		return nil, fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	return []Content{NewTextContent(fmt.Sprintf("Task #%s deleted from PR #%s", taskID, prID))}, nil
+}
@@ -262,6 +262,8 @@ func NewPRListTool() Tool {
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"state":      NewStringProperty("Optional state filter: OPEN, MERGED, DECLINED, or SUPERSEDED"),
 			"page":       NewNumberProperty("Optional page number (default: 1)"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -292,6 +294,8 @@ func NewPRCreateTool() Tool {
 			"description":  NewStringProperty("Optional pull request description"),
 			"destination":  NewStringProperty("Optional destination branch (defaults to main branch)"),
 			"close_branch": NewBooleanProperty("Optional: close source branch after merge (default: false)"),
+			"draft":        NewBooleanProperty("Optional: create the pull request as a draft (default: false)"),
+			"reviewers":    NewStringProperty("Optional: comma-separated reviewers by nickname, display name, email or UUID"),
 		}, []string{"repository", "title", "source"}),
 	}
 }
@@ -355,11 +359,12 @@ func NewPRCommentTool() Tool {
 	return Tool{
 		Name:        "pr_comment",
 		Title:       "Comment on Pull Request",
-		Description: "Add a comment to a pull request in a Bitbucket repository",
+		Description: "Add a comment to a pull request in a Bitbucket repository, or reply to an existing comment",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"pr_id":      NewStringProperty("Pull request ID"),
 			"content":    NewStringProperty("Comment content in raw/markdown format"),
+			"parent_id":  NewStringProperty("Optional: ID of the comment to reply to"),
 		}, []string{"repository", "pr_id", "content"}),
 	}
 }
@@ -369,11 +374,57 @@ func NewPRCommentsListTool() Tool {
 	return Tool{
 		Name:        "pr_comments",
 		Title:       "List Pull Request Comments",
-		Description: "List all comments on a pull request, including inline code review comments and general comments",
+		Description: "List all comments on a pull request as threads, including inline code review comments with the surrounding diff and general comments",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository":    NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":         NewStringProperty("Pull request ID"),
+			"unresolved":    NewBooleanProperty("Optional: only list threads that are not resolved (default: false)"),
+			"context_lines": NewNumberProperty("Optional: lines of diff shown around inline comments, 0 to hide (default: 3)"),
+		}, []string{"repository", "pr_id"}),
+	}
+}
+
+// NewPRCommentEditTool creates a tool definition for editing a PR comment.
+func NewPRCommentEditTool() Tool {
+	return Tool{
+		Name:        "pr_comment_edit",
+		Title:       "Edit Pull Request Comment",
+		Description: "Replace the content of a comment on a pull request",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"pr_id":      NewStringProperty("Pull request ID"),
-		}, []string{"repository", "pr_id"}),
+			"comment_id": NewStringProperty("Comment ID"),
+			"content":    NewStringProperty("New comment content in raw/markdown format"),
+		}, []string{"repository", "pr_id", "comment_id", "content"}),
+	}
+}
+
+// NewPRCommentDeleteTool creates a tool definition for deleting a PR comment.
+func NewPRCommentDeleteTool() Tool {
+	return Tool{
+		Name:        "pr_comment_delete",
+		Title:       "Delete Pull Request Comment",
+		Description: "Delete a comment from a pull request",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"comment_id": NewStringProperty("Comment ID"),
+		}, []string{"repository", "pr_id", "comment_id"}),
+	}
+}
+
+// NewPRCommentResolveTool creates a tool definition for resolving a PR comment thread.
+func NewPRCommentResolveTool() Tool {
+	return Tool{
+		Name:        "pr_comment_resolve",
+		Title:       "Resolve Pull Request Comment Thread",
+		Description: "Resolve the thread a pull request comment starts, or reopen a resolved thread",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"comment_id": NewStringProperty("ID of the thread's top-level comment"),
+			"unresolve":  NewBooleanProperty("Optional: reopen the thread instead (default: false)"),
+		}, []string{"repository", "pr_id", "comment_id"}),
 	}
 }
 
@@ -382,7 +433,7 @@ func NewPREditTool() Tool {
 	return Tool{
 		Name:        "pr_edit",
 		Title:       "Edit Pull Request",
-		Description: "Edit a pull request in a Bitbucket repository (update title, description, destination branch, or close-branch setting)",
+		Description: "Edit a pull request in a Bitbucket repository (update title, description, destination branch, close-branch setting, draft status, or reviewers)",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository":          NewStringProperty("Repository in format workspace/repo-slug"),
 			"pr_id":               NewStringProperty("Pull request ID"),
@@ -390,6 +441,8 @@ func NewPREditTool() Tool {
 			"description":         NewStringProperty("Optional: new PR description"),
 			"destination":         NewStringProperty("Optional: new destination branch"),
 			"close_source_branch": NewBooleanProperty("Optional: close source branch after merge"),
+			"draft":               NewBooleanProperty("Optional: true converts to a draft, false marks it ready for review"),
+			"reviewers":           NewStringProperty("Optional: comma-separated reviewers by nickname, display name, email or UUID, replacing the current reviewers (empty removes all)"),
 		}, []string{"repository", "pr_id"}),
 	}
 }
@@ -407,6 +460,80 @@ func NewPRUnapproveTool() Tool {
 	}
 }
 
+// NewPRRequestChangesTool creates a tool definition for requesting changes on a pull request.
+func NewPRRequestChangesTool() Tool {
+	return Tool{
+		Name:        "pr_request_changes",
+		Title:       "Request Changes on Pull Request",
+		Description: "Request changes on a pull request in a Bitbucket repository, or remove your change request",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"undo":       NewBooleanProperty("Optional: remove your change request instead (default: false)"),
+		}, []string{"repository", "pr_id"}),
+	}
+}
+
+// NewPRTasksTool creates a tool definition for listing pull request tasks.
+func NewPRTasksTool() Tool {
+	return Tool{
+		Name:        "pr_tasks",
+		Title:       "List Pull Request Tasks",
+		Description: "List the tasks on a pull request, optionally only open or resolved ones",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"state":      NewStringProperty("Optional: filter by state (open or resolved)"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
+		}, []string{"repository", "pr_id"}),
+	}
+}
+
+// NewPRTaskCreateTool creates a tool definition for creating a pull request task.
+func NewPRTaskCreateTool() Tool {
+	return Tool{
+		Name:        "pr_task_create",
+		Title:       "Create Pull Request Task",
+		Description: "Create a task on a pull request, optionally attached to a comment",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"content":    NewStringProperty("Task description"),
+			"comment_id": NewStringProperty("Optional: ID of the comment to attach the task to"),
+		}, []string{"repository", "pr_id", "content"}),
+	}
+}
+
+// NewPRTaskResolveTool creates a tool definition for resolving or reopening a pull request task.
+func NewPRTaskResolveTool() Tool {
+	return Tool{
+		Name:        "pr_task_resolve",
+		Title:       "Resolve Pull Request Task",
+		Description: "Resolve a task on a pull request, or reopen a resolved one",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"task_id":    NewStringProperty("Task ID"),
+			"reopen":     NewBooleanProperty("Optional: reopen the task instead (default: false)"),
+		}, []string{"repository", "pr_id", "task_id"}),
+	}
+}
+
+// NewPRTaskDeleteTool creates a tool definition for deleting a pull request task.
+func NewPRTaskDeleteTool() Tool {
+	return Tool{
+		Name:        "pr_task_delete",
+		Title:       "Delete Pull Request Task",
+		Description: "Delete a task from a pull request",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"pr_id":      NewStringProperty("Pull request ID"),
+			"task_id":    NewStringProperty("Task ID"),
+		}, []string{"repository", "pr_id", "task_id"}),
+	}
+}
+
 // NewPRActivityTool creates a tool definition for viewing pull request activity.
 func NewPRActivityTool() Tool {
 	return Tool{
@@ -432,6 +559,8 @@ func NewIssueListTool() Tool {
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"state":      NewStringProperty("Optional state filter: new, open, resolved, on hold, invalid, duplicate, wontfix, closed"),
 			"page":       NewNumberProperty("Optional page number (default: 1)"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -521,6 +650,8 @@ func NewPipelineListTool() Tool {
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"page":       NewNumberProperty("Optional page number (default: 1)"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -577,6 +708,8 @@ func NewRepoListTool() Tool {
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"workspace": NewStringProperty("Bitbucket workspace slug"),
 			"page":      NewNumberProperty("Optional page number (default: 1)"),
+			"all":       NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":     NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"workspace"}),
 	}
 }
@@ -603,6 +736,8 @@ func NewSnippetListTool() Tool {
 		Description: "List snippets in a Bitbucket workspace",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"workspace": NewStringProperty("Bitbucket workspace slug"),
+			"all":       NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":     NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"workspace"}),
 	}
 }
@@ -631,6 +766,8 @@ func NewBranchListTool() Tool {
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
 			"page":       NewNumberProperty("Optional page number (default: 1)"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -643,7 +780,10 @@ func NewWorkspaceListTool() Tool {
 		Name:        "workspace_list",
 		Title:       "List Workspaces",
 		Description: "List Bitbucket workspaces you belong to",
-		InputSchema: NewJSONSchema("object", map[string]interface{}{}, nil),
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"all":   NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit": NewNumberProperty("Optional: maximum number of results to fetch across pages"),
+		}, nil),
 	}
 }
 
@@ -667,6 +807,8 @@ func NewWorkspaceMembersTool() Tool {
 		Description: "List members of a Bitbucket workspace",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"workspace": NewStringProperty("Workspace slug"),
+			"all":       NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":     NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"workspace"}),
 	}
 }
@@ -679,6 +821,8 @@ func NewWorkspaceProjectsTool() Tool {
 		Description: "List projects in a Bitbucket workspace",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"workspace": NewStringProperty("Workspace slug"),
+			"all":       NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":     NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"workspace"}),
 	}
 }
@@ -741,7 +885,10 @@ func NewUserEmailsTool() Tool {
 		Name:        "user_emails",
 		Title:       "List User Emails",
 		Description: "List email addresses of the current authenticated user",
-		InputSchema: NewJSONSchema("object", map[string]interface{}{}, nil),
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"all":   NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit": NewNumberProperty("Optional: maximum number of results to fetch across pages"),
+		}, nil),
 	}
 }
 
@@ -751,7 +898,10 @@ func NewUserSSHKeysTool() Tool {
 		Name:        "user_ssh_keys",
 		Title:       "List SSH Keys",
 		Description: "List SSH keys of the current authenticated user",
-		InputSchema: NewJSONSchema("object", map[string]interface{}{}, nil),
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"all":   NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit": NewNumberProperty("Optional: maximum number of results to fetch across pages"),
+		}, nil),
 	}
 }
 
@@ -778,6 +928,8 @@ func NewEnvironmentListTool() Tool {
 		Description: "List deployment environments for a Bitbucket repository",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -832,6 +984,8 @@ func NewVariableListTool() Tool {
 		Description: "List pipeline variables for a Bitbucket repository",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -902,6 +1056,8 @@ func NewDownloadListTool() Tool {
 		Description: "List repository downloads in a Bitbucket repository",
 		InputSchema: NewJSONSchema("object", map[string]interface{}{
 			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
 		}, []string{"repository"}),
 	}
 }
@@ -919,8 +1075,44 @@ func NewDownloadDeleteTool() Tool {
 	}
 }
 
+// Commit Status Tool Definitions
+
+// NewStatusListTool creates a tool definition for listing commit statuses.
+func NewStatusListTool() Tool {
+	return Tool{
+		Name:        "status_list",
+		Title:       "List Commit Statuses",
+		Description: "List the build statuses reported against a commit",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository": NewStringProperty("Repository in format workspace/repo-slug"),
+			"commit":     NewStringProperty("Commit hash"),
+			"all":        NewBooleanProperty("Optional: fetch all pages by following pagination links (capped at 1000 results)"),
+			"limit":      NewNumberProperty("Optional: maximum number of results to fetch across pages"),
+		}, []string{"repository", "commit"}),
+	}
+}
+
+// NewStatusSetTool creates a tool definition for reporting a commit status.
+func NewStatusSetTool() Tool {
+	return Tool{
+		Name:        "status_set",
+		Title:       "Set Commit Status",
+		Description: "Create or update a build status on a commit; a status with the same key is replaced",
+		InputSchema: NewJSONSchema("object", map[string]interface{}{
+			"repository":  NewStringProperty("Repository in format workspace/repo-slug"),
+			"commit":      NewStringProperty("Commit hash"),
+			"state":       NewStringProperty("Build state: INPROGRESS, SUCCESSFUL, FAILED, or STOPPED"),
+			"key":         NewStringProperty("Unique key identifying the build"),
+			"url":         NewStringProperty("Link to the build results"),
+			"name":        NewStringProperty("Optional display name of the build"),
+			"description": NewStringProperty("Optional description of the build result"),
+			"refname":     NewStringProperty("Optional branch or tag the build ran on"),
+		}, []string{"repository", "commit", "state", "key", "url"}),
+	}
+}
+
 // RegisterDefaultTools registers all default bb tools with the given registry.
-// This includes PR, Issue, Pipeline, Repo, Snippet, Branch, Workspace, User, Environment, Variable, and Download tools.
+// This includes PR, Issue, Pipeline, Commit Status, Repo, Snippet, Branch, Workspace, User, Environment, Variable, and Download tools.
 func RegisterDefaultTools(registry *ToolRegistry) error {
 	// PR Tools
 	if err := registry.Register(NewPRListTool(), PRListHandler); err != nil {
@@ -950,12 +1142,36 @@ func RegisterDefaultTools(registry *ToolRegistry) error {
 	if err := registry.Register(NewPRCommentsListTool(), PRCommentsListHandler); err != nil {
 		return fmt.Errorf("failed to register pr_comments: %w", err)
 	}
+	if err := registry.Register(NewPRCommentEditTool(), PRCommentEditHandler); err != nil {
+		return fmt.Errorf("failed to register pr_comment_edit: %w", err)
+	}
+	if err := registry.Register(NewPRCommentDeleteTool(), PRCommentDeleteHandler); err != nil {
+		return fmt.Errorf("failed to register pr_comment_delete: %w", err)
+	}
+	if err := registry.Register(NewPRCommentResolveTool(), PRCommentResolveHandler); err != nil {
+		return fmt.Errorf("failed to register pr_comment_resolve: %w", err)
+	}
 	if err := registry.Register(NewPREditTool(), PREditHandler); err != nil {
 		return fmt.Errorf("failed to register pr_edit: %w", err)
 	}
 	if err := registry.Register(NewPRUnapproveTool(), PRUnapproveHandler); err != nil {
 		return fmt.Errorf("failed to register pr_unapprove: %w", err)
 	}
+	if err := registry.Register(NewPRRequestChangesTool(), PRRequestChangesHandler); err != nil {
+		return fmt.Errorf("failed to register pr_request_changes: %w", err)
+	}
+	if err := registry.Register(NewPRTasksTool(), PRTasksHandler); err != nil {
+		return fmt.Errorf("failed to register pr_tasks: %w", err)
+	}
+	if err := registry.Register(NewPRTaskCreateTool(), PRTaskCreateHandler); err != nil {
+		return fmt.Errorf("failed to register pr_task_create: %w", err)
+	}
+	if err := registry.Register(NewPRTaskResolveTool(), PRTaskResolveHandler); err != nil {
+		return fmt.Errorf("failed to register pr_task_resolve: %w", err)
+	}
+	if err := registry.Register(NewPRTaskDeleteTool(), PRTaskDeleteHandler); err != nil {
+		return fmt.Errorf("failed to register pr_task_delete: %w", err)
+	}
 	if err := registry.Register(NewPRActivityTool(), PRActivityHandler); err != nil {
 		return fmt.Errorf("failed to register pr_activity: %w", err)
 	}
@@ -994,6 +1210,14 @@ func RegisterDefaultTools(registry *ToolRegistry) error {
 		return fmt.Errorf("failed to register pipeline_stop: %w", err)
 	}
 
+	// Commit Status Tools
+	if err := registry.Register(NewStatusListTool(), StatusListHandler); err != nil {
+		return fmt.Errorf("failed to register status_list: %w", err)
+	}
+	if err := registry.Register(NewStatusSetTool(), StatusSetHandler); err != nil {
+		return fmt.Errorf("failed to register status_set: %w", err)
+	}
+
 	// Repo Tools
 	if err := registry.Register(NewRepoListTool(), RepoListHandler); err != nil {
 		return fmt.Errorf("failed to register repo_list: %w", err)
@@ -970,6 +970,26 @@ func TestToolSchemas(t *testing.T) {
 			tool:     NewPRCommentTool(),
 			required: []string{"repository", "pr_id", "content"},
 		},
+		{
+			name:     "pr_request_changes",
+			tool:     NewPRRequestChangesTool(),
+			required: []string{"repository", "pr_id"},
+		},
+		{
+			name:     "pr_comment_edit",
+			tool:     NewPRCommentEditTool(),
+			required: []string{"repository", "pr_id", "comment_id", "content"},
+		},
+		{
+			name:     "pr_task_create",
+			tool:     NewPRTaskCreateTool(),
+			required: []string{"repository", "pr_id", "content"},
+		},
+		{
+			name:     "pr_task_resolve",
+			tool:     NewPRTaskResolveTool(),
+			required: []string{"repository", "pr_id", "task_id"},
+		},
 		{
 			name:     "issue_list",
 			tool:     NewIssueListTool(),
@@ -1214,9 +1234,12 @@ func TestRegisterDefaultTools(t *testing.T) {
 	expectedTools := []string{
 		"pr_list", "pr_view", "pr_create",
 		"pr_approve", "pr_merge", "pr_decline", "pr_diff", "pr_comment", "pr_comments",
-		"pr_edit", "pr_unapprove", "pr_activity",
+		"pr_comment_edit", "pr_comment_delete", "pr_comment_resolve",
+		"pr_edit", "pr_unapprove", "pr_request_changes", "pr_activity",
+		"pr_tasks", "pr_task_create", "pr_task_resolve", "pr_task_delete",
 		"issue_list", "issue_create", "issue_view", "issue_edit", "issue_delete", "issue_comment",
 		"pipeline_list", "pipeline_trigger", "pipeline_view", "pipeline_stop",
+		"status_list", "status_set",
 		"repo_list", "repo_view",
 		"snippet_list", "snippet_view",
 		"branch_list",
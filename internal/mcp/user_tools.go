@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // User represents a Bitbucket user account.
@@ -50,7 +48,7 @@ func UserMeHandler(ctx context.Context, args map[string]interface{}) ([]Content,
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	data, err := client.Get("/user")
+	data, err := client.GetContext(ctx, "/user")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch current user: %w", err)
 	}
@@ -81,7 +79,7 @@ func UserViewHandler(ctx context.Context, args map[string]interface{}) ([]Conten
 	}
 
 	path := fmt.Sprintf("/users/%s", url.PathEscape(userID))
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
@@ -106,7 +104,7 @@ func UserEmailsHandler(ctx context.Context, args map[string]interface{}) ([]Cont
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	emails, err := api.GetPaginated[Email](client, "/user/emails")
+	emails, err := fetchPaginated[Email](ctx, client, "/user/emails", args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list emails: %w", err)
 	}
@@ -126,7 +124,7 @@ func UserSSHKeysHandler(ctx context.Context, args map[string]interface{}) ([]Con
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	keys, err := api.GetPaginated[SSHKey](client, "/user/ssh-keys?pagelen=50")
+	keys, err := fetchPaginated[SSHKey](ctx, client, "/user/ssh-keys?pagelen=50", args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list SSH keys: %w", err)
 	}
@@ -165,7 +163,7 @@ func UserSSHKeyAddHandler(ctx context.Context, args map[string]interface{}) ([]C
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	data, err := client.Post("/user/ssh-keys", string(jsonBody))
+	data, err := client.PostContext(ctx, "/user/ssh-keys", string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add SSH key: %w", err)
 	}
@@ -17,9 +17,14 @@ type Variable struct {
 	Secured bool   `json:"secured"`
 }
 
-func listVariables(client *api.Client, repository string) ([]Variable, error) {
-	path := fmt.Sprintf("/repositories/%s/pipelines_config/variables?pagelen=100", repository)
-	return api.GetPaginated[Variable](client, path)
+func variablesPath(repository string) string {
+	return fmt.Sprintf("/repositories/%s/pipelines_config/variables?pagelen=100", repository)
+}
+
+// listVariables fetches every pipeline variable of a repository, so that a
+// variable can be looked up by key.
+func listVariables(ctx context.Context, client *api.Client, repository string) ([]Variable, error) {
+	return api.GetAll[Variable](ctx, client, variablesPath(repository), 0)
 }
 
 func findVariableByKey(variables []Variable, key string) (*Variable, error) {
@@ -46,7 +51,7 @@ func VariableListHandler(ctx context.Context, args map[string]interface{}) ([]Co
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	variables, err := listVariables(client, repository)
+	variables, err := fetchPaginated[Variable](ctx, client, variablesPath(repository), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list variables: %w", err)
 	}
@@ -79,7 +84,7 @@ func VariableGetHandler(ctx context.Context, args map[string]interface{}) ([]Con
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	variables, err := listVariables(client, repository)
+	variables, err := listVariables(ctx, client, repository)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list variables: %w", err)
 	}
@@ -139,7 +144,7 @@ func VariableSetHandler(ctx context.Context, args map[string]interface{}) ([]Con
 	}
 
 	path := fmt.Sprintf("/repositories/%s/pipelines_config/variables", repository)
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create variable: %w", err)
 	}
@@ -188,7 +193,7 @@ func VariableUpdateHandler(ctx context.Context, args map[string]interface{}) ([]
 	}
 
 	// Find existing variable UUID by key
-	variables, err := listVariables(client, repository)
+	variables, err := listVariables(ctx, client, repository)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list variables: %w", err)
 	}
@@ -211,7 +216,7 @@ func VariableUpdateHandler(ctx context.Context, args map[string]interface{}) ([]
 
 	path := fmt.Sprintf("/repositories/%s/pipelines_config/variables/%s",
 		repository, url.PathEscape(existing.UUID))
-	data, err := client.Put(path, string(jsonBody))
+	data, err := client.PutContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update variable: %w", err)
 	}
@@ -250,7 +255,7 @@ func VariableDeleteHandler(ctx context.Context, args map[string]interface{}) ([]
 	}
 
 	// Find existing variable UUID by key
-	variables, err := listVariables(client, repository)
+	variables, err := listVariables(ctx, client, repository)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list variables: %w", err)
 	}
@@ -262,7 +267,7 @@ func VariableDeleteHandler(ctx context.Context, args map[string]interface{}) ([]
 
 	path := fmt.Sprintf("/repositories/%s/pipelines_config/variables/%s",
 		repository, url.PathEscape(existing.UUID))
-	_, err = client.Delete(path)
+	_, err = client.DeleteContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete variable: %w", err)
 	}
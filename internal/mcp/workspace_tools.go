@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-
-	"github.com/PhilipKram/bitbucket-cli/internal/api"
 )
 
 // Workspace represents a Bitbucket workspace.
@@ -54,7 +52,7 @@ func WorkspaceListHandler(ctx context.Context, args map[string]interface{}) ([]C
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	workspaces, err := api.GetPaginated[Workspace](client, "/workspaces?pagelen=50")
+	workspaces, err := fetchPaginated[Workspace](ctx, client, "/workspaces?pagelen=50", args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workspaces: %w", err)
 	}
@@ -80,7 +78,7 @@ func WorkspaceViewHandler(ctx context.Context, args map[string]interface{}) ([]C
 	}
 
 	path := fmt.Sprintf("/workspaces/%s", url.PathEscape(workspace))
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch workspace: %w", err)
 	}
@@ -111,7 +109,7 @@ func WorkspaceMembersHandler(ctx context.Context, args map[string]interface{}) (
 	}
 
 	path := fmt.Sprintf("/workspaces/%s/members?pagelen=50", url.PathEscape(workspace))
-	members, err := api.GetPaginated[WorkspaceMember](client, path)
+	members, err := fetchPaginated[WorkspaceMember](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workspace members: %w", err)
 	}
@@ -137,7 +135,7 @@ func WorkspaceProjectsHandler(ctx context.Context, args map[string]interface{})
 	}
 
 	path := fmt.Sprintf("/workspaces/%s/projects?pagelen=50", url.PathEscape(workspace))
-	projects, err := api.GetPaginated[Project](client, path)
+	projects, err := fetchPaginated[Project](ctx, client, path, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
@@ -191,7 +189,7 @@ func WorkspaceProjectCreateHandler(ctx context.Context, args map[string]interfac
 	}
 
 	path := fmt.Sprintf("/workspaces/%s/projects", url.PathEscape(workspace))
-	data, err := client.Post(path, string(jsonBody))
+	data, err := client.PostContext(ctx, path, string(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
@@ -222,7 +220,7 @@ func WorkspacePermissionsHandler(ctx context.Context, args map[string]interface{
 	}
 
 	path := fmt.Sprintf("/workspaces/%s/permissions?pagelen=50", url.PathEscape(workspace))
-	data, err := client.Get(path)
+	data, err := client.GetContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch workspace permissions: %w", err)
 	}
@@ -0,0 +1,300 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+// Backgrounds of added and removed lines in colored diffs, so that the
+// foreground is left to syntax highlighting, and the code resetting only the
+// foreground.
+const (
+	diffAddedBackground   = "\033[48;5;22m"
+	diffRemovedBackground = "\033[48;5;52m"
+	defaultForeground     = "\033[39m"
+)
+
+// WriteDiff writes a unified diff. On a terminal it is colored, with the code
+// highlighted according to the language of each file.
+func WriteDiff(w io.Writer, text string) {
+	writeDiff(w, text, isTTY())
+}
+
+func writeDiff(w io.Writer, text string, color bool) {
+	paint := func(text, code string) string {
+		if !color {
+			return text
+		}
+		return code + text + ColorReset
+	}
+
+	header := false
+	var path string
+	var code *codeHighlighter
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			header, path, code = true, "", nil
+			line = paint(line, ColorBold)
+		case strings.HasPrefix(line, "@@"):
+			if header {
+				header = false
+				code = newCodeHighlighter(path, color)
+			}
+			line = paint(line, ColorCyan)
+		case header:
+			if p, ok := strings.CutPrefix(line, "--- a/"); ok {
+				path = p
+			} else if p, ok := strings.CutPrefix(line, "+++ b/"); ok {
+				path = p
+			}
+			line = paint(line, ColorBold)
+		case code != nil && line != "" && strings.ContainsRune("+- ", rune(line[0])):
+			kind := diff.LineKind(line[0])
+			line = code.line(line[:1], line[1:], kind)
+		case strings.HasPrefix(line, "\\"):
+			line = paint(line, ColorGray)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// WriteSideBySideDiff writes files with the old and new version of each hunk
+// in two columns, fitting the output into width columns. On a terminal it is
+// colored like WriteDiff.
+func WriteSideBySideDiff(w io.Writer, files []diff.File, width int) {
+	writeSideBySideDiff(w, files, width, isTTY())
+}
+
+func writeSideBySideDiff(w io.Writer, files []diff.File, width int, color bool) {
+	paint := func(text, code string) string {
+		if !color {
+			return text
+		}
+		return code + text + ColorReset
+	}
+
+	// Each side has a 5 column line number and a space; " | " separates them.
+	textWidth := max((width-3)/2-6, 10)
+	blank := strings.Repeat(" ", textWidth+6)
+
+	for i, f := range files {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		name := f.Path()
+		switch {
+		case f.OldPath == "":
+			name += " (added)"
+		case f.NewPath == "":
+			name += " (deleted)"
+		case f.OldPath != f.NewPath:
+			name = f.OldPath + " -> " + f.NewPath
+		}
+		fmt.Fprintln(w, paint(name, ColorBold))
+		if f.Binary {
+			fmt.Fprintln(w, paint("Binary file changed", ColorGray))
+			continue
+		}
+
+		code := newCodeHighlighter(f.Path(), color)
+		cell := func(num int, l diff.Line) string {
+			n := ""
+			if num > 0 {
+				n = fmt.Sprint(num)
+			}
+			text := fitText(l.Text, textWidth)
+			return fmt.Sprintf("%5s ", n) + code.line("", text, l.Kind) + strings.Repeat(" ", textWidth-len([]rune(text)))
+		}
+
+		for _, h := range f.Hunks {
+			fmt.Fprintln(w, paint(h.Header, ColorCyan))
+			lines := h.Lines
+			for len(lines) > 0 {
+				if lines[0].Kind == diff.Context {
+					l := lines[0]
+					fmt.Fprintf(w, "%s | %s\n", cell(l.OldLine, l), cell(l.NewLine, l))
+					lines = lines[1:]
+					continue
+				}
+				// Pair a run of removed lines with the added lines after it.
+				var removed, added []diff.Line
+				for len(lines) > 0 && lines[0].Kind == diff.Removed {
+					removed = append(removed, lines[0])
+					lines = lines[1:]
+				}
+				for len(lines) > 0 && lines[0].Kind == diff.Added {
+					added = append(added, lines[0])
+					lines = lines[1:]
+				}
+				for j := 0; j < max(len(removed), len(added)); j++ {
+					left, right := blank, ""
+					if j < len(removed) {
+						left = cell(removed[j].OldLine, removed[j])
+					}
+					if j < len(added) {
+						right = cell(added[j].NewLine, added[j])
+					}
+					fmt.Fprintf(w, "%s | %s\n", left, strings.TrimRight(right, " "))
+				}
+			}
+		}
+	}
+}
+
+// fitText expands tabs and cuts text to width runes.
+func fitText(text string, width int) string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	if r := []rune(text); len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return text
+}
+
+// codeHighlighter colors the lines of code of one file in a diff.
+type codeHighlighter struct {
+	color bool
+	lexer chroma.Lexer
+}
+
+// newCodeHighlighter returns a highlighter for the file at path. Without
+// color it leaves lines as they are, and files whose language is not known
+// only get the colors of added and removed lines.
+func newCodeHighlighter(path string, color bool) *codeHighlighter {
+	h := &codeHighlighter{color: color}
+	if color && path != "" {
+		if lexer := lexers.Match(path); lexer != nil {
+			h.lexer = chroma.Coalesce(lexer)
+		}
+	}
+	return h
+}
+
+// line returns prefix followed by text, a line of code of the given kind.
+// Added and removed lines get a background color and a colored prefix, and
+// the code is highlighted on top of it.
+func (h *codeHighlighter) line(prefix, text string, kind diff.LineKind) string {
+	if !h.color {
+		return prefix + text
+	}
+	var sb strings.Builder
+	switch kind {
+	case diff.Added:
+		sb.WriteString(diffAddedBackground + ColorGreen + prefix + defaultForeground)
+	case diff.Removed:
+		sb.WriteString(diffRemovedBackground + ColorRed + prefix + defaultForeground)
+	default:
+		sb.WriteString(prefix)
+	}
+	for _, t := range h.tokens(text) {
+		if c := syntaxColor(t.Type); c != "" {
+			sb.WriteString(c + t.Value + defaultForeground)
+		} else {
+			sb.WriteString(t.Value)
+		}
+	}
+	if kind == diff.Added || kind == diff.Removed {
+		sb.WriteString(ColorReset)
+	}
+	return sb.String()
+}
+
+// tokens splits text into tokens of the file's language. Each line is
+// tokenized on its own, so constructs spanning lines, such as block
+// comments, are only recognized on their first line.
+func (h *codeHighlighter) tokens(text string) []chroma.Token {
+	plain := []chroma.Token{{Type: chroma.Text, Value: text}}
+	if h.lexer == nil {
+		return plain
+	}
+	it, err := h.lexer.Tokenise(nil, text)
+	if err != nil {
+		return plain
+	}
+	var tokens []chroma.Token
+	for _, t := range it.Tokens() {
+		// Lexers may add a final newline; the caller ends the line itself.
+		if t.Value = strings.TrimSuffix(t.Value, "\n"); t.Value != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// syntaxColor returns the color of a kind of token, or "" to leave it in
+// the default color.
+func syntaxColor(t chroma.TokenType) string {
+	switch {
+	case t.InCategory(chroma.Comment):
+		return ColorGray
+	case t.InCategory(chroma.Keyword):
+		return ColorPurple
+	case t.InSubCategory(chroma.LiteralString):
+		return ColorYellow
+	case t.InSubCategory(chroma.LiteralNumber):
+		return ColorCyan
+	case t == chroma.NameFunction || t == chroma.NameBuiltin:
+		return ColorBlue
+	}
+	return ""
+}
+
+// WriteDiffStat writes a summary of the changed files in the style of
+// "git diff --stat".
+func WriteDiffStat(w io.Writer, stats []diff.Stat) {
+	nameWidth, most := 0, 0
+	for _, s := range stats {
+		nameWidth = max(nameWidth, len(statName(s)))
+		most = max(most, s.Added+s.Removed)
+	}
+	const barWidth = 40
+
+	var added, removed int
+	for _, s := range stats {
+		added += s.Added
+		removed += s.Removed
+		plus, minus := s.Added, s.Removed
+		if most > barWidth {
+			plus = (s.Added*barWidth + most - 1) / most
+			minus = (s.Removed*barWidth + most - 1) / most
+		}
+		fmt.Fprintf(w, " %-*s | %5d %s%s\n", nameWidth, statName(s), s.Added+s.Removed,
+			ColorText(strings.Repeat("+", plus), "green"),
+			ColorText(strings.Repeat("-", minus), "red"))
+	}
+	fmt.Fprintf(w, " %s changed, %s(+), %s(-)\n", plural(len(stats), "file"), plural(added, "insertion"), plural(removed, "deletion"))
+}
+
+// WriteDiffNames writes the path of each changed file.
+func WriteDiffNames(w io.Writer, stats []diff.Stat) {
+	for _, s := range stats {
+		if s.Path != "" {
+			fmt.Fprintln(w, s.Path)
+		} else {
+			fmt.Fprintln(w, s.OldPath)
+		}
+	}
+}
+
+func statName(s diff.Stat) string {
+	switch {
+	case s.Path == "":
+		return s.OldPath
+	case s.OldPath != "" && s.OldPath != s.Path:
+		return s.OldPath + " => " + s.Path
+	}
+	return s.Path
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
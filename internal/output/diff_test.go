@@ -0,0 +1,131 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PhilipKram/bitbucket-cli/internal/diff"
+)
+
+const diffSample = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,5 +1,7 @@ package main
+ package main
+
+-import "fmt"
++import (
++	"fmt"
++)
+
+ func main() {}
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1 @@
++hello
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestWriteDiff(t *testing.T) {
+	// Tests do not run on a terminal, so the text is written uncolored.
+	var sb strings.Builder
+	WriteDiff(&sb, diffSample)
+	if sb.String() != diffSample {
+		t.Errorf("WriteDiff changed the diff:\n%s", sb.String())
+	}
+}
+
+func TestWriteDiffStat(t *testing.T) {
+	stats := []diff.Stat{
+		{Path: "main.go", OldPath: "main.go", Added: 3, Removed: 1},
+		{Path: "new.go", OldPath: "old.go", Added: 1},
+		{OldPath: "gone.txt", Removed: 80},
+	}
+	var sb strings.Builder
+	WriteDiffStat(&sb, stats)
+
+	want := ` main.go          |     4 ++-
+ old.go => new.go |     1 +
+ gone.txt         |    80 ----------------------------------------
+ 3 files changed, 4 insertions(+), 81 deletions(-)
+`
+	if sb.String() != want {
+		t.Errorf("WriteDiffStat output:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestWriteDiffNames(t *testing.T) {
+	var sb strings.Builder
+	WriteDiffNames(&sb, []diff.Stat{{Path: "main.go"}, {OldPath: "gone.txt"}})
+	if want := "main.go\ngone.txt\n"; sb.String() != want {
+		t.Errorf("WriteDiffNames = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestWriteSideBySideDiff(t *testing.T) {
+	var sb strings.Builder
+	WriteSideBySideDiff(&sb, diff.Parse(diffSample), 45)
+	lines := strings.Split(sb.String(), "\n")
+
+	if lines[0] != "main.go" {
+		t.Errorf("first line = %q, want file name", lines[0])
+	}
+	if lines[1] != "@@ -1,5 +1,7 @@ package main" {
+		t.Errorf("second line = %q, want hunk header", lines[1])
+	}
+	// The removed import line is paired with the first added line.
+	if want := `    3 import "fmt"    |     3 import (`; lines[4] != want {
+		t.Errorf("changed line = %q, want %q", lines[4], want)
+	}
+	if !strings.Contains(sb.String(), "docs/new.md (added)") {
+		t.Error("added files should be marked")
+	}
+	if !strings.Contains(sb.String(), "old.txt (deleted)") {
+		t.Error("deleted files should be marked")
+	}
+}
+
+func TestFitText(t *testing.T) {
+	if got := fitText("\tx", 10); got != "    x" {
+		t.Errorf("fitText expanded tabs to %q", got)
+	}
+	if got := fitText("abcdefghij", 5); got != "abcd…" {
+		t.Errorf("fitText = %q, want %q", got, "abcd…")
+	}
+}
+
+func TestWriteDiff_Highlighting(t *testing.T) {
+	var sb strings.Builder
+	writeDiff(&sb, diffSample, true)
+	out := sb.String()
+
+	if got := ansiPattern.ReplaceAllString(out, ""); got != diffSample {
+		t.Errorf("colored diff without the colors differs from the diff:\n%s", got)
+	}
+	for _, want := range []string{
+		ColorBold + "diff --git a/main.go b/main.go" + ColorReset,
+		ColorCyan + "@@ -1,5 +1,7 @@ package main" + ColorReset,
+		// Code is highlighted in context, removed and added lines alike.
+		" " + ColorPurple + "package" + defaultForeground + " main",
+		diffRemovedBackground + ColorRed + "-" + defaultForeground + ColorPurple + "import" + defaultForeground + " " + ColorYellow + `"fmt"` + defaultForeground + ColorReset,
+		diffAddedBackground + ColorGreen + "+" + defaultForeground + ColorPurple + "import" + defaultForeground,
+		// Plain text keeps the default color.
+		diffAddedBackground + ColorGreen + "+" + defaultForeground + "hello" + ColorReset,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("colored diff does not contain %q:\n%q", want, out)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RawData pairs a command's typed data with the API JSON it was decoded from.
+// Field selection (--json id,title,...) reads from the raw JSON so fields the
+// typed structs leave out are still available; all other output uses the
+// typed data.
+type RawData struct {
+	Data interface{}
+	JSON interface{}
+}
+
+// WithRaw wraps typed data with its raw API JSON, typically a
+// json.RawMessage or a []json.RawMessage for lists.
+func WithRaw(data, raw interface{}) RawData {
+	return RawData{Data: data, JSON: raw}
+}
+
+var (
+	selectedFields []string
+	listFields     bool
+)
+
+// SetFields selects the fields printed by Print and Render. A nil slice
+// prints every field. With list set, the available fields are printed
+// instead of the data.
+func SetFields(fields []string, list bool) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	selectedFields = fields
+	listFields = list
+}
+
+// ParseFields splits a comma-separated --json field list.
+func ParseFields(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func fieldSelection() ([]string, bool) {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return selectedFields, listFields
+}
+
+// selectData resolves a RawData wrapper and applies the field selection. It
+// reports whether the result should be printed at all.
+func selectData(w io.Writer, data interface{}) (interface{}, bool, error) {
+	fields, list := fieldSelection()
+	raw, isRaw := data.(RawData)
+	if !list && fields == nil {
+		if isRaw {
+			return raw.Data, true, nil
+		}
+		return data, true, nil
+	}
+
+	source := data
+	if isRaw {
+		source = raw.Data
+		if raw.JSON != nil {
+			source = raw.JSON
+		}
+	}
+	v, err := toGeneric(source)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if list {
+		paths := availableFields(v)
+		if len(paths) == 0 && isRaw {
+			// An empty list has no fields to show; fall back to the typed
+			// element's fields.
+			if zero, err := toGeneric(zeroElement(raw.Data)); err == nil {
+				paths = availableFields(zero)
+			}
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(w, "No fields available: the response has no objects.")
+			return nil, false, nil
+		}
+		fmt.Fprintln(w, "Available fields:")
+		for _, p := range paths {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+		return nil, false, nil
+	}
+
+	if items, ok := v.([]interface{}); ok {
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = pickFields(item, fields)
+		}
+		return out, true, nil
+	}
+	return pickFields(v, fields), true, nil
+}
+
+// pickFields builds an object holding only the given dotted field paths of
+// v. Missing fields are null. Values that are not objects pass through.
+func pickFields(v interface{}, fields []string) interface{} {
+	m, ok := v.(*orderedMap)
+	if !ok {
+		return v
+	}
+	out := &orderedMap{values: map[string]interface{}{}}
+	for _, field := range fields {
+		parts := strings.Split(field, ".")
+		var value interface{} = m
+		for _, p := range parts {
+			obj, ok := value.(*orderedMap)
+			if !ok {
+				value = nil
+				break
+			}
+			value = obj.values[p]
+		}
+
+		dst := out
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := dst.values[p].(*orderedMap)
+			if !ok {
+				next = &orderedMap{values: map[string]interface{}{}}
+				mapSet(dst, p, next)
+			}
+			dst = next
+		}
+		mapSet(dst, parts[len(parts)-1], value)
+	}
+	return out
+}
+
+// mapSet sets key in m, appending it to the key order if it is new.
+func mapSet(m *orderedMap, key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// availableFields lists the dotted paths of every object field in v, taking
+// the union over list elements.
+func availableFields(v interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		m, ok := v.(*orderedMap)
+		if !ok {
+			return
+		}
+		for _, k := range m.keys {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			seen[path] = true
+			walk(path, m.values[k])
+		}
+	}
+	if items, ok := v.([]interface{}); ok {
+		for _, item := range items {
+			walk("", item)
+		}
+	} else {
+		walk("", v)
+	}
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// zeroElement returns the zero value of a slice's element type, or of data
+// itself when it is not a slice.
+func zeroElement(data interface{}) interface{} {
+	t := reflect.TypeOf(data)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+	}
+	// Marshal a zero value of the element type; json.RawMessage zero values
+	// encode as null.
+	zero := reflect.New(t).Elem().Interface()
+	if _, ok := zero.(json.RawMessage); ok {
+		return nil
+	}
+	return zero
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type testPR struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func testPRData() RawData {
+	raw := []json.RawMessage{
+		json.RawMessage(`{"id":1,"title":"Fix","draft":true,"author":{"display_name":"Ann","uuid":"{a}"},"summary":{"html":"x"}}`),
+		json.RawMessage(`{"id":2,"title":"Docs","draft":false,"author":{"display_name":"Bob","uuid":"{b}"}}`),
+	}
+	return WithRaw([]testPR{{1, "Fix"}, {2, "Docs"}}, raw)
+}
+
+func TestPrintData_SelectsRawFields(t *testing.T) {
+	t.Cleanup(func() { SetFields(nil, false) })
+	SetFields(ParseFields("id, draft,author.display_name,summary.html"), false)
+
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatNDJSON, testPRData()); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := `{"id":1,"draft":true,"author":{"display_name":"Ann"},"summary":{"html":"x"}}` + "\n" +
+		`{"id":2,"draft":false,"author":{"display_name":"Bob"},"summary":{"html":null}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("printData() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRender_SelectedFieldsAsCSV(t *testing.T) {
+	t.Cleanup(func() { SetFields(nil, false) })
+	SetFields([]string{"id", "author.display_name"}, false)
+
+	var buf bytes.Buffer
+	if err := render(&buf, FormatCSV, testPRData(), NewTable("ID")); err != nil {
+		t.Fatalf("render() error: %v", err)
+	}
+	want := "id,author.display_name\n1,Ann\n2,Bob\n"
+	if buf.String() != want {
+		t.Errorf("render() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPrintData_RawDataWithoutSelectionUsesTypedData(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatNDJSON, testPRData()); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := `{"id":1,"title":"Fix"}` + "\n" + `{"id":2,"title":"Docs"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("printData() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPrintData_ListFields(t *testing.T) {
+	t.Cleanup(func() { SetFields(nil, false) })
+	SetFields(nil, true)
+
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatJSON, testPRData()); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := "Available fields:\n  author\n  author.display_name\n  author.uuid\n  draft\n  id\n  summary\n  summary.html\n  title\n"
+	if buf.String() != want {
+		t.Errorf("printData() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// An empty list falls back to the typed element's fields.
+	buf.Reset()
+	if err := printData(&buf, FormatJSON, WithRaw([]testPR{}, []json.RawMessage{})); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	if want := "Available fields:\n  id\n  title\n"; buf.String() != want {
+		t.Errorf("printData(empty) = %q, want %q", buf.String(), want)
+	}
+}
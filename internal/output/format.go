@@ -3,6 +3,7 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -15,6 +16,10 @@ const (
 	ColorRed    = "\033[31m"
 	ColorYellow = "\033[33m"
 	ColorGray   = "\033[90m"
+	ColorCyan   = "\033[36m"
+	ColorBlue   = "\033[34m"
+	ColorPurple = "\033[35m"
+	ColorBold   = "\033[1m"
 )
 
 // isTTY checks if stdout is a terminal
@@ -54,7 +59,11 @@ func (t *Table) AddRow(values ...string) {
 
 // Print renders the table to stdout.
 func (t *Table) Print() {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	t.write(os.Stdout)
+}
+
+func (t *Table) write(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	// Print header
 	fmt.Fprintln(w, strings.Join(t.headers, "\t"))
 	// Print separator
@@ -95,7 +104,7 @@ func Truncate(s string, maxLen int) string {
 
 // ColorText wraps text with the specified color.
 // Only applies color if output is a TTY.
-// Supported colors: green, red, yellow, gray
+// Supported colors: green, red, yellow, gray, cyan and bold
 func ColorText(text, color string) string {
 	if !isTTY() {
 		return text
@@ -111,6 +120,10 @@ func ColorText(text, color string) string {
 		colorCode = ColorYellow
 	case "gray":
 		colorCode = ColorGray
+	case "cyan":
+		colorCode = ColorCyan
+	case "bold":
+		colorCode = ColorBold
 	default:
 		return text
 	}
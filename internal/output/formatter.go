@@ -0,0 +1,407 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Format is an output format selectable with the global --format flag.
+type Format string
+
+// Supported output formats.
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// Formats lists the supported output formats.
+func Formats() []string {
+	return []string{
+		string(FormatTable), string(FormatJSON), string(FormatYAML),
+		string(FormatCSV), string(FormatTSV), string(FormatNDJSON),
+	}
+}
+
+// ParseFormat validates a format name. The empty string selects FormatTable.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatTable, nil
+	}
+	for _, f := range Formats() {
+		if strings.EqualFold(s, f) {
+			return Format(f), nil
+		}
+	}
+	return "", fmt.Errorf("invalid format %q: must be one of %s", s, strings.Join(Formats(), ", "))
+}
+
+var (
+	formatMu      sync.RWMutex
+	currentFormat = FormatTable
+
+	currentJQ       *JQQuery
+	currentTemplate *OutputTemplate
+)
+
+// SetFormat selects the format used by Print and Render for the rest of the
+// process. It is called once per command from the root command.
+func SetFormat(f Format) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	if f == "" {
+		f = FormatTable
+	}
+	currentFormat = f
+}
+
+// CurrentFormat returns the selected output format.
+func CurrentFormat() Format {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return currentFormat
+}
+
+// SetJQ filters structured output through a jq expression (see CompileJQ).
+// An empty expression removes the filter.
+func SetJQ(expr string) error {
+	var q *JQQuery
+	if expr != "" {
+		var err error
+		if q, err = CompileJQ(expr); err != nil {
+			return err
+		}
+	}
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	currentJQ = q
+	return nil
+}
+
+// SetTemplate renders structured output with a Go template (see
+// ParseTemplate). An empty template removes it.
+func SetTemplate(text string) error {
+	var t *OutputTemplate
+	if text != "" {
+		var err error
+		if t, err = ParseTemplate(text); err != nil {
+			return err
+		}
+	}
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	currentTemplate = t
+	return nil
+}
+
+// Filtered reports whether a --jq expression or --template is active.
+func Filtered() bool {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return currentJQ != nil || currentTemplate != nil
+}
+
+// IsTable reports whether human-readable output is selected. Commands with
+// free-form output (e.g. 'view' commands) print it only when this is true and
+// hand their data to Print otherwise.
+func IsTable() bool {
+	return CurrentFormat() == FormatTable
+}
+
+// Print writes data in the selected machine-readable format. Lists of objects
+// become one CSV/TSV row per element, with nested fields flattened to dotted
+// column names. With the table format data is printed as JSON.
+func Print(data interface{}) error {
+	return printData(os.Stdout, CurrentFormat(), data)
+}
+
+// Fprint is like Print but writes to w.
+func Fprint(w io.Writer, data interface{}) error {
+	return printData(w, CurrentFormat(), data)
+}
+
+// Render writes a list command's result: the table format prints table, CSV
+// and TSV print the table's columns without colors, and the structured
+// formats encode data.
+func Render(data interface{}, table *Table) error {
+	return render(os.Stdout, CurrentFormat(), data, table)
+}
+
+func render(w io.Writer, format Format, data interface{}, table *Table) error {
+	if fields, list := fieldSelection(); Filtered() || fields != nil || list {
+		return printData(w, format, data)
+	}
+	switch format {
+	case FormatTable, "":
+		table.write(w)
+		return nil
+	case FormatCSV:
+		return writeDelimited(w, ',', table.headers, table.rows)
+	case FormatTSV:
+		return writeDelimited(w, '\t', table.headers, table.rows)
+	}
+	return printData(w, format, data)
+}
+
+func printData(w io.Writer, format Format, data interface{}) error {
+	data, ok, err := selectData(w, data)
+	if err != nil || !ok {
+		return err
+	}
+
+	formatMu.RLock()
+	jq, tmpl := currentJQ, currentTemplate
+	formatMu.RUnlock()
+	if jq != nil {
+		return writeJQ(w, jq, data)
+	}
+	if tmpl != nil {
+		return tmpl.Execute(w, data)
+	}
+
+	switch format {
+	case FormatYAML:
+		v, err := toGeneric(data)
+		if err != nil {
+			return err
+		}
+		return writeYAML(w, v)
+	case FormatNDJSON:
+		v, err := toGeneric(data)
+		if err != nil {
+			return err
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		for _, item := range items {
+			line, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(line))
+		}
+		return nil
+	case FormatCSV, FormatTSV:
+		v, err := toGeneric(data)
+		if err != nil {
+			return err
+		}
+		headers, rows := flattenRows(v)
+		sep := ','
+		if format == FormatTSV {
+			sep = '\t'
+		}
+		return writeDelimited(w, sep, headers, rows)
+	}
+
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("formatting JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(out))
+	return nil
+}
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// writeDelimited writes CSV (sep ',') or TSV (sep '\t'). TSV fields have tabs
+// and newlines replaced by spaces instead of being quoted.
+func writeDelimited(w io.Writer, sep rune, headers []string, rows [][]string) error {
+	clean := func(row []string) []string {
+		out := make([]string, len(row))
+		for i, v := range row {
+			v = ansiPattern.ReplaceAllString(v, "")
+			if sep == '\t' {
+				v = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(v)
+			}
+			out[i] = v
+		}
+		return out
+	}
+
+	if sep == '\t' {
+		fmt.Fprintln(w, strings.Join(clean(headers), "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(clean(row), "\t"))
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(clean(headers)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(clean(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// orderedMap is a JSON object that remembers the order of its keys, so YAML
+// and CSV output list fields in the same order as JSON output.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// toGeneric converts data to JSON-like values (orderedMap, []interface{},
+// string, json.Number, bool, nil) by round-tripping it through JSON.
+func toGeneric(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := &orderedMap{values: map[string]interface{}{}}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyTok.(string)
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := m.values[key]; !dup {
+					m.keys = append(m.keys, key)
+				}
+				m.values[key] = v
+			}
+			_, err := dec.Token()
+			return m, err
+		case '[':
+			list := []interface{}{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			_, err := dec.Token()
+			return list, err
+		}
+	}
+	return tok, nil
+}
+
+// MarshalJSON lets an orderedMap be re-encoded with its key order intact.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// flattenRows turns a value into CSV columns and rows. A list becomes one row
+// per element and anything else a single row. Nested objects are flattened to
+// dotted keys; arrays are kept as compact JSON.
+func flattenRows(v interface{}) ([]string, [][]string) {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+
+	var headers []string
+	seen := map[string]bool{}
+	flat := make([]map[string]string, len(items))
+	for i, item := range items {
+		flat[i] = map[string]string{}
+		flattenInto(flat[i], "", item, func(key string) {
+			if !seen[key] {
+				seen[key] = true
+				headers = append(headers, key)
+			}
+		})
+	}
+
+	rows := make([][]string, len(flat))
+	for i, f := range flat {
+		row := make([]string, len(headers))
+		for j, h := range headers {
+			row[j] = f[h]
+		}
+		rows[i] = row
+	}
+	return headers, rows
+}
+
+func flattenInto(dst map[string]string, prefix string, v interface{}, addKey func(string)) {
+	if m, ok := v.(*orderedMap); ok {
+		for _, k := range m.keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenInto(dst, key, m.values[k], addKey)
+		}
+		return
+	}
+	key := prefix
+	if key == "" {
+		key = "value"
+	}
+	addKey(key)
+	dst[key] = scalarString(v)
+}
+
+// scalarString renders a JSON-like value as a plain string.
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
@@ -0,0 +1,161 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testRepo struct {
+	Name    string `json:"name"`
+	Private bool   `json:"is_private"`
+	Owner   struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Tags []string `json:"tags"`
+}
+
+func testRepos() []testRepo {
+	a := testRepo{Name: "alpha", Private: true, Tags: []string{"go"}}
+	a.Owner.Login = "ann"
+	b := testRepo{Name: "beta, the second"}
+	b.Owner.Login = "bob"
+	return []testRepo{a, b}
+}
+
+func testTable() *Table {
+	table := NewTable("NAME", "PRIVATE")
+	table.AddRow("alpha", ColorGreen+"true"+ColorReset)
+	table.AddRow("beta, the second", "false")
+	return table
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats() {
+		got, err := ParseFormat(f)
+		if err != nil || string(got) != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if got, _ := ParseFormat(""); got != FormatTable {
+		t.Errorf("ParseFormat(\"\") = %q, want table", got)
+	}
+	if got, _ := ParseFormat("JSON"); got != FormatJSON {
+		t.Errorf("ParseFormat(\"JSON\") = %q, want json", got)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") should fail")
+	}
+}
+
+func TestRender_CSVUsesTableColumns(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render(&buf, FormatCSV, testRepos(), testTable()); err != nil {
+		t.Fatalf("render() error: %v", err)
+	}
+	want := "NAME,PRIVATE\nalpha,true\n\"beta, the second\",false\n"
+	if buf.String() != want {
+		t.Errorf("render(csv) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRender_TSV(t *testing.T) {
+	table := NewTable("NAME", "NOTE")
+	table.AddRow("alpha", "two\tcolumns\nand lines")
+	var buf bytes.Buffer
+	if err := render(&buf, FormatTSV, nil, table); err != nil {
+		t.Fatalf("render() error: %v", err)
+	}
+	want := "NAME\tNOTE\nalpha\ttwo columns and lines\n"
+	if buf.String() != want {
+		t.Errorf("render(tsv) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRender_TableFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render(&buf, FormatTable, testRepos(), testTable()); err != nil {
+		t.Fatalf("render() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "NAME") || !strings.Contains(buf.String(), "alpha") {
+		t.Errorf("render(table) = %q", buf.String())
+	}
+}
+
+func TestPrintData_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatNDJSON, testRepos()); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := `{"name":"alpha","is_private":true,"owner":{"login":"ann"},"tags":["go"]}` + "\n" +
+		`{"name":"beta, the second","is_private":false,"owner":{"login":"bob"},"tags":null}` + "\n"
+	if buf.String() != want {
+		t.Errorf("printData(ndjson) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPrintData_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatYAML, testRepos()); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := `- name: alpha
+  is_private: true
+  owner:
+    login: ann
+  tags:
+    - go
+- name: beta, the second
+  is_private: false
+  owner:
+    login: bob
+  tags: null
+`
+	if buf.String() != want {
+		t.Errorf("printData(yaml) =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestYAMLString_Quoting(t *testing.T) {
+	tests := map[string]string{
+		"plain":        "plain",
+		"":             `""`,
+		"true":         `"true"`,
+		"42":           `"42"`,
+		"key: value":   `"key: value"`,
+		"- item":       `"- item"`,
+		"line\nbreak":  `"line\nbreak"`,
+		" padded":      `" padded"`,
+		"feat/x <y>":   "feat/x <y>",
+		"#not-comment": `"#not-comment"`,
+	}
+	for in, want := range tests {
+		if got := yamlString(in); got != want {
+			t.Errorf("yamlString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestPrintData_CSVFlattensObjects(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printData(&buf, FormatCSV, testRepos()[0]); err != nil {
+		t.Fatalf("printData() error: %v", err)
+	}
+	want := "name,is_private,owner.login,tags\nalpha,true,ann,\"[\"\"go\"\"]\"\n"
+	if buf.String() != want {
+		t.Errorf("printData(csv) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSetFormat(t *testing.T) {
+	t.Cleanup(func() { SetFormat(FormatTable) })
+
+	SetFormat(FormatYAML)
+	if CurrentFormat() != FormatYAML || IsTable() {
+		t.Errorf("CurrentFormat() = %q after SetFormat(yaml)", CurrentFormat())
+	}
+	SetFormat("")
+	if !IsTable() {
+		t.Errorf("SetFormat(\"\") should select the table format, got %q", CurrentFormat())
+	}
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+)
+
+// --jq filtering is done with gojq, a Go implementation of the jq language,
+// so scripts do not depend on an external jq binary. It differs from jq in a
+// few documented ways, notably that object keys are always sorted.
+
+// JQQuery is a compiled --jq expression.
+type JQQuery struct {
+	code *gojq.Code
+}
+
+// CompileJQ parses and compiles a jq expression.
+func CompileJQ(expr string) (*JQQuery, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %w", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %w", err)
+	}
+	return &JQQuery{code: code}, nil
+}
+
+// Run evaluates the query against data, which may be any JSON-encodable
+// value, and returns every output.
+func (q *JQQuery) Run(data interface{}) ([]interface{}, error) {
+	input, err := jqInputValue(data)
+	if err != nil {
+		return nil, err
+	}
+	var results []interface{}
+	iter := q.code.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, ok := v.(error); ok {
+			if haltErr, ok := err.(*gojq.HaltError); ok && haltErr.Value() == nil {
+				return results, nil
+			}
+			return nil, fmt.Errorf("jq: %w", err)
+		}
+		results = append(results, v)
+	}
+}
+
+// jqInputValue converts data to the plain maps, slices and numbers gojq
+// works with by round-tripping it through JSON.
+func jqInputValue(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	// gojq turns json.Number into int, big.Int or float64 as jq would.
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+	return v, nil
+}
+
+// writeJQ prints the outputs of q for data like jq -r: strings raw and other
+// values as indented JSON. Numbers are formatted the same way at any depth.
+func writeJQ(w io.Writer, q *JQQuery, data interface{}) error {
+	results, err := q.Run(data)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if s, ok := r.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		out, err := gojq.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("formatting output: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, out, "", "  "); err != nil {
+			return fmt.Errorf("formatting output: %w", err)
+		}
+		fmt.Fprintln(w, buf.String())
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pagerCommand returns the pager to use: BB_PAGER, then PAGER, then less.
+// It returns nil when paging is turned off by setting BB_PAGER to "" or
+// either variable to "cat".
+func pagerCommand() []string {
+	pager, ok := os.LookupEnv("BB_PAGER")
+	if !ok {
+		pager = os.Getenv("PAGER")
+	}
+	if !ok && pager == "" {
+		pager = "less"
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return nil
+	}
+	return fields
+}
+
+// StartPager pipes output through the user's pager when stdout is a
+// terminal. It returns the writer to print to and a function to call once
+// everything is written, which waits for the pager to exit. Without a
+// terminal or a pager that can be started, output goes to stdout.
+func StartPager() (io.Writer, func()) {
+	noop := func() {}
+	if !isTTY() {
+		return os.Stdout, noop
+	}
+	args := pagerCommand()
+	if args == nil {
+		return os.Stdout, noop
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		// Quit when the output fits on one screen and keep colors.
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noop
+	}
+	if err := cmd.Start(); err != nil {
+		return os.Stdout, noop
+	}
+	return w, func() {
+		w.Close()
+		_ = cmd.Wait()
+	}
+}
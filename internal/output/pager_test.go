@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestPagerCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		bbPager *string
+		pager   string
+		want    []string
+	}{
+		{"default", nil, "", []string{"less"}},
+		{"PAGER", nil, "more -s", []string{"more", "-s"}},
+		{"BB_PAGER wins", strPtr("less -R"), "more", []string{"less", "-R"}},
+		{"empty BB_PAGER disables", strPtr(""), "more", nil},
+		{"cat disables", nil, "cat", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pager)
+			if tt.bbPager != nil {
+				t.Setenv("BB_PAGER", *tt.bbPager)
+			} else {
+				// Setenv restores the variable after the test.
+				t.Setenv("BB_PAGER", "")
+				os.Unsetenv("BB_PAGER")
+			}
+			if got := pagerCommand(); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("pagerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,192 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+)
+
+// OutputTemplate is a parsed --template. Templates are executed once against
+// the command's JSON data, so list commands range over the result:
+//
+//	{{range .}}{{tablerow .id .title (timeago .updated_on)}}{{end}}
+type OutputTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses a Go text/template with the output helper functions.
+func ParseTemplate(text string) (*OutputTemplate, error) {
+	t, err := template.New("output").Option("missingkey=zero").Funcs(templateFuncs(nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &OutputTemplate{tmpl: t}, nil
+}
+
+// Execute renders data with the template. Rows added with tablerow are
+// aligned and written when tablerender is called and at the end.
+func (t *OutputTemplate) Execute(w io.Writer, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+
+	var buf bytes.Buffer
+	rows := &templateTable{out: &buf}
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Funcs(templateFuncs(rows)).Execute(&buf, v); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	rows.flush()
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// templateTable collects tablerow rows until they are rendered.
+type templateTable struct {
+	out  io.Writer
+	rows [][]string
+}
+
+func (t *templateTable) flush() {
+	if t == nil || len(t.rows) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(t.out, 0, 0, 2, ' ', 0)
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+	t.rows = nil
+}
+
+func templateFuncs(rows *templateTable) template.FuncMap {
+	return template.FuncMap{
+		"tablerow": func(fields ...interface{}) string {
+			row := make([]string, len(fields))
+			for i, f := range fields {
+				row[i] = templateString(f)
+			}
+			if rows != nil {
+				rows.rows = append(rows.rows, row)
+			}
+			return ""
+		},
+		"tablerender": func() string {
+			rows.flush()
+			return ""
+		},
+		"timeago": func(v interface{}) (string, error) {
+			t, err := templateTime(v)
+			if err != nil {
+				return "", err
+			}
+			return timeAgo(time.Since(t)), nil
+		},
+		"timefmt": func(layout string, v interface{}) (string, error) {
+			t, err := templateTime(v)
+			if err != nil {
+				return "", err
+			}
+			return t.Format(layout), nil
+		},
+		"color": func(color string, v interface{}) string {
+			return ColorText(templateString(v), color)
+		},
+		"truncate": func(length int, v interface{}) string {
+			return Truncate(templateString(v), length)
+		},
+		"join": func(sep string, v interface{}) string {
+			list, _ := v.([]interface{})
+			parts := make([]string, len(list))
+			for i, item := range list {
+				parts[i] = templateString(item)
+			}
+			return strings.Join(parts, sep)
+		},
+		"pluck": func(field string, v interface{}) []interface{} {
+			list, _ := v.([]interface{})
+			out := make([]interface{}, 0, len(list))
+			for _, item := range list {
+				if m, ok := item.(map[string]interface{}); ok {
+					out = append(out, m[field])
+				}
+			}
+			return out
+		},
+		"json": func(v interface{}) (string, error) {
+			out, err := json.Marshal(v)
+			return string(out), err
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// templateString renders a decoded JSON value for template output. Whole
+// numbers print without a decimal point.
+func templateString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1e15 {
+			return fmt.Sprintf("%d", int64(t))
+		}
+		return fmt.Sprint(t)
+	case map[string]interface{}, []interface{}:
+		out, _ := json.Marshal(t)
+		return string(out)
+	}
+	return fmt.Sprint(v)
+}
+
+func templateTime(v interface{}) (time.Time, error) {
+	s := templateString(v)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("no timestamp to format")
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return t, nil
+}
+
+// timeAgo describes a duration in the past, e.g. "3 hours ago".
+func timeAgo(d time.Duration) string {
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", name)
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+	switch {
+	case d < time.Minute:
+		return "less than a minute ago"
+	case d < time.Hour:
+		return unit(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return unit(int(d.Hours()), "hour")
+	case d < 30*24*time.Hour:
+		return unit(int(d.Hours()/24), "day")
+	case d < 365*24*time.Hour:
+		return unit(int(d.Hours()/24/30), "month")
+	}
+	return unit(int(d.Hours()/24/365), "year")
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func executeTemplate(t *testing.T, text string, data interface{}) string {
+	t.Helper()
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		t.Fatalf("ParseTemplate() error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestTemplate_Fields(t *testing.T) {
+	got := executeTemplate(t, `{{range .}}{{.name}} ({{.owner.login}}) {{join "," .tags}}{{"\n"}}{{end}}`, testRepos())
+	want := "alpha (ann) go\nbeta, the second (bob) \n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplate_TableRow(t *testing.T) {
+	data := []map[string]interface{}{
+		{"id": 7, "title": "Short"},
+		{"id": 123, "title": "A longer title"},
+	}
+	got := executeTemplate(t, `{{range .}}{{tablerow .id .title}}{{end}}`, data)
+	want := "7    Short\n123  A longer title\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplate_Helpers(t *testing.T) {
+	created := time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339Nano)
+	data := map[string]interface{}{"created_on": created, "title": "Refactor the parser", "state": "OPEN"}
+	got := executeTemplate(t, `{{timeago .created_on}}|{{truncate 10 .title}}|{{color "green" .state}}|{{lower .state}}`, data)
+	// Tests do not run on a terminal, so color leaves the text unchanged.
+	want := "3 hours ago|Refacto...|OPEN|open"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	if _, err := ParseTemplate("{{.name"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	tests := map[time.Duration]string{
+		10 * time.Second:     "less than a minute ago",
+		time.Minute:          "1 minute ago",
+		5 * time.Hour:        "5 hours ago",
+		49 * time.Hour:       "2 days ago",
+		400 * 24 * time.Hour: "1 year ago",
+	}
+	for d, want := range tests {
+		if got := timeAgo(d); got != want {
+			t.Errorf("timeAgo(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// writeYAML writes a JSON-like value (as produced by toGeneric) as a YAML
+// document. Strings are double-quoted only when a plain scalar would be
+// ambiguous, so the output reads like hand-written YAML.
+func writeYAML(w io.Writer, v interface{}) error {
+	var b strings.Builder
+	switch t := v.(type) {
+	case *orderedMap:
+		if len(t.keys) == 0 {
+			b.WriteString("{}\n")
+		} else {
+			yamlMap(&b, t, 0)
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			b.WriteString("[]\n")
+		} else {
+			yamlList(&b, t, 0)
+		}
+	default:
+		b.WriteString(yamlScalar(v) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func yamlMap(b *strings.Builder, m *orderedMap, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, k := range m.keys {
+		b.WriteString(pad + yamlString(k) + ":")
+		yamlValue(b, m.values[k], indent+2)
+	}
+}
+
+func yamlList(b *strings.Builder, list []interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, item := range list {
+		switch t := item.(type) {
+		case *orderedMap:
+			if len(t.keys) == 0 {
+				b.WriteString(pad + "- {}\n")
+				continue
+			}
+			// Render the object one level deeper, then hang its first
+			// line off the dash.
+			var nested strings.Builder
+			yamlMap(&nested, t, indent+2)
+			b.WriteString(pad + "- " + strings.TrimPrefix(nested.String(), pad+"  "))
+		case []interface{}:
+			if len(t) == 0 {
+				b.WriteString(pad + "- []\n")
+				continue
+			}
+			b.WriteString(pad + "-\n")
+			yamlList(b, t, indent+2)
+		default:
+			b.WriteString(pad + "- " + yamlScalar(item) + "\n")
+		}
+	}
+}
+
+// yamlValue writes the value of a mapping entry whose "key:" has already been
+// written.
+func yamlValue(b *strings.Builder, v interface{}, indent int) {
+	switch t := v.(type) {
+	case *orderedMap:
+		if len(t.keys) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		yamlMap(b, t, indent)
+	case []interface{}:
+		if len(t) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		yamlList(b, t, indent)
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlScalar(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return yamlString(t)
+	case json.Number:
+		return t.String()
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	}
+	return yamlString(scalarString(v))
+}
+
+// yamlString returns s as a plain scalar when that is unambiguous, and as a
+// double-quoted scalar otherwise.
+func yamlString(s string) string {
+	if !yamlNeedsQuotes(s) {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}